@@ -2,12 +2,19 @@ package agent
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
+	"ai_automation/config"
 	"ai_automation/domain/entities"
+	"ai_automation/domain/i18n"
 	"ai_automation/domain/interfaces"
 
 	"github.com/sirupsen/logrus"
@@ -19,102 +26,393 @@ type Agent struct {
 	security      interfaces.SecurityLayer
 	logger        *logrus.Logger
 	maxIterations int
+	loginDetector PageDetector
+	// captchaDetector pauses the task for the user to solve a CAPTCHA, nil disables it
+	captchaDetector PageDetector
+	// captureOnFailure saves a screenshot when an action fails
+	captureOnFailure bool
+	navLimiter       *navigationLimiter
+	// stepTimeout limits how long a single action may run, zero disables it
+	stepTimeout time.Duration
+	out         Presenter
+	// planTasks asks the AI for a subtask plan before the first step
+	planTasks bool
+	// verifyCompletion asks the AI to confirm completion against the page before finishing
+	verifyCompletion bool
+	// jsEnabled allows execute_js actions
+	jsEnabled bool
+	// allowedActions limits which actions may run (ALLOWED_ACTIONS), nil allows all
+	allowedActions entities.ActionSet
+	// dismissBanners clicks away cookie consent banners after navigation
+	dismissBanners bool
+	// taskTimeout caps wall-clock time of tasks without their own deadline, zero disables it
+	taskTimeout time.Duration
+	// dialogPolicy decides how JS dialogs are answered
+	dialogPolicy DialogPolicy
+	// stepMode waits for Enter after every action, slowMo delays every action
+	stepMode bool
+	slowMo   time.Duration
+	// focusWindow raises the browser window before every action (FOCUS_WINDOW)
+	focusWindow bool
+	// progress shows the step counter, nil disables it
+	progress *Progress
+	// maxNoopIterations stops a task whose page stopped changing, zero disables it
+	maxNoopIterations int
+	// maxURLRevisits caps navigations back to visited pages (MAX_URL_REVISITS), zero disables it
+	maxURLRevisits int
+	// screenshotDir overrides ~/.ai_automation/screenshots (SCREENSHOT_DIR)
+	screenshotDir string
+	// approver answers approval requests instead of the reader, nil asks the user
+	approver Approver
+	// observers receive the task lifecycle, see AddObserver
+	observers []Observer
 }
 
+// Approver - decides whether an action that needs approval may run, for callers without a terminal
+type Approver func(ctx context.Context, action *entities.Action) bool
+
 func (a *Agent) GetBrowser() interfaces.BrowserController {
 	return a.browser
 }
 
+func (a *Agent) GetAI() interfaces.AIService {
+	return a.ai
+}
+
 func NewAgent(
 	browser interfaces.BrowserController,
 	ai interfaces.AIService,
 	security interfaces.SecurityLayer,
 	logger *logrus.Logger,
 ) *Agent {
+	cfg := config.FromEnv().Agent
 	return &Agent{
-		browser:       browser,
-		ai:            ai,
-		security:      security,
-		logger:        logger,
-		maxIterations: 100, // Prevent infinite loops
+		browser:           browser,
+		ai:                ai,
+		security:          security,
+		logger:            logger,
+		maxIterations:     100, // Prevent infinite loops
+		loginDetector:     DetectLoginWall,
+		captchaDetector:   captchaDetector(cfg.CaptchaDetection),
+		captureOnFailure:  cfg.CaptureOnFailure,
+		navLimiter:        newNavigationLimiter(cfg.MinNavigateInterval, cfg.MaxNavigationsPerDomain),
+		stepTimeout:       cfg.StepTimeout,
+		out:               NewConsolePresenter(os.Stdout),
+		planTasks:         cfg.TaskPlanning,
+		verifyCompletion:  cfg.VerifyCompletion,
+		jsEnabled:         cfg.JSEnabled,
+		allowedActions:    cfg.AllowedActions,
+		dismissBanners:    cfg.DismissBanners,
+		taskTimeout:       cfg.TaskTimeout,
+		dialogPolicy:      DialogPolicy(cfg.DialogPolicy),
+		stepMode:          cfg.StepMode,
+		slowMo:            cfg.SlowMo,
+		focusWindow:       cfg.FocusWindow,
+		screenshotDir:     cfg.ScreenshotDir,
+		progress:          NewProgress(isTerminal(os.Stdout)),
+		maxNoopIterations: cfg.MaxNoopIterations,
+		maxURLRevisits:    cfg.MaxURLRevisits,
 	}
 }
 
+// SetPresenter overrides where user-facing progress messages are written
+func (a *Agent) SetPresenter(out Presenter) {
+	a.out = out
+}
+
+// SetProgress overrides the step progress indicator; nil disables it
+func (a *Agent) SetProgress(progress *Progress) {
+	a.progress = progress
+}
+
+// SetApprover answers approval requests with approve instead of asking through the reader; nil asks again
+func (a *Agent) SetApprover(approve Approver) {
+	a.approver = approve
+}
+
+// SetLoginDetector overrides the login wall heuristic; nil disables detection
+func (a *Agent) SetLoginDetector(detector PageDetector) {
+	a.loginDetector = detector
+}
+
+// SetCaptchaDetector overrides the CAPTCHA heuristic; nil disables detection
+func (a *Agent) SetCaptchaDetector(detector PageDetector) {
+	a.captchaDetector = detector
+}
+
+// ExecuteTask - runs the task until the AI completes it or it fails, observers are told the outcome
 func (a *Agent) ExecuteTask(ctx context.Context, task *entities.Task, reader *bufio.Reader) error {
-	fmt.Printf("Задача: %s\n", task.Description)
-	fmt.Println("Начинаю работу...")
-	fmt.Println()
+	err := a.executeTask(ctx, task, reader)
+	a.notify(func(o Observer) { o.OnTaskComplete(task, err) })
+	return err
+}
+
+func (a *Agent) executeTask(ctx context.Context, task *entities.Task, reader *bufio.Reader) error {
+	a.out.Println(i18n.T(i18n.MsgTaskHeader, task.Description))
+	a.out.Println(i18n.T(i18n.MsgStartingWork))
+	a.out.Println()
 
 	task.Status = entities.TaskStatusInProgress
 	history := []entities.Action{}
+	startedAt := time.Now()
+	// Progress shows tokens of this task only, the AI service counts for its whole lifetime
+	tokensAtStart := a.tokensUsed()
+
+	// Deadline propagates to every browser and AI call through ctx
+	if task.Deadline.IsZero() && a.taskTimeout > 0 {
+		task.Deadline = time.Now().Add(a.taskTimeout)
+	}
+	if !task.Deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, task.Deadline)
+		defer cancel()
+	}
+
+	if a.planTasks && len(task.Subtasks) == 0 {
+		a.planTask(ctx, task)
+	}
+	loginPausedURL := ""
+	captchaPausedURL := ""
+	elementsPage := 0
+	// scope limits extraction to a container chosen with focus_on
+	scope := ""
+	// missRetries counts re-decisions after a selector matched nothing in the current step
+	missRetries := 0
+	progress := progressTracker{limit: a.maxNoopIterations}
+	// visitedURL is the page the previous step ran on, as recorded in task.Visits
+	visitedURL := ""
+	// rejections counts completions the verification turned down
+	rejections := 0
+	// queued holds further actions the AI returned in the same response, they run one per
+	// iteration on a freshly extracted page until one fails
+	var queued []entities.Action
+
+	if task.StartURL != "" {
+		start, err := a.openStartURL(ctx, task, reader)
+		if err != nil {
+			task.Status = entities.TaskStatusWaiting
+			return err
+		}
+		history = append(history, *start)
+	}
 
 	for iteration := 0; iteration < a.maxIterations; iteration++ {
+		if ctx.Err() != nil {
+			return a.cancelTask(ctx, task)
+		}
+		a.showProgress(iteration, startedAt, tokensAtStart)
+
+		// An open dialog blocks the page, answer it before reading the page
+		a.handleDialog(ctx, task, reader)
+
 		// Extract current page info
-		fmt.Println("Анализирую текущую страницу...")
-		pageInfo, err := a.browser.ExtractPageInfo(ctx)
+		a.out.Println(i18n.T(i18n.MsgAnalyzingPage))
+		pageInfo, err := a.browser.ExtractPageInfoWithin(ctx, scope, elementsPage)
 		if err != nil {
-			fmt.Printf("Ошибка при анализе страницы: %v\n", err)
+			if ctx.Err() != nil {
+				return a.cancelTask(ctx, task)
+			}
+			a.logger.WithError(err).Error("Failed to extract page info")
+			a.out.Println(i18n.T(i18n.MsgPageAnalysisError, err))
 			return fmt.Errorf("failed to extract page info: %w", err)
 		}
+		// The container is gone (e.g. after navigation), go back to the whole page
+		if scope != "" && pageInfo.Scope == "" {
+			a.out.Println(i18n.T(i18n.MsgFocusNotFound, scope))
+			scope = ""
+		}
 
 		if pageInfo.URL != "" && pageInfo.URL != "about:blank" {
-			fmt.Printf("Текущая страница: %s\n", pageInfo.URL)
+			a.out.Println(i18n.T(i18n.MsgCurrentPage, pageInfo.URL))
+		}
+
+		// CAPTCHAs can't be solved by the AI, hand the browser to the user once per page
+		if a.captchaDetector != nil && pageInfo.URL != captchaPausedURL && a.captchaDetector(pageInfo) {
+			captchaPausedURL = pageInfo.URL
+			pageInfo, err = a.waitForUser(ctx, reader, i18n.MsgCaptchaDetected, i18n.MsgCaptchaSolve)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Pause once per page when we land on a login wall
+		if a.loginDetector != nil && pageInfo.URL != loginPausedURL && a.loginDetector(pageInfo) {
+			loginPausedURL = pageInfo.URL
+			pageInfo, err = a.waitForUser(ctx, reader, i18n.MsgLoginDetected, i18n.MsgLoginInstructions)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Arrivals are counted so the AI learns about pages it keeps coming back to
+		visitedURL = recordArrival(task, pageInfo.URL, visitedURL)
+
+		// Successful steps that never change the page are not caught by failure-based checks,
+		// re-decisions after a missed selector belong to the same step
+		if missRetries == 0 && progress.observe(pageInfo) {
+			a.out.Println(i18n.T(i18n.MsgNoProgress, progress.noops))
+			task.Status = entities.TaskStatusNoProgress
+			return fmt.Errorf("no progress: page unchanged for %d steps", progress.noops)
 		}
 
 		// Decide next action - AI will determine if task is complete
-		fmt.Println("Определяю следующее действие...")
-		action, err := a.ai.DecideNextAction(ctx, task, pageInfo, history)
-		if err != nil {
-			fmt.Printf("Ошибка при определении действия: %v\n", err)
-			return fmt.Errorf("failed to decide next action: %w", err)
+		var action *entities.Action
+		if len(queued) > 0 {
+			a.out.Println(i18n.T(i18n.MsgQueuedAction))
+			next := queued[0]
+			queued = queued[1:]
+			action = &next
+		} else {
+			a.out.Println(i18n.T(i18n.MsgDecidingAction))
+			action, err = a.ai.DecideNextAction(ctx, task, pageInfo, history)
+			if err != nil {
+				if ctx.Err() != nil {
+					return a.cancelTask(ctx, task)
+				}
+				a.logger.WithError(err).Error("Failed to decide next action")
+				a.out.Println(i18n.T(i18n.MsgDecisionError, err))
+				return fmt.Errorf("failed to decide next action: %w", err)
+			}
+			if action != nil && len(action.FollowUps) > 0 {
+				queued = append([]entities.Action(nil), action.FollowUps...)
+				action.FollowUps = nil
+			}
 		}
 
-		// If AI returns nil or a "complete" action, task is done
+		// If AI returns nil, there is nothing left to do
 		if action == nil {
 			task.Status = entities.TaskStatusCompleted
 			return nil
 		}
+		a.notify(func(o Observer) { o.OnActionDecided(task, action) })
 
-		// Check if action indicates task completion
-		if action.Type == "complete" || strings.Contains(strings.ToLower(action.Description), "задача выполнена") ||
-			strings.Contains(strings.ToLower(action.Description), "task complete") {
+		// AI explicitly signals completion with a summary of the result
+		if action.Type == entities.ActionComplete {
+			if a.verifyCompletion && rejections < maxCompletionRejections {
+				if complete, reason := a.confirmCompletion(ctx, task, action); !complete {
+					rejections++
+					a.out.Println(i18n.T(i18n.MsgCompletionRejected, reason))
+					a.out.Println()
+					action.Error = "completion rejected: " + reason
+					history = append(history, *action)
+					queued = a.dropQueued(queued)
+					progress.reset()
+					continue
+				}
+			}
 			task.Status = entities.TaskStatusCompleted
+			task.Result = action.Text
+			if task.Result != "" {
+				a.out.Printf("\n%s\n", i18n.T(i18n.MsgTaskSummary, task.Result))
+			}
 			return nil
 		}
 
-		// Check if action requires approval
-		if a.security.RequiresApproval(ctx, action, pageInfo) {
-			action.RequiresApproval = true
-			fmt.Printf("\nВНИМАНИЕ: Требуется подтверждение деструктивного действия!\n")
-			fmt.Printf("Действие: %s\n", getActionDescription(action))
-			fmt.Printf("Описание: %s\n", action.Description)
-			fmt.Println("\nЭто действие может быть необратимым (удаление, оплата и т.д.)")
-			fmt.Print("Введите 'продолжить' или 'подтвердить' для выполнения, или 'отмена' для отмены: ")
-
-			response, _ := reader.ReadString('\n')
-			response = strings.TrimSpace(strings.ToLower(response))
+		// Next page of elements is shown on the next iteration, any other action starts from the first page
+		if action.Type == entities.ActionMoreItems {
+			if pageInfo.HasMore {
+				elementsPage++
+				a.out.Println(i18n.T(i18n.MsgMoreElements, elementsPage+1))
+			}
+			history = append(history, *action)
+			continue
+		}
+		elementsPage = 0
 
-			if response == "продолжить" || response == "подтвердить" || response == "да" || response == "yes" || response == "y" {
-				fmt.Println("Действие подтверждено, продолжаю...")
-				fmt.Println()
+		// Extraction scope only changes what the next steps see
+		if action.Type == entities.ActionFocus {
+			scope = action.Selector
+			if scope == "" {
+				a.out.Println(i18n.T(i18n.MsgFocusCleared))
 			} else {
-				fmt.Println("Действие отменено пользователем")
-				task.Status = entities.TaskStatusWaiting
-				return fmt.Errorf("action cancelled by user")
+				a.out.Println(i18n.T(i18n.MsgFocusSet, scope))
 			}
+			a.out.Println()
+			history = append(history, *action)
+			continue
+		}
+
+		// Plan progress only changes task state, there is nothing to run in the browser
+		if action.Type == entities.ActionSubtaskDone {
+			if subtask := task.CompleteCurrentSubtask(); subtask != nil {
+				a.out.Println(i18n.T(i18n.MsgSubtaskCompleted, subtask.Description))
+				a.out.Println()
+			}
+			progress.reset()
+			history = append(history, *action)
+			continue
+		}
+
+		// Clarification from the user is handled here since it needs the reader
+		if action.Type == entities.ActionAskUser {
+			a.askUser(task, action, reader)
+			progress.reset()
+			history = append(history, *action)
+			continue
+		}
+
+		admitted, err := a.admitAction(ctx, task, action, pageInfo, reader)
+		if err != nil {
+			task.Status = entities.TaskStatusWaiting
+			return err
+		}
+		if !admitted {
+			history = append(history, *action)
+			queued = a.dropQueued(queued)
+			continue
 		}
 
 		// Execute action
-		fmt.Printf("Выполняю действие: %s\n", getActionDescription(action))
-		result := a.executeAction(ctx, action)
+		a.out.Println(i18n.T(i18n.MsgExecutingAction, getActionDescription(action)))
+		a.logger.WithFields(logrus.Fields{
+			"task":      task.ID,
+			"iteration": iteration,
+			"action":    action.Type,
+			"selector":  action.Selector,
+			"url":       action.URL,
+		}).Debug("Executing action")
+		result := a.executeStep(ctx, action)
+		a.notify(func(o Observer) { o.OnActionExecuted(task, action, result) })
+
+		// No further action can succeed once the browser is gone
+		if errors.Is(result.Err, interfaces.ErrBrowserClosed) {
+			a.out.Println(i18n.T(i18n.MsgBrowserClosed))
+			task.Status = entities.TaskStatusFailed
+			return fmt.Errorf("browser closed: %w", result.Err)
+		}
+
+		// A selector that matches nothing is usually a guess, the AI picks again from the
+		// elements on the page within the same step instead of counting a failed one
+		if !result.Success && missRetries < maxMissRetries && a.elementMissing(ctx, action, result) {
+			missRetries++
+			a.out.Println(i18n.T(i18n.MsgElementMissing, action.Selector))
+			action.Error = missingElementFeedback(action.Selector)
+			history = append(history, *action)
+			queued = a.dropQueued(queued)
+			iteration--
+			continue
+		}
+		missRetries = 0
+
+		a.reportOutcome(task, result.Success && !repeatsLastAction(history, action))
+		if !result.Success && a.captureOnFailure {
+			a.captureFailure(ctx, task, iteration, result)
+		}
 
 		// Log result
 		if result.Success {
-			fmt.Printf("%s\n\n", result.Message)
+			a.out.Printf("%s\n\n", result.Message)
+			// Successful steps are kept on the task so they can be exported for replay
+			task.Actions = append(task.Actions, *action)
 		} else {
-			fmt.Printf("Ошибка: %s - %s\n", result.Message, result.Error)
-			fmt.Println("Попробую другой подход...")
-			fmt.Println()
+			a.logger.WithFields(logrus.Fields{
+				"task":      task.ID,
+				"iteration": iteration,
+				"action":    action.Type,
+			}).Warnf("Action failed: %s", result.Error)
+			a.out.Println(i18n.T(i18n.MsgActionError, result.Message, result.Error))
+			a.out.Println(i18n.T(i18n.MsgTryingAnotherWay))
+			a.out.Println()
 
 			// If action failed, we continue - agent should adapt
 			// But we limit consecutive failures
@@ -128,38 +426,330 @@ func (a *Agent) ExecuteTask(ctx context.Context, task *entities.Task, reader *bu
 			}
 		}
 
+		// Returned data and errors go back to the AI with the history
+		action.Result = result.Data
+		if !result.Success {
+			action.Error = result.Error
+			// Later actions of the same response assumed this one worked
+			queued = a.dropQueued(queued)
+		}
+		// Reading values off a page leaves it unchanged, yet every new read is progress
+		if result.Success && result.Data != "" && !repeatsLastAction(history, action) {
+			progress.reset()
+		}
+		if visit := task.Visit(pageInfo.URL); visit != nil {
+			visit.Note = visitNote(action, result)
+		}
+
 		// Add to history
 		history = append(history, *action)
+		a.pauseStep(reader)
 
 		// Wait a bit between actions to allow page to load
-		time.Sleep(1 * time.Second)
+		select {
+		case <-ctx.Done():
+			return a.cancelTask(ctx, task)
+		case <-time.After(1 * time.Second):
+		}
 	}
 
-	fmt.Printf("Достигнуто максимальное количество итераций (%d)\n", a.maxIterations)
+	a.out.Println(i18n.T(i18n.MsgMaxIterations, a.maxIterations))
 	task.Status = entities.TaskStatusFailed
 	return fmt.Errorf("reached maximum iterations (%d)", a.maxIterations)
 }
 
+// errActionCancelled - the user or the Approver turned down an action that needs approval
+var errActionCancelled = errors.New("action cancelled by user")
+
+// admitAction - checks an action before it runs, whether the AI chose it or the task started with it.
+// Actions outside ALLOWED_ACTIONS and navigations past MAX_URL_REVISITS are rejected with action.Error
+// telling the AI why; actions that need approval are confirmed, a refusal returns errActionCancelled
+func (a *Agent) admitAction(ctx context.Context, task *entities.Task, action *entities.Action, pageInfo *entities.PageInfo, reader *bufio.Reader) (bool, error) {
+	// Actions outside ALLOWED_ACTIONS never run, the AI is told to pick another one
+	if !a.allowedActions.Allows(action.Type) {
+		a.out.Println(i18n.T(i18n.MsgActionNotAllowed, action.Type))
+		a.out.Println()
+		action.Error = fmt.Sprintf("action %s is not allowed, allowed actions: %s", action.Type, a.allowedActions)
+		return false, nil
+	}
+
+	// Going back to the same page again and again is a loop, the AI has to find another way
+	if visit := a.revisitLimitReached(task, action); visit != nil {
+		a.out.Println(i18n.T(i18n.MsgRevisitBlocked, visit.URL, visit.Count))
+		a.out.Println()
+		action.Error = fmt.Sprintf("%s was already visited %d times, use what was found there or try another page", visit.URL, visit.Count)
+		return false, nil
+	}
+
+	if a.security.RequiresApproval(ctx, action, pageInfo) {
+		action.RequiresApproval = true
+		a.notify(func(o Observer) { o.OnApprovalRequired(task, action) })
+		if !a.confirmAction(ctx, action, reader) {
+			return false, errActionCancelled
+		}
+	}
+	return true, nil
+}
+
+// dropQueued - discards actions queued from the same AI response once one of them did not go
+// as planned, the AI decides again with the failure in history
+func (a *Agent) dropQueued(queued []entities.Action) []entities.Action {
+	if len(queued) > 0 {
+		a.logger.Infof("Skipping %d queued actions after an unsuccessful step", len(queued))
+	}
+	return nil
+}
+
+// confirmAction - shows the action to the user and reads their approval, or asks the approver when one is set
+func (a *Agent) confirmAction(ctx context.Context, action *entities.Action, reader *bufio.Reader) bool {
+	a.out.Printf("\n%s\n", i18n.T(i18n.MsgApprovalRequired))
+	a.out.Println(i18n.T(i18n.MsgApprovalAction, getActionDescription(action)))
+	a.out.Println(i18n.T(i18n.MsgApprovalDescription, action.Description))
+
+	var approved bool
+	if a.approver != nil {
+		approved = a.approver(ctx, action)
+	} else {
+		a.out.Printf("\n%s\n", i18n.T(i18n.MsgApprovalWarning))
+		a.out.Print(i18n.T(i18n.MsgApprovalPrompt))
+
+		response, _ := reader.ReadString('\n')
+		approved = isApprovalResponse(strings.TrimSpace(strings.ToLower(response)))
+	}
+
+	if !approved {
+		a.out.Println(i18n.T(i18n.MsgActionRejected))
+		return false
+	}
+	a.out.Println(i18n.T(i18n.MsgActionApproved))
+	a.out.Println()
+	return true
+}
+
+// showProgress - prints the step counter with tokens spent on the task since tokensAtStart
+// (when the AI service reports them)
+func (a *Agent) showProgress(iteration int, startedAt time.Time, tokensAtStart int) {
+	if a.progress == nil {
+		return
+	}
+	tokens := -1
+	if used := a.tokensUsed(); used >= 0 && tokensAtStart >= 0 {
+		tokens = used - tokensAtStart
+	}
+	a.out.Println(a.progress.Line(iteration+1, a.maxIterations, tokens, time.Since(startedAt)))
+}
+
+// tokensUsed - returns tokens the AI service has spent so far, -1 if it does not count them
+func (a *Agent) tokensUsed() int {
+	if counter, ok := a.ai.(interface{ TokensUsed() int }); ok {
+		return counter.TokensUsed()
+	}
+	return -1
+}
+
+// reportOutcome - lets AI services that adapt to progress (e.g. escalate to a stronger model) know
+// whether the step moved the task forward
+func (a *Agent) reportOutcome(task *entities.Task, success bool) {
+	if reporter, ok := a.ai.(interface {
+		ReportOutcome(task *entities.Task, success bool)
+	}); ok {
+		reporter.ReportOutcome(task, success)
+	}
+}
+
+// repeatsLastAction - reports a step identical to the previous one, which usually means no progress
+func repeatsLastAction(history []entities.Action, action *entities.Action) bool {
+	if len(history) == 0 {
+		return false
+	}
+	last := history[len(history)-1]
+	return last.Type == action.Type && last.Selector == action.Selector && last.URL == action.URL && last.Text == action.Text
+}
+
+// planTask - asks AI to split the task into subtasks; planning is best effort,
+// on failure the agent works without a plan
+func (a *Agent) planTask(ctx context.Context, task *entities.Task) {
+	steps, err := a.ai.PlanTask(ctx, task)
+	if err != nil {
+		a.logger.Warnf("Failed to plan task, continuing without a plan: %v", err)
+		return
+	}
+	if len(steps) == 0 {
+		return
+	}
+
+	task.Subtasks = make([]entities.Subtask, 0, len(steps))
+	a.out.Println(i18n.T(i18n.MsgTaskPlan))
+	for i, step := range steps {
+		task.Subtasks = append(task.Subtasks, entities.Subtask{Description: step})
+		a.out.Println(i18n.T(i18n.MsgTaskPlanStep, i+1, step))
+	}
+	a.out.Println()
+}
+
+// askUser - prints AI question, reads the answer and stores it in task context for next prompts
+func (a *Agent) askUser(task *entities.Task, action *entities.Action, reader *bufio.Reader) {
+	a.out.Printf("\n%s\n", i18n.T(i18n.MsgAskUserQuestion, action.Text))
+	a.out.Print(i18n.T(i18n.MsgAskUserPrompt))
+
+	answer, err := reader.ReadString('\n')
+	answer = strings.TrimSpace(answer)
+	if err != nil {
+		a.logger.Warnf("Failed to read user answer: %v", err)
+	}
+	a.out.Println()
+
+	if answer == "" {
+		answer = "(no answer, proceed with your best judgement)"
+	}
+
+	addContext(task, fmt.Sprintf("Q: %s\nA: %s", action.Text, answer))
+}
+
+// addContext - appends an entry to the task context shown to the AI
+func addContext(task *entities.Task, entry string) {
+	if task.Context == "" {
+		task.Context = entry
+	} else {
+		task.Context += "\n" + entry
+	}
+}
+
+// cancelTask - marks task as cancelled after context cancellation, or as timed out when its deadline passed
+func (a *Agent) cancelTask(ctx context.Context, task *entities.Task) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		a.out.Printf("\n%s\n", i18n.T(i18n.MsgTaskTimedOut))
+		task.Status = entities.TaskStatusTimedOut
+		return fmt.Errorf("task timed out: deadline %s exceeded", task.Deadline.Format(time.TimeOnly))
+	}
+
+	a.out.Printf("\n%s\n", i18n.T(i18n.MsgTaskInterrupted))
+	task.Status = entities.TaskStatusCancelled
+	return fmt.Errorf("task cancelled: %w", ctx.Err())
+}
+
+// waitForUser - asks user to finish a manual step in the browser (log in, solve a CAPTCHA)
+// and re-reads the page afterwards
+func (a *Agent) waitForUser(ctx context.Context, reader *bufio.Reader, detected, instructions i18n.MessageID) (*entities.PageInfo, error) {
+	a.out.Printf("\n%s\n", i18n.T(detected))
+	a.out.Println(i18n.T(instructions))
+	a.out.Print(i18n.T(i18n.MsgPressEnter))
+
+	if _, err := reader.ReadString('\n'); err != nil {
+		return nil, fmt.Errorf("failed to wait for user: %w", err)
+	}
+	a.out.Println()
+
+	pageInfo, err := a.browser.ExtractPageInfo(ctx, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract page info: %w", err)
+	}
+	return pageInfo, nil
+}
+
+// formatJSON - pretty-prints JSON for terminal output, returns input unchanged if it is not valid JSON
+func formatJSON(data string) string {
+	var out bytes.Buffer
+	if err := json.Indent(&out, []byte(data), "", "  "); err != nil {
+		return data
+	}
+	return out.String()
+}
+
+// isApprovalResponse - checks whether user confirmed the action (accepts both languages)
+func isApprovalResponse(response string) bool {
+	switch response {
+	case "продолжить", "подтвердить", "да", "continue", "confirm", "yes", "y":
+		return true
+	}
+	return false
+}
+
 // getActionDescription - returns human-readable description of action
 func getActionDescription(action *entities.Action) string {
 	switch action.Type {
 	case entities.ActionNavigate:
-		return fmt.Sprintf("Переход на страницу: %s", action.URL)
+		if action.NewTab {
+			return i18n.T(i18n.MsgActionNewTab, action.URL)
+		}
+		return i18n.T(i18n.MsgActionNavigate, action.URL)
 	case entities.ActionClick:
-		return fmt.Sprintf("Клик на элемент: %s", action.Selector)
+		return i18n.T(i18n.MsgActionClick, action.Selector)
 	case entities.ActionTypeText:
-		return fmt.Sprintf("Ввод текста '%s' в поле: %s", action.Text, action.Selector)
+		return i18n.T(i18n.MsgActionType, action.Text, action.Selector)
+	case entities.ActionSetDate:
+		return i18n.T(i18n.MsgActionSetDate, action.Text, action.Selector)
+	case entities.ActionSetChecked:
+		if action.Uncheck {
+			return i18n.T(i18n.MsgActionUncheck, action.Selector)
+		}
+		return i18n.T(i18n.MsgActionCheck, action.Selector)
+	case entities.ActionSelect:
+		return i18n.T(i18n.MsgActionSelect, strings.Join(action.Values, ", "), action.Selector)
+	case entities.ActionGetOptions:
+		return i18n.T(i18n.MsgActionGetOptions, action.Selector)
 	case entities.ActionScroll:
-		return "Прокрутка страницы"
+		return i18n.T(i18n.MsgActionScroll)
 	case entities.ActionExtract:
-		return "Извлечение информации со страницы"
+		return i18n.T(i18n.MsgActionExtract)
 	case entities.ActionWait:
-		return "Ожидание"
+		return i18n.T(i18n.MsgActionWait)
+	case entities.ActionCopy:
+		return i18n.T(i18n.MsgActionCopy)
+	case entities.ActionPaste:
+		return i18n.T(i18n.MsgActionPaste, action.Selector)
+	case entities.ActionReadElement:
+		return i18n.T(i18n.MsgActionReadElement, action.Selector)
+	case entities.ActionRightClick:
+		return i18n.T(i18n.MsgActionRightClick, action.Selector)
+	case entities.ActionCount:
+		return i18n.T(i18n.MsgActionCount, action.Selector)
+	case entities.ActionClickAt:
+		return i18n.T(i18n.MsgActionClickAt, action.X, action.Y)
+	case entities.ActionWaitLoad:
+		return i18n.T(i18n.MsgActionWaitLoad)
+	case entities.ActionFindByRole:
+		return i18n.T(i18n.MsgActionFindByRole, action.Role, action.Text)
+	case entities.ActionFindNear:
+		return i18n.T(i18n.MsgActionFindNear, action.Role, action.Text)
+	case entities.ActionExecuteJS:
+		return i18n.T(i18n.MsgActionExecuteJS, action.Text)
+	case entities.ActionDownload:
+		return i18n.T(i18n.MsgActionDownload, action.Selector)
+	case entities.ActionScreenshot:
+		return i18n.T(i18n.MsgActionScreenshot)
+	case entities.ActionSavePDF:
+		return i18n.T(i18n.MsgActionSavePDF)
+	case entities.ActionCloseTab:
+		return i18n.T(i18n.MsgActionCloseTab)
+	case entities.ActionFocus:
+		return i18n.T(i18n.MsgActionFocus, action.Selector)
+	case entities.ActionPrevTab:
+		return i18n.T(i18n.MsgActionPrevTab)
+	case entities.ActionSelectText:
+		return i18n.T(i18n.MsgActionSelectText, action.Selector)
+	case entities.ActionGetSelected:
+		return i18n.T(i18n.MsgActionSelection)
+	case entities.ActionLoadAll:
+		return i18n.T(i18n.MsgActionLoadAll)
+	case entities.ActionWaitText:
+		return i18n.T(i18n.MsgActionWaitText, action.Text, action.Selector)
+	case entities.ActionWaitStable:
+		return i18n.T(i18n.MsgActionWaitStable, action.Selector)
 	default:
 		return string(action.Type)
 	}
 }
 
+// switchToOpenTab - reuses a tab already showing url instead of opening it twice
+func (a *Agent) switchToOpenTab(ctx context.Context, url string) bool {
+	switched, err := a.browser.SwitchToTab(ctx, url)
+	if err != nil {
+		a.logger.Warnf("Failed to look for an open tab with %s: %v", url, err)
+	}
+	return switched
+}
+
 func (a *Agent) executeAction(ctx context.Context, action *entities.Action) *entities.ActionResult {
 	result := &entities.ActionResult{
 		Success: false,
@@ -171,27 +761,101 @@ func (a *Agent) executeAction(ctx context.Context, action *entities.Action) *ent
 			result.Error = "URL is required for navigate action"
 			return result
 		}
-		err := a.browser.Navigate(ctx, action.URL)
+		if action.NewTab && a.switchToOpenTab(ctx, action.URL) {
+			result.Success = true
+			result.Message = i18n.T(i18n.MsgTabReused, action.URL)
+			break
+		}
+		if err := a.navLimiter.Wait(ctx, action.URL); err != nil {
+			result.Fail(err)
+			return result
+		}
+		var err error
+		if action.NewTab {
+			err = a.browser.OpenNewTab(ctx, action.URL)
+		} else {
+			err = a.browser.Navigate(ctx, action.URL)
+		}
 		if err != nil {
-			result.Error = err.Error()
+			result.Fail(err)
 			return result
 		}
+		if err := a.browser.WaitForNavigation(ctx, pageLoadTimeout); err != nil {
+			a.logger.Warnf("Page did not finish loading: %v", err)
+		}
+		a.dismissConsentBanner(ctx)
 		result.Success = true
-		result.Message = fmt.Sprintf("Успешно перешел на страницу: %s", action.URL)
+		result.Message = i18n.T(i18n.MsgNavigateSuccess, action.URL)
+		if action.NewTab {
+			result.Message = i18n.T(i18n.MsgNewTabSuccess, action.URL)
+		}
 
 	case entities.ActionClick:
 		if action.Selector == "" {
 			result.Error = "Selector is required for click action"
 			return result
 		}
-		err := a.browser.Click(ctx, action.Selector)
+		beforeURL, _ := a.browser.GetCurrentURL(ctx)
+		// An approved click must hit exactly the approved element, others may use the closest match
+		clicked := action.Selector
+		var err error
+		if action.RequiresApproval {
+			err = a.browser.Click(ctx, action.Selector)
+		} else {
+			clicked, err = a.browser.ClickClosest(ctx, action.Selector)
+		}
 		if err != nil {
-			result.Error = err.Error()
+			result.Fail(err)
 			result.Message = fmt.Sprintf("Failed to click on %s", action.Selector)
 			return result
 		}
+		a.waitAfterClick(ctx, beforeURL)
+		result.Success = true
+		result.Message = i18n.T(i18n.MsgClickSuccess, action.Selector)
+		if clicked != action.Selector {
+			result.Message = i18n.T(i18n.MsgClickSubstituted, action.Selector, clicked)
+			result.Data = result.Message
+		}
+
+	case entities.ActionRightClick:
+		if action.Selector == "" {
+			result.Error = "Selector is required for right_click action"
+			return result
+		}
+		err := a.browser.RightClick(ctx, action.Selector)
+		if err != nil {
+			result.Fail(err)
+			result.Message = fmt.Sprintf("Failed to right-click on %s", action.Selector)
+			return result
+		}
 		result.Success = true
-		result.Message = fmt.Sprintf("Успешно кликнул на элемент: %s", action.Selector)
+		result.Message = i18n.T(i18n.MsgRightClickSuccess, action.Selector)
+
+	case entities.ActionClickAt:
+		if action.X < 0 || action.Y < 0 {
+			result.Error = "Coordinates must not be negative for click_at action"
+			return result
+		}
+		beforeURL, _ := a.browser.GetCurrentURL(ctx)
+		err := a.browser.ClickAt(ctx, action.X, action.Y)
+		if err != nil {
+			result.Fail(err)
+			result.Message = fmt.Sprintf("Failed to click at (%d, %d)", action.X, action.Y)
+			return result
+		}
+		a.waitAfterClick(ctx, beforeURL)
+		result.Success = true
+		result.Message = i18n.T(i18n.MsgClickAtSuccess, action.X, action.Y)
+
+	case entities.ActionWaitLoad:
+		if err := a.browser.WaitForNavigation(ctx, pageLoadTimeout); err != nil {
+			result.Fail(err)
+			result.Message = "Page did not finish loading"
+			return result
+		}
+		currentURL, _ := a.browser.GetCurrentURL(ctx)
+		result.Success = true
+		result.Message = i18n.T(i18n.MsgWaitLoadSuccess, currentURL)
 
 	case entities.ActionTypeText:
 		if action.Selector == "" {
@@ -202,45 +866,404 @@ func (a *Agent) executeAction(ctx context.Context, action *entities.Action) *ent
 			result.Error = "Text is required for type action"
 			return result
 		}
-		err := a.browser.TypeText(ctx, action.Selector, action.Text)
+		var err error
+		if action.Append {
+			err = a.browser.AppendText(ctx, action.Selector, action.Text)
+		} else {
+			err = a.browser.TypeText(ctx, action.Selector, action.Text)
+		}
 		if err != nil {
-			result.Error = err.Error()
+			result.Fail(err)
 			result.Message = fmt.Sprintf("Failed to type text into %s", action.Selector)
 			return result
 		}
 		result.Success = true
-		result.Message = fmt.Sprintf("Успешно ввел текст в поле: %s", action.Selector)
+		result.Message = i18n.T(i18n.MsgTypeSuccess, action.Selector)
+
+	case entities.ActionSetDate:
+		if action.Selector == "" || action.Text == "" {
+			result.Error = "Selector and date are required for set_date action"
+			return result
+		}
+		if err := a.browser.SetDate(ctx, action.Selector, action.Text); err != nil {
+			result.Fail(err)
+			result.Message = fmt.Sprintf("Failed to set date in %s", action.Selector)
+			return result
+		}
+		result.Success = true
+		result.Message = i18n.T(i18n.MsgSetDateSuccess, action.Text, action.Selector)
+
+	case entities.ActionSetChecked:
+		if action.Selector == "" {
+			result.Error = "Selector is required for set_checked action"
+			return result
+		}
+		if err := a.browser.SetChecked(ctx, action.Selector, !action.Uncheck); err != nil {
+			result.Fail(err)
+			result.Message = fmt.Sprintf("Failed to set checkbox %s", action.Selector)
+			return result
+		}
+		result.Success = true
+		if action.Uncheck {
+			result.Message = i18n.T(i18n.MsgUncheckSuccess, action.Selector)
+		} else {
+			result.Message = i18n.T(i18n.MsgCheckSuccess, action.Selector)
+		}
+
+	case entities.ActionSelect:
+		if action.Selector == "" || len(action.Values) == 0 {
+			result.Error = "Selector and values are required for select_option action"
+			return result
+		}
+		if err := a.browser.SelectOption(ctx, action.Selector, action.Values); err != nil {
+			result.Fail(err)
+			result.Message = fmt.Sprintf("Failed to select options in %s", action.Selector)
+			return result
+		}
+		result.Success = true
+		result.Message = i18n.T(i18n.MsgSelectSuccess, action.Selector, strings.Join(action.Values, ", "))
+
+	case entities.ActionGetOptions:
+		if action.Selector == "" {
+			result.Error = "Selector is required for get_select_options action"
+			return result
+		}
+		options, err := a.browser.GetSelectOptions(ctx, action.Selector)
+		if err != nil {
+			result.Fail(err)
+			result.Message = fmt.Sprintf("Failed to get options of %s", action.Selector)
+			return result
+		}
+		encoded, _ := json.Marshal(options)
+		result.Success = true
+		result.Message = i18n.T(i18n.MsgOptionsSuccess, action.Selector, len(options), string(encoded))
+		result.Data = string(encoded)
 
 	case entities.ActionScroll:
-		direction := "down"
-		amount := 500
+		direction := strings.ToLower(strings.TrimSpace(action.Direction))
+		if direction == "" {
+			direction = "down"
+		}
+		if direction != "down" && direction != "up" {
+			result.Error = fmt.Sprintf("Unknown scroll direction %q, use down or up", action.Direction)
+			return result
+		}
+		amount := defaultScrollAmount
+		if action.Amount > 0 {
+			amount = action.Amount
+		}
 		err := a.browser.Scroll(ctx, direction, amount)
 		if err != nil {
-			result.Error = err.Error()
+			result.Fail(err)
 			return result
 		}
 		result.Success = true
-		result.Message = "Успешно прокрутил страницу"
+		result.Message = i18n.T(i18n.MsgScrollSuccess)
 
 	case entities.ActionExtract:
-		pageInfo, err := a.browser.ExtractPageInfo(ctx)
+		pageInfo, err := a.browser.ExtractPageInfo(ctx, 0)
 		if err != nil {
-			result.Error = err.Error()
+			result.Fail(err)
 			return result
 		}
 		result.Success = true
-		result.Message = "Успешно извлек информацию со страницы"
+		result.Message = i18n.T(i18n.MsgExtractSuccess)
+		result.PageInfo = pageInfo
+
+	case entities.ActionLoadAll:
+		loads, err := a.browser.LoadAll(ctx, loadAllMaxScrolls)
+		if err != nil {
+			result.Fail(err)
+			result.Message = "Failed to load the whole list"
+			return result
+		}
+		pageInfo, err := a.browser.ExtractPageInfo(ctx, 0)
+		if err != nil {
+			result.Fail(err)
+			return result
+		}
+		result.Success = true
+		result.Message = i18n.T(i18n.MsgLoadAllSuccess, loads, len(pageInfo.Elements), len(pageInfo.Links), len(pageInfo.Buttons))
+		result.Data = result.Message
 		result.PageInfo = pageInfo
 
 	case entities.ActionWait:
-		timeout := 3
+		timeout := defaultWaitSeconds
+		if action.Timeout > 0 {
+			timeout = min(action.Timeout, maxWaitSeconds)
+		}
 		err := a.browser.Wait(ctx, "", timeout)
 		if err != nil {
-			result.Error = err.Error()
+			result.Fail(err)
+			return result
+		}
+		result.Success = true
+		result.Message = i18n.T(i18n.MsgWaitSuccess, timeout)
+
+	case entities.ActionCopy:
+		if action.Text == "" {
+			result.Error = "Text is required for copy action"
+			return result
+		}
+		err := a.browser.WriteClipboard(ctx, action.Text)
+		if err != nil {
+			result.Fail(err)
+			result.Message = "Failed to copy text to clipboard"
+			return result
+		}
+		result.Success = true
+		result.Message = i18n.T(i18n.MsgCopySuccess)
+
+	case entities.ActionPaste:
+		if action.Selector == "" {
+			result.Error = "Selector is required for paste action"
+			return result
+		}
+		text, err := a.browser.ReadClipboard(ctx)
+		if err != nil {
+			result.Fail(err)
+			result.Message = "Failed to read clipboard"
+			return result
+		}
+		if err := a.browser.TypeText(ctx, action.Selector, text); err != nil {
+			result.Fail(err)
+			result.Message = fmt.Sprintf("Failed to paste text into %s", action.Selector)
+			return result
+		}
+		result.Success = true
+		result.Message = i18n.T(i18n.MsgPasteSuccess, action.Selector)
+		result.Data = text
+
+	case entities.ActionExtractData:
+		if action.Text == "" {
+			result.Error = "Fields are required for extract_data action"
+			return result
+		}
+		pageInfo, err := a.browser.ExtractPageInfo(ctx, 0)
+		if err != nil {
+			result.Fail(err)
+			return result
+		}
+		data, err := a.ai.ExtractData(ctx, pageInfo, action.Text)
+		if err != nil {
+			result.Fail(err)
+			result.Message = "Failed to extract structured data"
+			return result
+		}
+		result.Success = true
+		result.Message = i18n.T(i18n.MsgExtractDataSuccess, formatJSON(data))
+		result.Data = data
+		result.PageInfo = pageInfo
+		return result
+
+	case entities.ActionCount:
+		if action.Selector == "" {
+			result.Error = "Selector is required for count_elements action"
+			return result
+		}
+		count, err := a.browser.CountElements(ctx, action.Selector)
+		if err != nil {
+			result.Fail(err)
+			result.Message = fmt.Sprintf("Failed to count elements %s", action.Selector)
+			return result
+		}
+		result.Success = true
+		result.Message = i18n.T(i18n.MsgCountSuccess, action.Selector, count)
+		result.Data = strconv.Itoa(count)
+
+	case entities.ActionFindByRole:
+		if action.Role == "" {
+			result.Error = "Role is required for find_by_role action"
+			return result
+		}
+		elements, err := a.browser.FindElementsByRole(ctx, action.Role, action.Text)
+		if err != nil {
+			result.Fail(err)
+			result.Message = fmt.Sprintf("Failed to find elements with role %s", action.Role)
+			return result
+		}
+		lines := make([]string, 0, len(elements))
+		for _, elem := range elements {
+			lines = append(lines, fmt.Sprintf("  - %q (selector: %s)", elem.AccessibleName, elem.Selector))
+		}
+		result.Success = true
+		result.Message = i18n.T(i18n.MsgFindByRoleSuccess, action.Role, len(elements), strings.Join(lines, "\n"))
+		result.Data = strings.Join(lines, "\n")
+
+	case entities.ActionFindNear:
+		if action.Role == "" || action.Text == "" {
+			result.Error = "Text and role are required for find_near_text action"
+			return result
+		}
+		element, err := a.browser.FindElementNearText(ctx, action.Text, action.Role)
+		if err != nil {
+			result.Fail(err)
+			result.Message = fmt.Sprintf("Failed to find %s near %q", action.Role, action.Text)
+			return result
+		}
+		name := element.AccessibleName
+		if name == "" {
+			name = element.Text
+		}
+		result.Success = true
+		result.Message = i18n.T(i18n.MsgFindNearSuccess, action.Text, name, element.Selector)
+		result.Data = element.Selector
+
+	case entities.ActionWaitText:
+		if action.Selector == "" || action.Text == "" {
+			result.Error = "Selector and text are required for wait_for_text action"
+			return result
+		}
+		if err := a.browser.WaitForTextContains(ctx, action.Selector, action.Text, contentWaitTimeout); err != nil {
+			result.Fail(err)
+			result.Message = fmt.Sprintf("Failed to wait for text in %s", action.Selector)
+			return result
+		}
+		result.Success = true
+		result.Message = i18n.T(i18n.MsgWaitTextSuccess, action.Text, action.Selector)
+
+	case entities.ActionWaitStable:
+		if action.Selector == "" {
+			result.Error = "Selector is required for wait_for_stable action"
+			return result
+		}
+		if err := a.browser.WaitForElementStable(ctx, action.Selector, contentWaitTimeout); err != nil {
+			result.Fail(err)
+			result.Message = fmt.Sprintf("Failed to wait for %s to settle", action.Selector)
+			return result
+		}
+		result.Success = true
+		result.Message = i18n.T(i18n.MsgWaitStableSuccess, action.Selector)
+
+	case entities.ActionCloseTab:
+		if err := a.browser.CloseCurrentTab(ctx); err != nil {
+			result.Fail(err)
+			result.Message = "Failed to close tab"
+			return result
+		}
+		currentURL, _ := a.browser.GetCurrentURL(ctx)
+		result.Success = true
+		result.Message = i18n.T(i18n.MsgTabClosed, currentURL)
+
+	case entities.ActionPrevTab:
+		if err := a.browser.SwitchToPreviousTab(ctx); err != nil {
+			result.Fail(err)
+			result.Message = "Failed to switch to previous tab"
+			return result
+		}
+		currentURL, _ := a.browser.GetCurrentURL(ctx)
+		result.Success = true
+		result.Message = i18n.T(i18n.MsgTabSwitched, currentURL)
+
+	case entities.ActionScreenshot:
+		// Optional file name is carried in Text
+		path, err := a.saveScreenshot(ctx, action.Text)
+		if err != nil {
+			result.Fail(err)
+			result.Message = "Failed to take screenshot"
+			return result
+		}
+		result.Success = true
+		result.Message = i18n.T(i18n.MsgScreenshotSaved, path)
+		result.Data = path
+
+	case entities.ActionSavePDF:
+		// Optional file name is carried in Text
+		path, err := a.savePDF(ctx, action.Text)
+		if err != nil {
+			result.Fail(err)
+			result.Message = "Failed to save page as PDF"
+			return result
+		}
+		result.Success = true
+		result.Message = i18n.T(i18n.MsgPDFSaved, path)
+		result.Data = path
+
+	case entities.ActionDownload:
+		if action.Selector == "" {
+			result.Error = "Selector is required for download_file action"
+			return result
+		}
+		path, err := a.browser.DownloadFile(ctx, action.Selector)
+		if err != nil {
+			result.Fail(err)
+			result.Message = fmt.Sprintf("Failed to download file via %s", action.Selector)
+			return result
+		}
+		result.Success = true
+		result.Message = i18n.T(i18n.MsgDownloadSuccess, path)
+		result.Data = path
+
+	case entities.ActionExecuteJS:
+		if !a.jsEnabled {
+			result.Error = "execute_js is disabled, set ENABLE_JS_ACTION=true to allow it"
+			return result
+		}
+		if strings.TrimSpace(action.Text) == "" {
+			result.Error = "Script is required for execute_js action"
+			return result
+		}
+		value, err := a.browser.ExecuteScript(ctx, action.Text)
+		if err != nil {
+			result.Fail(err)
+			result.Message = "Failed to execute script"
+			return result
+		}
+		result.Success = true
+		result.Message = i18n.T(i18n.MsgExecuteJSSuccess, value)
+		result.Data = value
+
+	case entities.ActionReadElement:
+		if action.Selector == "" {
+			result.Error = "Selector is required for read_element action"
+			return result
+		}
+		var value string
+		var err error
+		if action.Attribute != "" {
+			value, err = a.browser.GetAttribute(ctx, action.Selector, action.Attribute)
+		} else {
+			value, err = a.browser.GetElementText(ctx, action.Selector)
+		}
+		if err != nil {
+			result.Fail(err)
+			result.Message = fmt.Sprintf("Failed to read element %s", action.Selector)
+			return result
+		}
+		result.Success = true
+		result.Message = i18n.T(i18n.MsgReadElementSuccess, action.Selector, value)
+		result.Data = value
+
+	case entities.ActionSelectText:
+		if action.Selector == "" {
+			result.Error = "Selector is required for select_text action"
+			return result
+		}
+		if err := a.browser.SelectText(ctx, action.Selector); err != nil {
+			result.Fail(err)
+			result.Message = fmt.Sprintf("Failed to select text of %s", action.Selector)
+			return result
+		}
+		selected, err := a.browser.GetSelectedText(ctx)
+		if err != nil {
+			result.Fail(err)
+			result.Message = "Failed to read selection"
+			return result
+		}
+		result.Success = true
+		result.Message = i18n.T(i18n.MsgSelectTextSuccess, action.Selector, selected)
+		result.Data = selected
+
+	case entities.ActionGetSelected:
+		selected, err := a.browser.GetSelectedText(ctx)
+		if err != nil {
+			result.Fail(err)
+			result.Message = "Failed to read selection"
 			return result
 		}
 		result.Success = true
-		result.Message = fmt.Sprintf("Ожидание %d секунд завершено", timeout)
+		result.Message = i18n.T(i18n.MsgSelectionSuccess, selected)
+		result.Data = selected
 
 	default:
 		result.Error = fmt.Sprintf("Unknown action type: %s", action.Type)
@@ -248,7 +1271,7 @@ func (a *Agent) executeAction(ctx context.Context, action *entities.Action) *ent
 	}
 
 	// Get updated page info after action
-	pageInfo, err := a.browser.ExtractPageInfo(ctx)
+	pageInfo, err := a.browser.ExtractPageInfo(ctx, 0)
 	if err == nil {
 		result.PageInfo = pageInfo
 	}
@@ -0,0 +1,744 @@
+package agent_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"ai_automation/domain/entities"
+	"ai_automation/domain/i18n"
+	"ai_automation/domain/interfaces"
+	"ai_automation/testing/mocks"
+)
+
+func TestApprovalRejectedStopsTask(t *testing.T) {
+	security := mocks.NewSecurity()
+	security.Approval[entities.ActionClick] = true
+	browser := mocks.NewBrowser()
+	ai := mocks.NewAI(&entities.Action{Type: entities.ActionClick, Selector: "#pay", Description: "pay"})
+	ag, _ := newTestAgent(browser, ai, security)
+
+	task := &entities.Task{ID: "task", Description: "pay the bill"}
+	if err := ag.ExecuteTask(context.Background(), task, input("no")); err == nil {
+		t.Fatal("ExecuteTask succeeded after the user rejected the action")
+	}
+	if task.Status != entities.TaskStatusWaiting {
+		t.Errorf("status = %s, want %s", task.Status, entities.TaskStatusWaiting)
+	}
+	if n := browser.CallCount("Click") + browser.CallCount("ClickClosest"); n != 0 {
+		t.Errorf("rejected click was executed %d times", n)
+	}
+}
+
+func TestApprovedActionRunsExactly(t *testing.T) {
+	security := mocks.NewSecurity()
+	security.Approval[entities.ActionClick] = true
+	browser := mocks.NewBrowser()
+	ai := mocks.NewAI(&entities.Action{Type: entities.ActionClick, Selector: "#pay", Description: "pay"})
+	ag, _ := newTestAgent(browser, ai, security)
+
+	task := &entities.Task{ID: "task", Description: "pay the bill"}
+	if err := ag.ExecuteTask(context.Background(), task, input("yes")); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+	if calls := browser.CallsTo("Click"); len(calls) != 1 || calls[0].Args[0] != "#pay" {
+		t.Errorf("Click calls = %v, want one click on #pay", calls)
+	}
+	if browser.CallCount("ClickClosest") != 0 {
+		t.Error("approved click fell back to a fuzzy match")
+	}
+	if len(task.Actions) != 1 || !task.Actions[0].RequiresApproval {
+		t.Errorf("recorded actions = %+v, want the approved click", task.Actions)
+	}
+}
+
+func TestCompleteSetsResult(t *testing.T) {
+	ai := mocks.NewAI(&entities.Action{Type: entities.ActionComplete, Text: "price is 42", Description: "done"})
+	ag, out := newTestAgent(mocks.NewBrowser(), ai, mocks.NewSecurity())
+
+	task := &entities.Task{ID: "task", Description: "find the price"}
+	if err := ag.ExecuteTask(context.Background(), task, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+	if task.Status != entities.TaskStatusCompleted {
+		t.Errorf("status = %s, want %s", task.Status, entities.TaskStatusCompleted)
+	}
+	if task.Result != "price is 42" {
+		t.Errorf("result = %q, want %q", task.Result, "price is 42")
+	}
+	if !strings.Contains(out.String(), "price is 42") {
+		t.Errorf("summary not shown to the user:\n%s", out.String())
+	}
+}
+
+func TestAskUserAddsAnswerToContext(t *testing.T) {
+	ai := mocks.NewAI(&entities.Action{Type: entities.ActionAskUser, Text: "Which color?", Description: "ask"})
+	ag, _ := newTestAgent(mocks.NewBrowser(), ai, mocks.NewSecurity())
+
+	task := &entities.Task{ID: "task", Description: "buy a shirt"}
+	if err := ag.ExecuteTask(context.Background(), task, input("blue")); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+	if !strings.Contains(task.Context, "Q: Which color?\nA: blue") {
+		t.Errorf("context = %q, want the question and answer", task.Context)
+	}
+	if n := ai.CallCount("DecideNextAction"); n != 2 {
+		t.Errorf("DecideNextAction called %d times, want 2", n)
+	}
+}
+
+func TestTaskDeadlineTimesOut(t *testing.T) {
+	actions := make([]*entities.Action, 10)
+	for i := range actions {
+		actions[i] = &entities.Action{Type: entities.ActionScroll, Text: "down", Description: "scroll"}
+	}
+	ai := mocks.NewAI(actions...)
+	ag, _ := newTestAgent(mocks.NewBrowser(), ai, mocks.NewSecurity())
+
+	task := &entities.Task{ID: "task", Description: "scroll forever", Deadline: time.Now().Add(1500 * time.Millisecond)}
+	if err := ag.ExecuteTask(context.Background(), task, input()); err == nil {
+		t.Fatal("ExecuteTask succeeded past its deadline")
+	}
+	if task.Status != entities.TaskStatusTimedOut {
+		t.Errorf("status = %s, want %s", task.Status, entities.TaskStatusTimedOut)
+	}
+	if ai.Remaining() == 0 {
+		t.Error("all scripted actions ran despite the deadline")
+	}
+}
+
+func TestCancelledContextStopsTask(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ai := mocks.NewAI(&entities.Action{Type: entities.ActionNavigate, URL: "https://example.com", Description: "open"})
+	browser := mocks.NewBrowser()
+	ag, _ := newTestAgent(browser, ai, mocks.NewSecurity())
+
+	task := &entities.Task{ID: "task", Description: "open site"}
+	if err := ag.ExecuteTask(ctx, task, input()); err == nil {
+		t.Fatal("ExecuteTask succeeded with a cancelled context")
+	}
+	if task.Status != entities.TaskStatusCancelled {
+		t.Errorf("status = %s, want %s", task.Status, entities.TaskStatusCancelled)
+	}
+	if browser.CallCount("Navigate") != 0 {
+		t.Error("browser was used after cancellation")
+	}
+}
+
+func TestMissingElementIsRetriedWithinStep(t *testing.T) {
+	browser := mocks.NewBrowser()
+	browser.Missing["#buy-now"] = true
+	ai := mocks.NewAI(
+		&entities.Action{Type: entities.ActionClick, Selector: "#buy-now", Description: "buy"},
+		&entities.Action{Type: entities.ActionClick, Selector: "#buy", Description: "buy"},
+	)
+	ag, _ := newTestAgent(browser, ai, mocks.NewSecurity())
+
+	task := &entities.Task{ID: "task", Description: "buy the item"}
+	if err := ag.ExecuteTask(context.Background(), task, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+	if len(task.Actions) != 1 || task.Actions[0].Selector != "#buy" {
+		t.Errorf("successful actions = %+v, want the click on #buy", task.Actions)
+	}
+
+	// The second decision sees the miss with feedback
+	calls := ai.CallsTo("DecideNextAction")
+	if len(calls) < 2 {
+		t.Fatalf("DecideNextAction called %d times, want a retry", len(calls))
+	}
+	history := calls[1].Args[2].([]entities.Action)
+	if len(history) != 1 || history[0].Selector != "#buy-now" || !strings.Contains(history[0].Error, "#buy-now") {
+		t.Errorf("history for the retry = %+v, want the missed click with feedback", history)
+	}
+}
+
+func TestMissingElementRetriesAreCapped(t *testing.T) {
+	browser := mocks.NewBrowser()
+	browser.Missing["#ghost"] = true
+	actions := make([]*entities.Action, 4)
+	for i := range actions {
+		actions[i] = &entities.Action{Type: entities.ActionClick, Selector: "#ghost", Description: "click ghost"}
+	}
+	ai := mocks.NewAI(actions...)
+	ag, out := newTestAgent(browser, ai, mocks.NewSecurity())
+
+	task := &entities.Task{ID: "task", Description: "click the ghost"}
+	if err := ag.ExecuteTask(context.Background(), task, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+	// Two inline retries, the third miss is a failed step, the fourth starts a new step's retries
+	if n := strings.Count(out.String(), i18n.T(i18n.MsgTryingAnotherWay)); n != 1 {
+		t.Errorf("failed steps reported %d times, want 1", n)
+	}
+	if got := ai.CallCount("DecideNextAction"); got != 5 {
+		t.Errorf("DecideNextAction called %d times, want 5", got)
+	}
+	if len(task.Actions) != 0 {
+		t.Errorf("missed clicks recorded as successful: %+v", task.Actions)
+	}
+}
+
+func TestDisallowedActionIsRejected(t *testing.T) {
+	t.Setenv("ALLOWED_ACTIONS", "navigate,extract")
+
+	browser := mocks.NewBrowser()
+	ai := mocks.NewAI(&entities.Action{Type: entities.ActionClick, Selector: "#buy", Description: "buy"})
+	ag, out := newTestAgent(browser, ai, mocks.NewSecurity())
+
+	task := &entities.Task{ID: "task", Description: "read the page"}
+	if err := ag.ExecuteTask(context.Background(), task, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+	if n := browser.CallCount("Click") + browser.CallCount("ClickClosest"); n != 0 {
+		t.Errorf("disallowed click was executed %d times", n)
+	}
+	if !strings.Contains(out.String(), i18n.T(i18n.MsgActionNotAllowed, entities.ActionClick)) {
+		t.Errorf("rejection not reported:\n%s", out.String())
+	}
+
+	calls := ai.CallsTo("DecideNextAction")
+	history := calls[len(calls)-1].Args[2].([]entities.Action)
+	if len(history) != 1 || !strings.Contains(history[0].Error, "not allowed") {
+		t.Errorf("history = %+v, want the rejected click with an error", history)
+	}
+}
+
+func TestConsentBannerDismissedAfterNavigation(t *testing.T) {
+	t.Setenv("AUTO_DISMISS_BANNERS", "true")
+
+	browser := mocks.NewBrowser()
+	browser.ConsentBanner = "Accept all"
+	ai := mocks.NewAI(&entities.Action{Type: entities.ActionNavigate, URL: "https://example.com", Description: "open site"})
+	ag, out := newTestAgent(browser, ai, mocks.NewSecurity())
+
+	task := &entities.Task{ID: "task", Description: "open site"}
+	if err := ag.ExecuteTask(context.Background(), task, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+	if browser.ConsentBanner != "" {
+		t.Error("consent banner is still on the page")
+	}
+	if !strings.Contains(out.String(), "Accept all") {
+		t.Errorf("dismissal not reported:\n%s", out.String())
+	}
+}
+
+func TestConsentBannerKeptWhenDisabled(t *testing.T) {
+	t.Setenv("AUTO_DISMISS_BANNERS", "")
+
+	browser := mocks.NewBrowser()
+	browser.ConsentBanner = "Accept all"
+	ai := mocks.NewAI(&entities.Action{Type: entities.ActionNavigate, URL: "https://example.com", Description: "open site"})
+	ag, _ := newTestAgent(browser, ai, mocks.NewSecurity())
+
+	if err := ag.ExecuteTask(context.Background(), &entities.Task{ID: "task", Description: "open site"}, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+	if browser.CallCount("DismissConsentBanner") != 0 {
+		t.Error("banner was dismissed although AUTO_DISMISS_BANNERS is off")
+	}
+}
+
+func TestNoProgressAbortsTask(t *testing.T) {
+	t.Setenv("MAX_NOOP_ITERATIONS", "2")
+
+	actions := make([]*entities.Action, 10)
+	for i := range actions {
+		actions[i] = &entities.Action{Type: entities.ActionScroll, Text: "down", Description: "scroll"}
+	}
+	ai := mocks.NewAI(actions...)
+	// The mock page never changes, whatever the agent does
+	ag, out := newTestAgent(mocks.NewBrowser(), ai, mocks.NewSecurity())
+
+	task := &entities.Task{ID: "task", Description: "find the footer"}
+	if err := ag.ExecuteTask(context.Background(), task, input()); err == nil {
+		t.Fatal("ExecuteTask succeeded without any progress")
+	}
+	if task.Status != entities.TaskStatusNoProgress {
+		t.Errorf("status = %s, want %s", task.Status, entities.TaskStatusNoProgress)
+	}
+	if n := ai.CallCount("DecideNextAction"); n != 2 {
+		t.Errorf("DecideNextAction called %d times, want 2 before the abort", n)
+	}
+	if !strings.Contains(out.String(), i18n.T(i18n.MsgNoProgress, 2)) {
+		t.Errorf("abort not reported:\n%s", out.String())
+	}
+}
+
+func TestReadsFromOnePageAreProgress(t *testing.T) {
+	browser := mocks.NewBrowser()
+	var actions []*entities.Action
+	for i := 1; i <= 6; i++ {
+		selector := fmt.Sprintf("#price-%d", i)
+		browser.ElementText[selector] = fmt.Sprintf("%d EUR", i*10)
+		actions = append(actions, &entities.Action{Type: entities.ActionReadElement, Selector: selector, Description: "read a price"})
+	}
+	actions = append(actions, &entities.Action{Type: entities.ActionComplete, Text: "10-60 EUR"})
+	// The mock page never changes, six reads are more than MAX_NOOP_ITERATIONS allows by default
+	ag, _ := newTestAgent(browser, mocks.NewAI(actions...), mocks.NewSecurity())
+
+	task := &entities.Task{ID: "task", Description: "read all prices"}
+	if err := ag.ExecuteTask(context.Background(), task, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+	if task.Status != entities.TaskStatusCompleted || task.Result != "10-60 EUR" {
+		t.Errorf("task = %s %q, want it completed after all reads", task.Status, task.Result)
+	}
+}
+
+func TestTypeAppendKeepsExistingText(t *testing.T) {
+	browser := mocks.NewBrowser()
+	browser.ElementText["#search"] = "red shoes"
+	browser.ElementText["#name"] = "old name"
+	ai := mocks.NewAI(
+		&entities.Action{Type: entities.ActionTypeText, Selector: "#search", Text: " size 42", Append: true, Description: "refine search"},
+		&entities.Action{Type: entities.ActionTypeText, Selector: "#name", Text: "new name", Description: "rename"},
+	)
+	ag, _ := newTestAgent(browser, ai, mocks.NewSecurity())
+
+	if err := ag.ExecuteTask(context.Background(), &entities.Task{ID: "task", Description: "fill the form"}, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+	if got := browser.ElementText["#search"]; got != "red shoes size 42" {
+		t.Errorf("appended field = %q, want %q", got, "red shoes size 42")
+	}
+	if got := browser.ElementText["#name"]; got != "new name" {
+		t.Errorf("cleared field = %q, want %q", got, "new name")
+	}
+}
+
+func TestSetDateFillsDateField(t *testing.T) {
+	browser := mocks.NewBrowser()
+	ai := mocks.NewAI(
+		&entities.Action{Type: entities.ActionSetDate, Selector: "#check-in", Text: "2024-07-01", Description: "arrival day"},
+	)
+	ag, _ := newTestAgent(browser, ai, mocks.NewSecurity())
+
+	if err := ag.ExecuteTask(context.Background(), &entities.Task{ID: "task", Description: "book a room"}, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+	if got := browser.ElementText["#check-in"]; got != "2024-07-01" {
+		t.Errorf("date field = %q, want 2024-07-01", got)
+	}
+	if n := browser.CallCount("TypeText"); n != 0 {
+		t.Errorf("TypeText called %d times, want the date set with SetDate", n)
+	}
+}
+
+func TestSetCheckedOnlyTogglesWhenNeeded(t *testing.T) {
+	browser := mocks.NewBrowser()
+	browser.Checked["#terms"] = true
+	browser.Checked["#newsletter"] = true
+	ai := mocks.NewAI(
+		&entities.Action{Type: entities.ActionSetChecked, Selector: "#terms", Description: "accept the terms"},
+		&entities.Action{Type: entities.ActionSetChecked, Selector: "#newsletter", Uncheck: true, Description: "no newsletter"},
+	)
+	ag, _ := newTestAgent(browser, ai, mocks.NewSecurity())
+
+	if err := ag.ExecuteTask(context.Background(), &entities.Task{ID: "task", Description: "sign up"}, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+	if !browser.Checked["#terms"] || browser.Checked["#newsletter"] {
+		t.Errorf("checked = %v, want terms checked and newsletter unchecked", browser.Checked)
+	}
+	// The terms box was already checked, only the newsletter one changed
+	if browser.Toggles != 1 {
+		t.Errorf("toggled %d checkboxes, want 1", browser.Toggles)
+	}
+	if calls := browser.CallsTo("SetChecked"); len(calls) != 2 || calls[0].Args[1] != true || calls[1].Args[1] != false {
+		t.Errorf("SetChecked calls = %v", calls)
+	}
+}
+
+func TestSelectOptionsOfMultiSelect(t *testing.T) {
+	browser := mocks.NewBrowser()
+	browser.SelectOptions["#toppings"] = []string{"Cheese", "Ham", "Olives"}
+	ai := mocks.NewAI(
+		&entities.Action{Type: entities.ActionGetOptions, Selector: "#toppings", Description: "see the toppings"},
+		&entities.Action{Type: entities.ActionSelect, Selector: "#toppings", Values: []string{"Cheese", "Olives"}, Description: "pick toppings"},
+		&entities.Action{Type: entities.ActionSelect, Selector: "#toppings", Values: []string{"Pineapple"}, Description: "pick a missing topping"},
+	)
+	ag, _ := newTestAgent(browser, ai, mocks.NewSecurity())
+
+	if err := ag.ExecuteTask(context.Background(), &entities.Task{ID: "task", Description: "order a pizza"}, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+	if got := browser.Selected["#toppings"]; !reflect.DeepEqual(got, []string{"Cheese", "Olives"}) {
+		t.Errorf("selected = %v, want Cheese and Olives", got)
+	}
+
+	calls := ai.CallsTo("DecideNextAction")
+	history := calls[len(calls)-1].Args[2].([]entities.Action)
+	if len(history) != 3 || history[0].Result != `["Cheese","Ham","Olives"]` {
+		t.Fatalf("history = %+v, want get_select_options returning the options as JSON", history)
+	}
+	if history[2].Error == "" {
+		t.Errorf("selecting an unknown option = %+v, want an error", history[2])
+	}
+}
+
+func TestBrowserClosedStopsTask(t *testing.T) {
+	browser := mocks.NewBrowser()
+	closed := &interfaces.BrowserError{Kind: interfaces.ErrBrowserClosed, Message: "browser closed: invalid session id"}
+	browser.Errors["Click"] = closed
+	browser.Errors["ClickClosest"] = closed
+	ai := mocks.NewAI(
+		&entities.Action{Type: entities.ActionClick, Selector: "#buy", Description: "buy"},
+		&entities.Action{Type: entities.ActionClick, Selector: "#buy", Description: "buy again"},
+	)
+	ag, out := newTestAgent(browser, ai, mocks.NewSecurity())
+
+	task := &entities.Task{ID: "task", Description: "buy the item"}
+	err := ag.ExecuteTask(context.Background(), task, input())
+	if !errors.Is(err, interfaces.ErrBrowserClosed) {
+		t.Fatalf("ExecuteTask error = %v, want ErrBrowserClosed", err)
+	}
+	if task.Status != entities.TaskStatusFailed {
+		t.Errorf("status = %s, want %s", task.Status, entities.TaskStatusFailed)
+	}
+	if n := ai.CallCount("DecideNextAction"); n != 1 {
+		t.Errorf("DecideNextAction called %d times, want no decision after the browser closed", n)
+	}
+	if !strings.Contains(out.String(), i18n.T(i18n.MsgBrowserClosed)) {
+		t.Errorf("closed browser not reported:\n%s", out.String())
+	}
+}
+
+func TestTimeoutIsNotRetriedAsMissingElement(t *testing.T) {
+	browser := mocks.NewBrowser()
+	browser.Missing["#results"] = true
+	browser.Errors["WaitForTextContains"] = &interfaces.BrowserError{Kind: interfaces.ErrTimeout, Message: "text did not appear"}
+	ai := mocks.NewAI(&entities.Action{Type: entities.ActionWaitText, Selector: "#results", Text: "Done", Description: "wait"})
+	ag, out := newTestAgent(browser, ai, mocks.NewSecurity())
+
+	if err := ag.ExecuteTask(context.Background(), &entities.Task{ID: "task", Description: "wait for results"}, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+	if browser.CallCount("ElementExists") != 0 {
+		t.Error("a timed out wait was probed as a missing element")
+	}
+	if strings.Contains(out.String(), i18n.T(i18n.MsgElementMissing, "#results")) {
+		t.Errorf("timeout reported as a missing element:\n%s", out.String())
+	}
+}
+
+func TestFindNearTextReturnsSelector(t *testing.T) {
+	browser := mocks.NewBrowser()
+	browser.NearText = map[string]entities.PageElement{
+		"iPhone 15": {TagName: "button", Text: "Buy", AccessibleName: "Buy", Selector: "#products > li:nth-of-type(2) > button"},
+	}
+	ai := mocks.NewAI(
+		&entities.Action{Type: entities.ActionFindNear, Text: "iPhone 15", Role: "button", Description: "find the Buy button"},
+	)
+	ag, _ := newTestAgent(browser, ai, mocks.NewSecurity())
+
+	if err := ag.ExecuteTask(context.Background(), &entities.Task{ID: "task", Description: "buy an iPhone 15"}, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+
+	// The selector goes to the AI as the result, so it can click exactly that button
+	calls := ai.CallsTo("DecideNextAction")
+	history := calls[len(calls)-1].Args[2].([]entities.Action)
+	if len(history) != 1 || history[0].Result != "#products > li:nth-of-type(2) > button" {
+		t.Errorf("history = %+v, want find_near_text returning the row's button", history)
+	}
+}
+
+func TestFollowUpActionsRunInOrder(t *testing.T) {
+	browser := mocks.NewBrowser()
+	ai := mocks.NewAI(&entities.Action{
+		Type: entities.ActionTypeText, Selector: "#q", Text: "go jobs", Description: "enter the query",
+		FollowUps: []entities.Action{{Type: entities.ActionClick, Selector: "#search", Description: "run the search"}},
+	})
+	ag, _ := newTestAgent(browser, ai, mocks.NewSecurity())
+
+	if err := ag.ExecuteTask(context.Background(), &entities.Task{ID: "task", Description: "search for Go jobs"}, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+	if got := browser.ElementText["#q"]; got != "go jobs" {
+		t.Errorf("query field = %q, want go jobs", got)
+	}
+	if calls := browser.CallsTo("ClickClosest"); len(calls) != 1 || calls[0].Args[0] != "#search" {
+		t.Errorf("ClickClosest calls = %+v, want the follow-up click", calls)
+	}
+	// The follow-up ran without asking the AI again, the second decision completes the task
+	if n := ai.CallCount("DecideNextAction"); n != 2 {
+		t.Errorf("DecideNextAction called %d times, want 2", n)
+	}
+}
+
+func TestFollowUpActionsDroppedAfterFailure(t *testing.T) {
+	browser := mocks.NewBrowser()
+	browser.Errors["TypeText"] = errors.New("field is read-only")
+	ai := mocks.NewAI(&entities.Action{
+		Type: entities.ActionTypeText, Selector: "#q", Text: "go jobs", Description: "enter the query",
+		FollowUps: []entities.Action{{Type: entities.ActionClick, Selector: "#search", Description: "run the search"}},
+	})
+	ag, _ := newTestAgent(browser, ai, mocks.NewSecurity())
+
+	if err := ag.ExecuteTask(context.Background(), &entities.Task{ID: "task", Description: "search for Go jobs"}, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+	if n := browser.CallCount("ClickClosest"); n != 0 {
+		t.Errorf("follow-up click ran %d times after the typing failed", n)
+	}
+}
+
+func TestSelectTextThenReadSelection(t *testing.T) {
+	browser := mocks.NewBrowser()
+	browser.ElementText["#quote"] = "To be or not to be"
+	ai := mocks.NewAI(
+		&entities.Action{Type: entities.ActionSelectText, Selector: "#quote", Description: "select the quote"},
+		&entities.Action{Type: entities.ActionGetSelected, Description: "read the quote"},
+	)
+	ag, _ := newTestAgent(browser, ai, mocks.NewSecurity())
+
+	task := &entities.Task{ID: "task", Description: "copy the quote"}
+	if err := ag.ExecuteTask(context.Background(), task, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+	if browser.CallCount("SelectText") != 1 {
+		t.Errorf("SelectText called %d times, want 1", browser.CallCount("SelectText"))
+	}
+
+	// The selection read back goes to the AI as the result of get_selection
+	calls := ai.CallsTo("DecideNextAction")
+	history := calls[len(calls)-1].Args[2].([]entities.Action)
+	if len(history) != 2 || history[1].Type != entities.ActionGetSelected || history[1].Result != "To be or not to be" {
+		t.Errorf("history = %+v, want get_selection returning the quote", history)
+	}
+}
+
+func TestNavigateInNewTabKeepsCurrentTab(t *testing.T) {
+	browser := mocks.NewBrowser()
+	ai := mocks.NewAI(
+		&entities.Action{Type: entities.ActionNavigate, URL: "https://shop.example.com", Description: "open shop"},
+		&entities.Action{Type: entities.ActionNavigate, URL: "https://prices.example.com", NewTab: true, Description: "compare prices"},
+	)
+	ag, _ := newTestAgent(browser, ai, mocks.NewSecurity())
+
+	if err := ag.ExecuteTask(context.Background(), &entities.Task{ID: "task", Description: "compare prices"}, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+	if browser.Tabs != 2 {
+		t.Errorf("open tabs = %d, want 2", browser.Tabs)
+	}
+	if n := browser.CallCount("Navigate"); n != 1 {
+		t.Errorf("Navigate called %d times, want only the first URL in the current tab", n)
+	}
+	calls := browser.CallsTo("OpenNewTab")
+	if len(calls) != 1 || calls[0].Args[0] != "https://prices.example.com" {
+		t.Errorf("OpenNewTab calls = %+v, want the comparison URL", calls)
+	}
+}
+
+func TestNavigateInNewTabReusesOpenTab(t *testing.T) {
+	browser := mocks.NewBrowser()
+	ai := mocks.NewAI(
+		&entities.Action{Type: entities.ActionNavigate, URL: "https://prices.example.com", NewTab: true, Description: "compare prices"},
+		&entities.Action{Type: entities.ActionPrevTab, Description: "back"},
+		&entities.Action{Type: entities.ActionNavigate, URL: "https://prices.example.com", NewTab: true, Description: "compare again"},
+	)
+	ag, _ := newTestAgent(browser, ai, mocks.NewSecurity())
+
+	if err := ag.ExecuteTask(context.Background(), &entities.Task{ID: "task", Description: "compare prices"}, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+	if browser.Tabs != 2 {
+		t.Errorf("open tabs = %d, want 2", browser.Tabs)
+	}
+	if n := browser.CallCount("OpenNewTab"); n != 1 {
+		t.Errorf("OpenNewTab called %d times, want the second open to switch to the tab", n)
+	}
+	if n := browser.CallCount("SwitchToTab"); n != 2 {
+		t.Errorf("SwitchToTab called %d times, want before each new tab", n)
+	}
+}
+
+func TestFocusWindowBringsBrowserToFront(t *testing.T) {
+	steps := func() []*entities.Action {
+		return []*entities.Action{
+			{Type: entities.ActionNavigate, URL: "https://shop.example.com", Description: "open shop"},
+			{Type: entities.ActionScroll, Description: "look around"},
+		}
+	}
+
+	t.Setenv("FOCUS_WINDOW", "")
+	browser := mocks.NewBrowser()
+	ag, _ := newTestAgent(browser, mocks.NewAI(steps()...), mocks.NewSecurity())
+	if err := ag.ExecuteTask(context.Background(), &entities.Task{ID: "task", Description: "browse"}, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+	if n := browser.CallCount("BringToFront"); n != 0 {
+		t.Errorf("BringToFront called %d times with FOCUS_WINDOW off", n)
+	}
+
+	t.Setenv("FOCUS_WINDOW", "true")
+	browser = mocks.NewBrowser()
+	ag, _ = newTestAgent(browser, mocks.NewAI(steps()...), mocks.NewSecurity())
+	if err := ag.ExecuteTask(context.Background(), &entities.Task{ID: "task", Description: "browse"}, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+	if n := browser.CallCount("BringToFront"); n != 2 {
+		t.Errorf("BringToFront called %d times, want once per action", n)
+	}
+}
+
+func TestStartURLOpenedBeforeFirstDecision(t *testing.T) {
+	browser := mocks.NewBrowser()
+	ai := mocks.NewAI()
+	ag, _ := newTestAgent(browser, ai, mocks.NewSecurity())
+
+	task := &entities.Task{ID: "task", Description: "search for Go jobs", StartURL: "https://jobs.example.com"}
+	if err := ag.ExecuteTask(context.Background(), task, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+
+	navigations := browser.CallsTo("Navigate")
+	if len(navigations) != 1 || navigations[0].Args[0] != task.StartURL {
+		t.Fatalf("Navigate calls = %+v, want the start URL", navigations)
+	}
+	// The first decision already sees the start page and the navigation in history
+	first := ai.CallsTo("DecideNextAction")[0]
+	if page := first.Args[1].(*entities.PageInfo); page.URL != task.StartURL {
+		t.Errorf("first decision on %s, want %s", page.URL, task.StartURL)
+	}
+	if history := first.Args[2].([]entities.Action); len(history) != 1 || history[0].URL != task.StartURL {
+		t.Errorf("history = %+v, want the start navigation", history)
+	}
+	if !strings.Contains(task.Context, task.StartURL) {
+		t.Errorf("context %q does not mention the start URL", task.Context)
+	}
+}
+
+func TestStartURLGoesThroughActionChecks(t *testing.T) {
+	// Navigation is not allowed: the start page is not opened and the AI learns why
+	t.Setenv("ALLOWED_ACTIONS", "click,extract")
+	browser := mocks.NewBrowser()
+	ai := mocks.NewAI()
+	ag, _ := newTestAgent(browser, ai, mocks.NewSecurity())
+	task := &entities.Task{ID: "task", Description: "search for Go jobs", StartURL: "https://jobs.example.com"}
+	if err := ag.ExecuteTask(context.Background(), task, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+	if n := browser.CallCount("Navigate"); n != 0 {
+		t.Errorf("disallowed start page was opened %d times", n)
+	}
+	if history := ai.CallsTo("DecideNextAction")[0].Args[2].([]entities.Action); len(history) != 1 || !strings.Contains(history[0].Error, "not allowed") {
+		t.Errorf("history = %+v, want the rejected start navigation", history)
+	}
+
+	// A navigation that needs approval is confirmed first, a refusal stops the task
+	t.Setenv("ALLOWED_ACTIONS", "")
+	browser = mocks.NewBrowser()
+	security := mocks.NewSecurity()
+	security.Approval[entities.ActionNavigate] = true
+	ag, _ = newTestAgent(browser, mocks.NewAI(), security)
+	task = &entities.Task{ID: "task", Description: "search for Go jobs", StartURL: "https://jobs.example.com"}
+	if err := ag.ExecuteTask(context.Background(), task, input("no")); err == nil {
+		t.Fatal("ExecuteTask succeeded after the start page was refused")
+	}
+	if n := browser.CallCount("Navigate"); n != 0 || task.Status != entities.TaskStatusWaiting {
+		t.Errorf("navigated %d times, status %s, want no navigation and a waiting task", n, task.Status)
+	}
+}
+
+func TestLoadAllExtractsEveryItem(t *testing.T) {
+	browser := mocks.NewBrowser()
+	browser.Loads = 4
+	// An infinite feed: 20 items until the list is scrolled to the end, then all 95
+	browser.PageInfoFunc = func(page int) *entities.PageInfo {
+		count := 20
+		if browser.CallCount("LoadAll") > 0 {
+			count = 95
+		}
+		info := &entities.PageInfo{URL: "https://feed.example.com", Page: page}
+		for i := 0; i < count; i++ {
+			info.Links = append(info.Links, entities.LinkInfo{Text: fmt.Sprintf("Item %d", i+1)})
+		}
+		return info
+	}
+	ai := mocks.NewAI(&entities.Action{Type: entities.ActionLoadAll, Description: "load the whole feed"})
+	ag, _ := newTestAgent(browser, ai, mocks.NewSecurity())
+
+	if err := ag.ExecuteTask(context.Background(), &entities.Task{ID: "task", Description: "collect all items"}, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+
+	calls := ai.CallsTo("DecideNextAction")
+	last := calls[len(calls)-1]
+	if page := last.Args[1].(*entities.PageInfo); len(page.Links) != 95 || page.Links[94].Text != "Item 95" {
+		t.Errorf("page after load_all has %d items, want all 95", len(page.Links))
+	}
+	history := last.Args[2].([]entities.Action)
+	if len(history) != 1 || !strings.Contains(history[0].Result, "95") {
+		t.Errorf("history = %+v, want load_all reporting 95 links", history)
+	}
+}
+
+func TestScrollAndWaitUseActionParameters(t *testing.T) {
+	browser := mocks.NewBrowser()
+	ai := mocks.NewAI(
+		&entities.Action{Type: entities.ActionScroll, Direction: "up", Amount: 1200, Description: "back to the filters"},
+		&entities.Action{Type: entities.ActionScroll, Description: "see more"},
+		&entities.Action{Type: entities.ActionWait, Timeout: 7, Description: "let the results load"},
+		&entities.Action{Type: entities.ActionWait, Timeout: 600, Description: "wait a long time"},
+	)
+	ag, _ := newTestAgent(browser, ai, mocks.NewSecurity())
+
+	if err := ag.ExecuteTask(context.Background(), &entities.Task{ID: "task", Description: "browse"}, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+
+	scrolls := browser.CallsTo("Scroll")
+	if len(scrolls) != 2 || scrolls[0].Args[0] != "up" || scrolls[0].Args[1] != 1200 ||
+		scrolls[1].Args[0] != "down" || scrolls[1].Args[1] != 500 {
+		t.Errorf("Scroll calls = %+v, want up 1200 then the default down 500", scrolls)
+	}
+	waits := browser.CallsTo("Wait")
+	if len(waits) != 2 || waits[0].Args[1] != 7 || waits[1].Args[1] != 30 {
+		t.Errorf("Wait calls = %+v, want 7 seconds then the 30 second cap", waits)
+	}
+}
+
+func TestRejectedCompletionResumesTask(t *testing.T) {
+	t.Setenv("VERIFY_COMPLETION", "true")
+
+	browser := mocks.NewBrowser()
+	ai := mocks.NewAI(
+		&entities.Action{Type: entities.ActionComplete, Text: "form filled", Description: "done"},
+		&entities.Action{Type: entities.ActionClick, Selector: "#submit", Description: "submit the form"},
+	)
+	ai.Rejections = []string{"the form was not submitted"}
+	ag, out := newTestAgent(browser, ai, mocks.NewSecurity())
+
+	task := &entities.Task{ID: "task", Description: "send the feedback form"}
+	if err := ag.ExecuteTask(context.Background(), task, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+
+	if browser.CallCount("ClickClosest") != 1 {
+		t.Error("the loop did not continue after the rejected completion")
+	}
+	if n := ai.CallCount("VerifyCompletion"); n != 2 {
+		t.Errorf("VerifyCompletion called %d times, want 2", n)
+	}
+	if task.Status != entities.TaskStatusCompleted {
+		t.Errorf("status = %s, want completed", task.Status)
+	}
+	if !strings.Contains(out.String(), i18n.T(i18n.MsgCompletionRejected, "the form was not submitted")) {
+		t.Errorf("rejection not reported:\n%s", out.String())
+	}
+	// The next decision sees why its completion was turned down
+	history := ai.CallsTo("DecideNextAction")[1].Args[2].([]entities.Action)
+	if len(history) != 1 || history[0].Type != entities.ActionComplete || !strings.Contains(history[0].Error, "not submitted") {
+		t.Errorf("history = %+v, want the rejected completion", history)
+	}
+}
@@ -0,0 +1,150 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"ai_automation/domain/entities"
+	"ai_automation/domain/interfaces"
+
+	"github.com/sirupsen/logrus"
+)
+
+// BrowserFactory - opens a browser of its own for one batch worker
+type BrowserFactory func() (interfaces.BrowserController, error)
+
+// TaskResult - outcome of one batch task
+type TaskResult struct {
+	Task *entities.Task
+	Err  error
+}
+
+// BatchRunner - runs independent tasks in parallel, each worker has its own agent and browser.
+// Workers share the AI service and security layer, which must be safe for concurrent use
+type BatchRunner struct {
+	newBrowser BrowserFactory
+	ai         interfaces.AIService
+	security   interfaces.SecurityLayer
+	logger     *logrus.Logger
+	out        Presenter
+}
+
+// NewBatchRunner - creates a runner opening worker browsers with newBrowser
+func NewBatchRunner(
+	newBrowser BrowserFactory,
+	ai interfaces.AIService,
+	security interfaces.SecurityLayer,
+	logger *logrus.Logger,
+) *BatchRunner {
+	return &BatchRunner{
+		newBrowser: newBrowser,
+		ai:         ai,
+		security:   security,
+		logger:     logger,
+		out:        NewConsolePresenter(os.Stdout),
+	}
+}
+
+// SetPresenter overrides where messages of all workers are written
+func (r *BatchRunner) SetPresenter(out Presenter) {
+	r.out = out
+}
+
+// RunTasks - runs tasks on up to concurrency workers and returns results in task order.
+// Nobody answers prompts in a batch, so an action that needs approval stops its task.
+// Every worker browser is closed before RunTasks returns
+func (r *BatchRunner) RunTasks(ctx context.Context, tasks []*entities.Task, concurrency int) []TaskResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > len(tasks) {
+		concurrency = len(tasks)
+	}
+
+	jobs := make(chan int, len(tasks))
+	for i := range tasks {
+		jobs <- i
+	}
+	close(jobs)
+
+	results := make([]TaskResult, len(tasks))
+	var mu sync.Mutex
+	var startErr error
+	var wg sync.WaitGroup
+	for worker := 0; worker < concurrency; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := r.runWorker(ctx, tasks, jobs, results, &mu); err != nil {
+				mu.Lock()
+				startErr = err
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Tasks left over when no worker could open a browser
+	for i, task := range tasks {
+		if results[i].Task == nil {
+			task.Status = entities.TaskStatusFailed
+			results[i] = TaskResult{Task: task, Err: fmt.Errorf("no browser available: %w", startErr)}
+		}
+	}
+	return results
+}
+
+// runWorker - takes tasks from jobs until it is drained, returns an error only when the browser did not start
+func (r *BatchRunner) runWorker(ctx context.Context, tasks []*entities.Task, jobs <-chan int, results []TaskResult, mu *sync.Mutex) error {
+	browser, err := r.newBrowser()
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to start batch worker browser")
+		return err
+	}
+	defer browser.Close()
+
+	ag := NewAgent(browser, r.ai, r.security, r.logger)
+	ag.SetProgress(nil)
+	for i := range jobs {
+		task := tasks[i]
+		if ctx.Err() != nil {
+			task.Status = entities.TaskStatusCancelled
+			results[i] = TaskResult{Task: task, Err: ctx.Err()}
+			continue
+		}
+
+		ag.SetPresenter(&taskPresenter{mu: mu, out: r.out, prefix: fmt.Sprintf("[%s] ", task.ID)})
+		err := ag.ExecuteTask(ctx, task, bufio.NewReader(strings.NewReader("")))
+		results[i] = TaskResult{Task: task, Err: err}
+	}
+	return nil
+}
+
+// taskPresenter - prefixes messages with the task ID and keeps output of parallel workers from interleaving
+type taskPresenter struct {
+	mu     *sync.Mutex
+	out    Presenter
+	prefix string
+}
+
+func (p *taskPresenter) Print(a ...interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.out.Print(p.prefix + fmt.Sprint(a...))
+}
+
+func (p *taskPresenter) Println(a ...interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.out.Print(p.prefix + fmt.Sprintln(a...))
+}
+
+func (p *taskPresenter) Printf(format string, a ...interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.out.Print(p.prefix + fmt.Sprintf(format, a...))
+}
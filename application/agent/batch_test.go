@@ -0,0 +1,113 @@
+package agent_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"ai_automation/application/agent"
+	"ai_automation/domain/entities"
+	"ai_automation/domain/interfaces"
+	"ai_automation/testing/mocks"
+)
+
+// browserPool - factory handing out mock browsers and remembering them
+type browserPool struct {
+	mu       sync.Mutex
+	browsers []*mocks.Browser
+	failures int
+}
+
+func (p *browserPool) newBrowser() (interfaces.BrowserController, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.failures > 0 {
+		p.failures--
+		return nil, errors.New("chrome did not start")
+	}
+	browser := mocks.NewBrowser()
+	p.browsers = append(p.browsers, browser)
+	return browser, nil
+}
+
+func newTestBatchRunner(pool *browserPool, ai *mocks.AI) (*agent.BatchRunner, *bytes.Buffer) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	var out bytes.Buffer
+	runner := agent.NewBatchRunner(pool.newBrowser, ai, mocks.NewSecurity(), logger)
+	runner.SetPresenter(agent.NewConsolePresenter(&out))
+	return runner, &out
+}
+
+func batchTasks(n int) []*entities.Task {
+	tasks := make([]*entities.Task, n)
+	for i := range tasks {
+		tasks[i] = &entities.Task{ID: fmt.Sprintf("task-%d", i+1), Description: fmt.Sprintf("task %d", i+1)}
+	}
+	return tasks
+}
+
+func TestRunTasksInParallel(t *testing.T) {
+	pool := &browserPool{}
+	runner, _ := newTestBatchRunner(pool, mocks.NewAI())
+
+	tasks := batchTasks(3)
+	results := runner.RunTasks(context.Background(), tasks, 2)
+
+	if len(results) != len(tasks) {
+		t.Fatalf("got %d results, want %d", len(results), len(tasks))
+	}
+	for i, result := range results {
+		if result.Task != tasks[i] {
+			t.Errorf("result %d is for %s, want %s", i, result.Task.ID, tasks[i].ID)
+		}
+		if result.Err != nil || result.Task.Status != entities.TaskStatusCompleted {
+			t.Errorf("%s: status %s, err %v; want completed", result.Task.ID, result.Task.Status, result.Err)
+		}
+	}
+	if len(pool.browsers) != 2 {
+		t.Errorf("opened %d browsers, want one per worker (2)", len(pool.browsers))
+	}
+	for i, browser := range pool.browsers {
+		if browser.CallCount("Close") != 1 {
+			t.Errorf("browser %d closed %d times, want 1", i, browser.CallCount("Close"))
+		}
+	}
+}
+
+func TestRunTasksWithoutBrowsers(t *testing.T) {
+	pool := &browserPool{failures: 2}
+	runner, _ := newTestBatchRunner(pool, mocks.NewAI())
+
+	results := runner.RunTasks(context.Background(), batchTasks(3), 2)
+	for _, result := range results {
+		if result.Err == nil || result.Task.Status != entities.TaskStatusFailed {
+			t.Errorf("%s: status %s, err %v; want failed", result.Task.ID, result.Task.Status, result.Err)
+		}
+	}
+}
+
+func TestRunTasksCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	pool := &browserPool{}
+	runner, _ := newTestBatchRunner(pool, mocks.NewAI())
+	for _, result := range runner.RunTasks(ctx, batchTasks(3), 2) {
+		if result.Task.Status != entities.TaskStatusCancelled {
+			t.Errorf("%s: status %s, want cancelled", result.Task.ID, result.Task.Status)
+		}
+	}
+	for i, browser := range pool.browsers {
+		if browser.CallCount("Close") != 1 {
+			t.Errorf("browser %d closed %d times, want 1", i, browser.CallCount("Close"))
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package agent
+
+import (
+	"strings"
+
+	"ai_automation/domain/entities"
+)
+
+// captchaFrameHosts - iframe sources of common CAPTCHA and bot-check widgets
+var captchaFrameHosts = []string{
+	"google.com/recaptcha",
+	"recaptcha.net",
+	"hcaptcha.com",
+	"challenges.cloudflare.com",
+	"arkoselabs.com",
+}
+
+// captchaMarkers - class names/ids of CAPTCHA containers found in element selectors
+var captchaMarkers = []string{"g-recaptcha", "h-captcha", "cf-turnstile", "captcha"}
+
+// captchaPhrases - visible text of CAPTCHA and bot-check pages
+var captchaPhrases = []string{
+	"i'm not a robot",
+	"i am not a robot",
+	"verify you are human",
+	"verify you're human",
+	"checking your browser",
+	"checking if the site connection is secure",
+	"complete the security check",
+	"я не робот",
+	"подтвердите, что вы не робот",
+}
+
+// DetectCaptcha - default heuristic that reports whether the page shows a CAPTCHA or bot challenge
+func DetectCaptcha(pageInfo *entities.PageInfo) bool {
+	if pageInfo == nil {
+		return false
+	}
+
+	// Widget iframe is the strongest signal
+	for _, frame := range pageInfo.Frames {
+		lowerFrame := strings.ToLower(frame)
+		for _, host := range captchaFrameHosts {
+			if strings.Contains(lowerFrame, host) {
+				return true
+			}
+		}
+	}
+
+	for _, elem := range pageInfo.Elements {
+		lowerSelector := strings.ToLower(elem.Selector)
+		for _, marker := range captchaMarkers {
+			if strings.Contains(lowerSelector, marker) {
+				return true
+			}
+		}
+	}
+
+	// Cloudflare interstitial
+	if strings.EqualFold(strings.TrimSpace(pageInfo.Title), "just a moment...") {
+		return true
+	}
+
+	lowerText := strings.ToLower(pageInfo.TextContent)
+	for _, phrase := range captchaPhrases {
+		if strings.Contains(lowerText, phrase) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// captchaDetector - returns DetectCaptcha unless detection is off (CAPTCHA_DETECTION=false)
+func captchaDetector(enabled bool) PageDetector {
+	if !enabled {
+		return nil
+	}
+	return DetectCaptcha
+}
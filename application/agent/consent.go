@@ -0,0 +1,26 @@
+package agent
+
+import (
+	"context"
+
+	"ai_automation/domain/i18n"
+)
+
+// dismissConsentBanner - clicks away a cookie banner on the freshly loaded page (AUTO_DISMISS_BANNERS=true),
+// so the AI does not spend a step on it. Best effort, failures are only logged
+func (a *Agent) dismissConsentBanner(ctx context.Context) {
+	if !a.dismissBanners {
+		return
+	}
+
+	clicked, err := a.browser.DismissConsentBanner(ctx)
+	if err != nil {
+		a.logger.WithError(err).Debug("Consent banner check failed")
+		return
+	}
+	if clicked == "" {
+		return
+	}
+	a.logger.Infof("Dismissed cookie consent banner: %s", clicked)
+	a.out.Println(i18n.T(i18n.MsgBannerDismissed, clicked))
+}
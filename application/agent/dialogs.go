@@ -0,0 +1,66 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+
+	"ai_automation/domain/entities"
+	"ai_automation/domain/i18n"
+)
+
+// DialogPolicy - how JS dialogs (alert/confirm/prompt/beforeunload) are answered
+type DialogPolicy string
+
+const (
+	// DialogAccept accepts every dialog
+	DialogAccept DialogPolicy = "accept"
+	// DialogDismiss dismisses every dialog
+	DialogDismiss DialogPolicy = "dismiss"
+	// DialogAsk shows the dialog message to the user and lets them decide
+	DialogAsk DialogPolicy = "ask"
+)
+
+// handleDialog - answers a dialog left open by the previous action according to the policy
+// and records its message in task context so the AI knows what happened
+func (a *Agent) handleDialog(ctx context.Context, task *entities.Task, reader *bufio.Reader) {
+	message, open := a.browser.GetOpenDialog(ctx)
+	if !open {
+		return
+	}
+	a.out.Printf("\n%s\n", i18n.T(i18n.MsgDialogOpened, message))
+
+	var accept bool
+	switch a.dialogPolicy {
+	case DialogAccept:
+		accept = true
+	case DialogDismiss:
+		accept = false
+	default:
+		a.out.Print(i18n.T(i18n.MsgDialogPrompt))
+		response, _ := reader.ReadString('\n')
+		accept = isApprovalResponse(strings.TrimSpace(strings.ToLower(response)))
+	}
+
+	if err := a.browser.HandleDialog(ctx, accept); err != nil {
+		a.logger.WithError(err).Warn("Failed to handle dialog")
+		return
+	}
+
+	outcome := "dismissed"
+	if accept {
+		outcome = "accepted"
+		a.out.Println(i18n.T(i18n.MsgDialogAccepted))
+	} else {
+		a.out.Println(i18n.T(i18n.MsgDialogDismissed))
+	}
+	a.out.Println()
+
+	entry := fmt.Sprintf("Browser dialog %q was %s", message, outcome)
+	if task.Context == "" {
+		task.Context = entry
+	} else {
+		task.Context += "\n" + entry
+	}
+}
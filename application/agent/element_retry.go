@@ -0,0 +1,38 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"ai_automation/domain/entities"
+	"ai_automation/domain/interfaces"
+)
+
+// maxMissRetries - how many times per step the AI may pick again after its selector matched nothing
+const maxMissRetries = 2
+
+// elementMissing - reports a failed action whose selector matches nothing on the current page.
+// Typed browser errors answer directly, other failures are checked against the page
+func (a *Agent) elementMissing(ctx context.Context, action *entities.Action, result *entities.ActionResult) bool {
+	if action.Selector == "" || ctx.Err() != nil {
+		return false
+	}
+	switch {
+	case errors.Is(result.Err, interfaces.ErrElementNotFound):
+		return true
+	case errors.Is(result.Err, interfaces.ErrTimeout),
+		errors.Is(result.Err, interfaces.ErrNavigation),
+		errors.Is(result.Err, interfaces.ErrCommand),
+		errors.Is(result.Err, interfaces.ErrElementDisabled),
+		errors.Is(result.Err, interfaces.ErrBrowserClosed):
+		return false
+	}
+	exists, err := a.browser.ElementExists(ctx, action.Selector)
+	return err == nil && !exists
+}
+
+// missingElementFeedback - error shown to the AI in history, the next prompt lists the elements that are present
+func missingElementFeedback(selector string) string {
+	return fmt.Sprintf("no element matches %s on this page, choose a selector from the elements listed for the current page", selector)
+}
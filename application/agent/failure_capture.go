@@ -0,0 +1,51 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ai_automation/domain/entities"
+)
+
+// failureScreenshotDir - returns directory for failure screenshots
+func failureScreenshotDir() (string, error) {
+	homeDir := os.Getenv("HOME")
+	if homeDir == "" {
+		return "", fmt.Errorf("HOME environment variable is not set")
+	}
+	return filepath.Join(homeDir, ".ai_automation", "failures"), nil
+}
+
+// captureFailure - saves screenshot of the page after failed action and appends its path to the result error.
+// Screenshot problems are only logged so the original error is never masked
+func (a *Agent) captureFailure(ctx context.Context, task *entities.Task, step int, result *entities.ActionResult) {
+	dir, err := failureScreenshotDir()
+	if err != nil {
+		a.logger.Warnf("Failed to capture failure screenshot: %v", err)
+		return
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		a.logger.Warnf("Failed to create failure screenshot directory: %v", err)
+		return
+	}
+
+	screenshot, err := a.browser.TakeScreenshot(ctx)
+	if err != nil {
+		a.logger.Warnf("Failed to take failure screenshot: %v", err)
+		return
+	}
+
+	taskID := strings.NewReplacer("/", "_", string(os.PathSeparator), "_").Replace(task.ID)
+	path := filepath.Join(dir, fmt.Sprintf("%s-%d.png", taskID, step+1))
+	if err := os.WriteFile(path, screenshot, 0644); err != nil {
+		a.logger.Warnf("Failed to save failure screenshot: %v", err)
+		return
+	}
+
+	a.logger.Infof("Saved failure screenshot to %s", path)
+	result.Error = fmt.Sprintf("%s (screenshot: %s)", result.Error, path)
+}
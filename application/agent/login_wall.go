@@ -0,0 +1,94 @@
+package agent
+
+import (
+	"net/url"
+	"strings"
+
+	"ai_automation/domain/entities"
+)
+
+// PageDetector inspects page info and reports whether the page matches a condition
+type PageDetector func(pageInfo *entities.PageInfo) bool
+
+// loginURLKeywords - URL fragments of sign-in pages
+var loginURLKeywords = []string{"login", "signin", "sign-in", "sign_in"}
+
+// signInPhrases - wording of sign-in titles and submit buttons
+var signInPhrases = []string{"sign in", "log in", "login", "войти", "вход"}
+
+// DetectLoginWall - default heuristic that reports whether the page is a login/auth wall.
+// A password field alone is not enough (signup and change-password forms have one too),
+// it needs sign-in wording in the page title
+func DetectLoginWall(pageInfo *entities.PageInfo) bool {
+	if pageInfo == nil {
+		return false
+	}
+
+	if isLoginURL(pageInfo.URL) {
+		return true
+	}
+
+	// Sign-in form, also without a visible password field (e.g. two-step login)
+	for _, form := range pageInfo.Forms {
+		if hasSignInWording(form.SubmitText) {
+			return true
+		}
+	}
+
+	return hasPasswordField(pageInfo) && hasSignInWording(pageInfo.Title)
+}
+
+// isLoginURL - checks URL for sign-in keywords; "auth" only counts as a whole path segment
+// or host label, so /author and /oauth/callback are not login pages
+func isLoginURL(rawURL string) bool {
+	lowerURL := strings.ToLower(rawURL)
+	for _, keyword := range loginURLKeywords {
+		if strings.Contains(lowerURL, keyword) {
+			return true
+		}
+	}
+
+	parsed, err := url.Parse(lowerURL)
+	if err != nil {
+		return false
+	}
+	for _, segment := range strings.Split(parsed.Path, "/") {
+		if segment == "auth" {
+			return true
+		}
+	}
+	for _, label := range strings.Split(parsed.Hostname(), ".") {
+		if label == "auth" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasSignInWording - checks text for sign-in phrases
+func hasSignInWording(text string) bool {
+	lowerText := strings.ToLower(text)
+	for _, phrase := range signInPhrases {
+		if strings.Contains(lowerText, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPasswordField - checks forms and extracted elements for a password input
+func hasPasswordField(pageInfo *entities.PageInfo) bool {
+	for _, form := range pageInfo.Forms {
+		for _, input := range form.Inputs {
+			if strings.EqualFold(input.Type, "password") {
+				return true
+			}
+		}
+	}
+	for _, elem := range pageInfo.Elements {
+		if elem.TagName == "input" && strings.EqualFold(elem.Attributes["type"], "password") {
+			return true
+		}
+	}
+	return false
+}
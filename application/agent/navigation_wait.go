@@ -0,0 +1,41 @@
+package agent
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// clickNavigationWindow - how long to watch for a URL change after a click
+	clickNavigationWindow = 2 * time.Second
+	// pageLoadTimeout - max time to wait for a page to finish loading
+	pageLoadTimeout = 15 * time.Second
+	// contentWaitTimeout - max time to wait for SPA content to appear or settle
+	contentWaitTimeout = 10 * time.Second
+	// loadAllMaxScrolls - how many times load_all may scroll an infinite list
+	loadAllMaxScrolls = 30
+	// defaultWaitSeconds and maxWaitSeconds - duration of a wait action without a timeout and its cap
+	defaultWaitSeconds = 3
+	maxWaitSeconds     = 30
+	// defaultScrollAmount - pixels scrolled when the action gives no amount
+	defaultScrollAmount = 500
+)
+
+// waitAfterClick - if the click started a navigation, waits for the new page to load
+// so the next step does not read stale page info. Clicks that stay on the page only cost the short window
+func (a *Agent) waitAfterClick(ctx context.Context, beforeURL string) {
+	if beforeURL == "" {
+		return
+	}
+
+	newURL, err := a.browser.WaitForURLChange(ctx, beforeURL, clickNavigationWindow)
+	if err != nil {
+		return
+	}
+
+	a.logger.Infof("Click navigated to %s, waiting for page load", newURL)
+	if err := a.browser.WaitForNavigation(ctx, pageLoadTimeout); err != nil {
+		a.logger.Warnf("Page did not finish loading: %v", err)
+	}
+	a.dismissConsentBanner(ctx)
+}
@@ -0,0 +1,56 @@
+package agent
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"ai_automation/domain/entities"
+)
+
+// progressTracker - counts consecutive steps after which the page looked exactly the same.
+// Failed actions are caught elsewhere, this catches successful ones that change nothing
+// (the same scroll or extract over and over)
+type progressTracker struct {
+	// limit is MAX_NOOP_ITERATIONS, zero disables the check
+	limit int
+	last  string
+	noops int
+}
+
+// observe - records the page seen at the start of a step, reports when limit steps in a row left it unchanged
+func (p *progressTracker) observe(pageInfo *entities.PageInfo) bool {
+	if p.limit <= 0 {
+		return false
+	}
+	fingerprint := pageFingerprint(pageInfo)
+	if fingerprint == p.last {
+		p.noops++
+	} else {
+		p.last = fingerprint
+		p.noops = 0
+	}
+	return p.noops >= p.limit
+}
+
+// reset - forgets the page, for steps that make progress without touching it (answers, finished
+// subtasks, new values read)
+func (p *progressTracker) reset() {
+	p.last = ""
+	p.noops = 0
+}
+
+// pageFingerprint - hashes what a step can change: URL, title, text and the elements with their values
+func pageFingerprint(pageInfo *entities.PageInfo) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%d\x00%s\x00%s\x00", pageInfo.URL, pageInfo.Title, pageInfo.Page, pageInfo.Scope, pageInfo.TextContent)
+	for _, elements := range [][]entities.PageElement{pageInfo.Elements, pageInfo.Buttons} {
+		for _, elem := range elements {
+			fmt.Fprintf(h, "%s\x00%s\x00%s\x00", elem.Selector, elem.Text, elem.Value)
+		}
+	}
+	for _, link := range pageInfo.Links {
+		fmt.Fprintf(h, "%s\x00%s\x00", link.URL, link.Text)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,43 @@
+package agent
+
+import "ai_automation/domain/entities"
+
+// Observer - receives the lifecycle of tasks, for GUIs and monitoring built on the agent.
+// Callbacks run on the task goroutine in the order below and must return quickly; the task
+// and action are the agent's own, read them but do not change them
+type Observer interface {
+	// OnActionDecided is called for every action the AI chose, queued follow-ups and the
+	// navigation to Task.StartURL included, before it is checked against ALLOWED_ACTIONS and approval
+	OnActionDecided(task *entities.Task, action *entities.Action)
+	// OnApprovalRequired is called before the user (or the Approver) is asked about the action
+	OnApprovalRequired(task *entities.Task, action *entities.Action)
+	// OnActionExecuted is called after the action ran in the browser, result tells whether it succeeded
+	OnActionExecuted(task *entities.Task, action *entities.Action, result *entities.ActionResult)
+	// OnTaskComplete is called once when ExecuteTask returns, err is the error it returns;
+	// task.Status tells a finished task from a failed, cancelled or waiting one
+	OnTaskComplete(task *entities.Task, err error)
+}
+
+// BaseObserver - Observer with empty callbacks, embed it to implement only the ones needed
+type BaseObserver struct{}
+
+func (BaseObserver) OnActionDecided(task *entities.Task, action *entities.Action) {}
+
+func (BaseObserver) OnApprovalRequired(task *entities.Task, action *entities.Action) {}
+
+func (BaseObserver) OnActionExecuted(task *entities.Task, action *entities.Action, result *entities.ActionResult) {
+}
+
+func (BaseObserver) OnTaskComplete(task *entities.Task, err error) {}
+
+// AddObserver registers an observer of the task lifecycle, observers are called in the order added
+func (a *Agent) AddObserver(observer Observer) {
+	a.observers = append(a.observers, observer)
+}
+
+// notify - calls fn for every registered observer
+func (a *Agent) notify(fn func(Observer)) {
+	for _, observer := range a.observers {
+		fn(observer)
+	}
+}
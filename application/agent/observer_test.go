@@ -0,0 +1,110 @@
+package agent_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"ai_automation/application/agent"
+	"ai_automation/domain/entities"
+	"ai_automation/testing/mocks"
+)
+
+// recordingObserver - records callbacks as "hook:action" strings
+type recordingObserver struct {
+	events []string
+}
+
+func (r *recordingObserver) OnActionDecided(task *entities.Task, action *entities.Action) {
+	r.events = append(r.events, "decided:"+string(action.Type))
+}
+
+func (r *recordingObserver) OnApprovalRequired(task *entities.Task, action *entities.Action) {
+	r.events = append(r.events, "approval:"+string(action.Type))
+}
+
+func (r *recordingObserver) OnActionExecuted(task *entities.Task, action *entities.Action, result *entities.ActionResult) {
+	r.events = append(r.events, fmt.Sprintf("executed:%s:%v", action.Type, result.Success))
+}
+
+func (r *recordingObserver) OnTaskComplete(task *entities.Task, err error) {
+	r.events = append(r.events, fmt.Sprintf("complete:%s:%v", task.Status, err))
+}
+
+func TestObserverSeesTaskLifecycle(t *testing.T) {
+	browser := mocks.NewBrowser()
+	ai := mocks.NewAI(
+		&entities.Action{Type: entities.ActionNavigate, URL: "https://shop.example", Description: "open the shop"},
+		&entities.Action{Type: entities.ActionClick, Selector: "#buy", Description: "buy"},
+		&entities.Action{Type: entities.ActionComplete, Text: "bought"},
+	)
+	security := mocks.NewSecurity()
+	security.Approval[entities.ActionClick] = true
+	ag, _ := newTestAgent(browser, ai, security)
+	ag.SetApprover(func(ctx context.Context, action *entities.Action) bool { return true })
+
+	observer := &recordingObserver{}
+	ag.AddObserver(observer)
+	// Observers with only some callbacks embed BaseObserver
+	completed := 0
+	ag.AddObserver(&completionCounter{count: &completed})
+
+	if err := ag.ExecuteTask(context.Background(), &entities.Task{ID: "task", Description: "buy the item"}, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+
+	want := []string{
+		"decided:navigate", "executed:navigate:true",
+		"decided:click", "approval:click", "executed:click:true",
+		"decided:complete", "complete:completed:<nil>",
+	}
+	if got := strings.Join(observer.events, " "); got != strings.Join(want, " ") {
+		t.Errorf("events = %s\nwant     %s", got, strings.Join(want, " "))
+	}
+	if completed != 1 {
+		t.Errorf("OnTaskComplete of the second observer called %d times, want 1", completed)
+	}
+}
+
+func TestObserverSeesStartNavigation(t *testing.T) {
+	ag, _ := newTestAgent(mocks.NewBrowser(), mocks.NewAI(&entities.Action{Type: entities.ActionComplete, Text: "done"}), mocks.NewSecurity())
+	observer := &recordingObserver{}
+	ag.AddObserver(observer)
+
+	task := &entities.Task{ID: "task", Description: "read the headline", StartURL: "https://news.example"}
+	if err := ag.ExecuteTask(context.Background(), task, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+	want := "decided:navigate executed:navigate:true decided:complete complete:completed:<nil>"
+	if got := strings.Join(observer.events, " "); got != want {
+		t.Errorf("events = %s, want %s", got, want)
+	}
+}
+
+func TestObserverSeesFailedTask(t *testing.T) {
+	security := mocks.NewSecurity()
+	security.Approval[entities.ActionClick] = true
+	ag, _ := newTestAgent(mocks.NewBrowser(), mocks.NewAI(&entities.Action{Type: entities.ActionClick, Selector: "#delete"}), security)
+	ag.SetApprover(func(ctx context.Context, action *entities.Action) bool { return false })
+	observer := &recordingObserver{}
+	ag.AddObserver(observer)
+
+	err := ag.ExecuteTask(context.Background(), &entities.Task{ID: "task", Description: "clean up"}, input())
+	if err == nil {
+		t.Fatal("ExecuteTask succeeded although the action was rejected")
+	}
+	want := fmt.Sprintf("decided:click approval:click complete:%s:%v", entities.TaskStatusWaiting, err)
+	if got := strings.Join(observer.events, " "); got != want {
+		t.Errorf("events = %s, want %s", got, want)
+	}
+}
+
+type completionCounter struct {
+	agent.BaseObserver
+	count *int
+}
+
+func (c *completionCounter) OnTaskComplete(task *entities.Task, err error) {
+	*c.count++
+}
@@ -0,0 +1,51 @@
+package agent
+
+import (
+	"fmt"
+	"io"
+)
+
+// Presenter - writes user-facing progress messages. It is kept apart from the logrus logger,
+// so machine logs (stderr, optionally JSON) never mix with human output
+type Presenter interface {
+	Print(a ...interface{})
+	Println(a ...interface{})
+	Printf(format string, a ...interface{})
+}
+
+// consolePresenter - Presenter writing plain text to a terminal
+type consolePresenter struct {
+	w io.Writer
+}
+
+// NewConsolePresenter - creates presenter writing to w
+func NewConsolePresenter(w io.Writer) Presenter {
+	return &consolePresenter{w: w}
+}
+
+func (p *consolePresenter) Print(a ...interface{}) {
+	fmt.Fprint(p.w, a...)
+}
+
+func (p *consolePresenter) Println(a ...interface{}) {
+	fmt.Fprintln(p.w, a...)
+}
+
+func (p *consolePresenter) Printf(format string, a ...interface{}) {
+	fmt.Fprintf(p.w, format, a...)
+}
+
+// presenterWriter - io.Writer printing through a Presenter, for output that arrives in pieces (streamed AI replies)
+type presenterWriter struct {
+	out Presenter
+}
+
+// NewPresenterWriter - creates writer printing to out
+func NewPresenterWriter(out Presenter) io.Writer {
+	return presenterWriter{out: out}
+}
+
+func (w presenterWriter) Write(p []byte) (int, error) {
+	w.out.Print(string(p))
+	return len(p), nil
+}
@@ -0,0 +1,51 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"ai_automation/domain/i18n"
+)
+
+// spinnerFrames - frames shown in front of the status line on a terminal
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Progress - formats a "Step N/max • tokens • elapsed" status line once per iteration,
+// the agent prints it through its Presenter. On a terminal the line is dimmed and has a spinner,
+// otherwise (pipes, files, batch runs) it is a plain line without ANSI control codes
+type Progress struct {
+	tty   bool
+	frame int
+}
+
+// NewProgress - creates progress indicator, tty enables ANSI styling
+func NewProgress(tty bool) *Progress {
+	return &Progress{tty: tty}
+}
+
+// isTerminal - reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Line - returns the status for step (1-based); tokens below zero are not shown
+func (p *Progress) Line(step, maxSteps, tokens int, elapsed time.Duration) string {
+	line := i18n.T(i18n.MsgProgressStep, step, maxSteps)
+	if tokens >= 0 {
+		line += " • " + i18n.T(i18n.MsgProgressTokens, tokens)
+	}
+	line += " • " + elapsed.Round(time.Second).String()
+
+	if !p.tty {
+		return line
+	}
+
+	frame := spinnerFrames[p.frame%len(spinnerFrames)]
+	p.frame++
+	return fmt.Sprintf("\033[2m%s %s\033[0m", frame, line)
+}
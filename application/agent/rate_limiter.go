@@ -0,0 +1,93 @@
+package agent
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// navigationWindow - window for per-domain navigation cap
+const navigationWindow = time.Minute
+
+// navigationLimiter - delays navigations so the agent does not hammer target sites
+type navigationLimiter struct {
+	mu           sync.Mutex
+	minInterval  time.Duration
+	domainLimit  int
+	lastNavigate time.Time
+	history      map[string][]time.Time
+	now          func() time.Time
+}
+
+// newNavigationLimiter - creates limiter configured by
+// MIN_NAVIGATE_INTERVAL_MS and MAX_NAVIGATIONS_PER_DOMAIN (per minute), zero disables a limit
+func newNavigationLimiter(minInterval time.Duration, domainLimit int) *navigationLimiter {
+	return &navigationLimiter{
+		minInterval: minInterval,
+		domainLimit: domainLimit,
+		history:     map[string][]time.Time{},
+		now:         time.Now,
+	}
+}
+
+// Wait - blocks until navigation to rawURL is allowed, returns ctx error if cancelled while waiting
+func (l *navigationLimiter) Wait(ctx context.Context, rawURL string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	domain := rawURL
+	if parsed, err := url.Parse(rawURL); err == nil && parsed.Host != "" {
+		domain = parsed.Hostname()
+	}
+
+	delay := l.delayFor(domain)
+	if delay > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	now := l.now()
+	l.lastNavigate = now
+	// Per-domain history is only needed (and pruned) when the domain cap is on
+	if l.domainLimit > 0 {
+		l.history[domain] = append(l.history[domain], now)
+	}
+
+	return nil
+}
+
+// delayFor - computes how long to wait before the next navigation to domain
+func (l *navigationLimiter) delayFor(domain string) time.Duration {
+	now := l.now()
+	var delay time.Duration
+
+	if l.minInterval > 0 && !l.lastNavigate.IsZero() {
+		if wait := l.lastNavigate.Add(l.minInterval).Sub(now); wait > delay {
+			delay = wait
+		}
+	}
+
+	if l.domainLimit > 0 {
+		// Drop navigations that left the window
+		recent := l.history[domain][:0]
+		for _, ts := range l.history[domain] {
+			if now.Sub(ts) < navigationWindow {
+				recent = append(recent, ts)
+			}
+		}
+		l.history[domain] = recent
+
+		if len(recent) >= l.domainLimit {
+			oldest := recent[len(recent)-l.domainLimit]
+			if wait := oldest.Add(navigationWindow).Sub(now); wait > delay {
+				delay = wait
+			}
+		}
+	}
+
+	return delay
+}
@@ -0,0 +1,91 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"ai_automation/domain/entities"
+	"ai_automation/domain/i18n"
+)
+
+// Script - recorded sequence of successful actions that can be replayed without the AI
+type Script struct {
+	Task    string            `json:"task"`
+	Actions []entities.Action `json:"actions"`
+}
+
+// SaveScript - writes successful actions of the task to a JSON script file
+func SaveScript(path string, task *entities.Task) error {
+	if len(task.Actions) == 0 {
+		return fmt.Errorf("task has no executed actions")
+	}
+
+	data, err := json.MarshalIndent(Script{Task: task.Description, Actions: task.Actions}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadScript - reads a script saved by SaveScript
+func LoadScript(path string) (*Script, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read script: %w", err)
+	}
+
+	var script Script
+	if err := json.Unmarshal(data, &script); err != nil {
+		return nil, fmt.Errorf("invalid script %s: %w", path, err)
+	}
+	if len(script.Actions) == 0 {
+		return nil, fmt.Errorf("script %s has no actions", path)
+	}
+	return &script, nil
+}
+
+// Replay - executes script actions in order through the browser without calling the AI.
+// Every step goes through the same approval check and dialog handling as ExecuteTask,
+// since the page may have changed since the script was recorded.
+// The first failed or rejected step stops the replay
+func (a *Agent) Replay(ctx context.Context, script *Script, reader *bufio.Reader) error {
+	task := &entities.Task{Description: script.Task, Status: entities.TaskStatusInProgress}
+	total := len(script.Actions)
+	for i := range script.Actions {
+		action := &script.Actions[i]
+		if ctx.Err() != nil {
+			return fmt.Errorf("replay cancelled at step %d: %w", i+1, ctx.Err())
+		}
+
+		a.handleDialog(ctx, task, reader)
+
+		pageInfo, err := a.browser.ExtractPageInfo(ctx, 0)
+		if err != nil {
+			return fmt.Errorf("replay stopped at step %d: failed to extract page info: %w", i+1, err)
+		}
+
+		a.out.Println(i18n.T(i18n.MsgReplayStep, i+1, total, getActionDescription(action)))
+		action.RequiresApproval = a.security.RequiresApproval(ctx, action, pageInfo)
+		if action.RequiresApproval && !a.confirmAction(ctx, action, reader) {
+			return fmt.Errorf("replay stopped at step %d (%s): action cancelled by user", i+1, action.Type)
+		}
+
+		result := a.executeStep(ctx, action)
+		if !result.Success {
+			return fmt.Errorf("replay stopped at step %d (%s): %s", i+1, action.Type, result.Error)
+		}
+		a.out.Printf("%s\n\n", result.Message)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("replay cancelled at step %d: %w", i+1, ctx.Err())
+		case <-time.After(1 * time.Second):
+		}
+	}
+
+	return nil
+}
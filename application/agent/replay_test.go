@@ -0,0 +1,123 @@
+package agent_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"ai_automation/application/agent"
+	"ai_automation/domain/entities"
+	"ai_automation/testing/mocks"
+)
+
+// newTestAgent - agent on mocks with output captured and progress disabled
+func newTestAgent(browser *mocks.Browser, ai *mocks.AI, security *mocks.Security) (*agent.Agent, *bytes.Buffer) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	var out bytes.Buffer
+	ag := agent.NewAgent(browser, ai, security, logger)
+	ag.SetPresenter(agent.NewConsolePresenter(&out))
+	ag.SetProgress(nil)
+	return ag, &out
+}
+
+// input - reader with the given user answers, one per line
+func input(lines ...string) *bufio.Reader {
+	return bufio.NewReader(strings.NewReader(strings.Join(lines, "\n") + "\n"))
+}
+
+func TestExportReplayRoundTrip(t *testing.T) {
+	ai := mocks.NewAI(
+		&entities.Action{Type: entities.ActionNavigate, URL: "https://example.com", Description: "open site"},
+		&entities.Action{Type: entities.ActionClick, Selector: "#more", Description: "open details"},
+	)
+	ag, _ := newTestAgent(mocks.NewBrowser(), ai, mocks.NewSecurity())
+
+	task := &entities.Task{ID: "task", Description: "open details"}
+	if err := ag.ExecuteTask(context.Background(), task, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "script.json")
+	if err := agent.SaveScript(path, task); err != nil {
+		t.Fatalf("SaveScript: %v", err)
+	}
+	script, err := agent.LoadScript(path)
+	if err != nil {
+		t.Fatalf("LoadScript: %v", err)
+	}
+	if script.Task != task.Description || len(script.Actions) != 2 {
+		t.Fatalf("script = %q with %d actions, want %q with 2", script.Task, len(script.Actions), task.Description)
+	}
+
+	browser := mocks.NewBrowser()
+	replayAI := mocks.NewAI()
+	replayer, _ := newTestAgent(browser, replayAI, mocks.NewSecurity())
+	if err := replayer.Replay(context.Background(), script, input()); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+
+	if browser.URL != "https://example.com" {
+		t.Errorf("URL after replay = %q, want https://example.com", browser.URL)
+	}
+	if calls := browser.CallsTo("ClickClosest"); len(calls) != 1 || calls[0].Args[0] != "#more" {
+		t.Errorf("clicks during replay = %v, want one click on #more", calls)
+	}
+	if len(replayAI.Calls()) != 0 {
+		t.Errorf("replay called the AI: %v", replayAI.Calls())
+	}
+}
+
+func TestReplayAsksApproval(t *testing.T) {
+	script := &agent.Script{
+		Task:    "delete item",
+		Actions: []entities.Action{{Type: entities.ActionClick, Selector: "#delete", Description: "delete item"}},
+	}
+	security := mocks.NewSecurity()
+	security.Approval[entities.ActionClick] = true
+
+	browser := mocks.NewBrowser()
+	ag, _ := newTestAgent(browser, mocks.NewAI(), security)
+	if err := ag.Replay(context.Background(), script, input("no")); err == nil {
+		t.Fatal("Replay succeeded after the user rejected the action")
+	}
+	if security.CallCount("RequiresApproval") != 1 {
+		t.Errorf("RequiresApproval called %d times, want 1", security.CallCount("RequiresApproval"))
+	}
+	if n := browser.CallCount("Click") + browser.CallCount("ClickClosest"); n != 0 {
+		t.Errorf("rejected click was executed %d times", n)
+	}
+
+	browser.Reset()
+	if err := ag.Replay(context.Background(), script, input("yes")); err != nil {
+		t.Fatalf("Replay after approval: %v", err)
+	}
+	if browser.CallCount("Click") != 1 {
+		t.Errorf("approved click executed %d times, want 1", browser.CallCount("Click"))
+	}
+}
+
+func TestReplayHandlesDialog(t *testing.T) {
+	script := &agent.Script{
+		Task:    "submit",
+		Actions: []entities.Action{{Type: entities.ActionClick, Selector: "#submit", Description: "submit"}},
+	}
+	browser := mocks.NewBrowser()
+	browser.DialogMessage = "Leave site?"
+	browser.DialogOpen = true
+
+	ag, _ := newTestAgent(browser, mocks.NewAI(), mocks.NewSecurity())
+	if err := ag.Replay(context.Background(), script, input("yes")); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if calls := browser.CallsTo("HandleDialog"); len(calls) != 1 || calls[0].Args[0] != true {
+		t.Errorf("HandleDialog calls = %v, want one accept", calls)
+	}
+}
@@ -0,0 +1,46 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+
+	"ai_automation/domain/entities"
+	"ai_automation/domain/i18n"
+)
+
+// RestorePages - reopens pages of the last session: the first one in the current tab, the others
+// in new tabs, focus staying on the first. Each page is opened like a navigate action the AI chose,
+// so ALLOWED_ACTIONS, approval rules and the navigation rate limit apply. Approval rules see the page
+// being opened, a sensitive site is confirmed before it loads; a page that is not admitted is skipped
+func (a *Agent) RestorePages(ctx context.Context, urls []string, reader *bufio.Reader) error {
+	for i, url := range urls {
+		action := &entities.Action{
+			Type:        entities.ActionNavigate,
+			URL:         url,
+			NewTab:      i > 0,
+			Description: "reopen a page of the last session",
+		}
+		if !a.allowedActions.Allows(action.Type) {
+			a.out.Println(i18n.T(i18n.MsgActionNotAllowed, action.Type))
+			return nil
+		}
+		if a.security.RequiresApproval(ctx, action, &entities.PageInfo{URL: url}) {
+			action.RequiresApproval = true
+			if !a.confirmAction(ctx, action, reader) {
+				continue
+			}
+		}
+
+		result := a.executeStep(ctx, action)
+		if !result.Success {
+			return fmt.Errorf("%s: %s", url, result.Error)
+		}
+		if action.NewTab {
+			if err := a.browser.SwitchToPreviousTab(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
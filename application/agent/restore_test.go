@@ -0,0 +1,36 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"ai_automation/testing/mocks"
+)
+
+func TestRestorePagesConfirmsSensitivePages(t *testing.T) {
+	browser := mocks.NewBrowser()
+	security := mocks.NewSecurity()
+	security.SensitiveURLs["https://bank.example.com/account"] = true
+	ag, _ := newTestAgent(browser, mocks.NewAI(), security)
+
+	pages := []string{
+		"https://shop.example.com/cart",
+		"https://bank.example.com/account",
+		"https://shop.example.com/item/1",
+	}
+	if err := ag.RestorePages(context.Background(), pages, input("no")); err != nil {
+		t.Fatalf("RestorePages: %v", err)
+	}
+
+	navigations := browser.CallsTo("Navigate")
+	if len(navigations) != 1 || navigations[0].Args[0] != pages[0] {
+		t.Errorf("Navigate calls = %v, want only the active page", navigations)
+	}
+	tabs := browser.CallsTo("OpenNewTab")
+	if len(tabs) != 1 || tabs[0].Args[0] != pages[2] {
+		t.Errorf("OpenNewTab calls = %v, want only the shop item, the rejected bank page skipped", tabs)
+	}
+	if n := browser.CallCount("SwitchToPreviousTab"); n != 1 {
+		t.Errorf("SwitchToPreviousTab called %d times, want focus back after the one new tab", n)
+	}
+}
@@ -0,0 +1,70 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// screenshotDir - returns directory for screenshot actions: SCREENSHOT_DIR or ~/.ai_automation/screenshots
+func screenshotDir(configured string) (string, error) {
+	if dir := configured; dir != "" {
+		return dir, nil
+	}
+	homeDir := os.Getenv("HOME")
+	if homeDir == "" {
+		return "", fmt.Errorf("HOME environment variable is not set")
+	}
+	return filepath.Join(homeDir, ".ai_automation", "screenshots"), nil
+}
+
+// outputPath - path of a file the agent saves in dir. Names come from the AI, only their base name
+// is kept so they cannot escape the directory; an empty name becomes prefix plus a timestamp
+func outputPath(dir, name, prefix, ext string) string {
+	name = strings.TrimSuffix(filepath.Base(strings.TrimSpace(name)), ext)
+	if name == "" || name == "." || name == string(os.PathSeparator) {
+		name = prefix + "-" + time.Now().Format("20060102-150405")
+	}
+	return filepath.Join(dir, name+ext)
+}
+
+// saveScreenshot - takes a screenshot and saves it as PNG, name defaults to a timestamp.
+// Returns the saved file path
+func (a *Agent) saveScreenshot(ctx context.Context, name string) (string, error) {
+	dir, err := screenshotDir(a.screenshotDir)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create screenshot directory: %w", err)
+	}
+
+	screenshot, err := a.browser.TakeScreenshot(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to take screenshot: %w", err)
+	}
+
+	path := outputPath(dir, name, "screenshot", ".png")
+	if err := os.WriteFile(path, screenshot, 0644); err != nil {
+		return "", fmt.Errorf("failed to save screenshot: %w", err)
+	}
+	return path, nil
+}
+
+// savePDF - saves the current page as PDF next to the screenshots, name defaults to a timestamp.
+// Returns the saved file path
+func (a *Agent) savePDF(ctx context.Context, name string) (string, error) {
+	dir, err := screenshotDir(a.screenshotDir)
+	if err != nil {
+		return "", err
+	}
+
+	path := outputPath(dir, name, "page", ".pdf")
+	if err := a.browser.SavePDF(ctx, path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,48 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+
+	"ai_automation/domain/entities"
+	"ai_automation/domain/i18n"
+)
+
+// openStartURL - navigates to task.StartURL before the first decision and returns the navigate
+// action for history, with its error when the page could not be opened or the navigation was not
+// admitted, so the AI can recover. It goes through the same checks and observers as AI-chosen
+// actions; a refused approval is returned as the error
+func (a *Agent) openStartURL(ctx context.Context, task *entities.Task, reader *bufio.Reader) (*entities.Action, error) {
+	action := &entities.Action{
+		Type:        entities.ActionNavigate,
+		URL:         task.StartURL,
+		Description: "open the start page of the task",
+	}
+	addContext(task, "Start page: "+task.StartURL)
+	a.notify(func(o Observer) { o.OnActionDecided(task, action) })
+
+	// Nothing is open yet, approval rules see the page the browser is on
+	pageInfo := &entities.PageInfo{}
+	if current, err := a.browser.GetCurrentURL(ctx); err == nil {
+		pageInfo.URL = current
+	}
+	admitted, err := a.admitAction(ctx, task, action, pageInfo, reader)
+	if err != nil || !admitted {
+		return action, err
+	}
+
+	a.out.Println(i18n.T(i18n.MsgExecutingAction, getActionDescription(action)))
+	result := a.executeStep(ctx, action)
+	a.notify(func(o Observer) { o.OnActionExecuted(task, action, result) })
+	if !result.Success {
+		a.logger.Warnf("Failed to open start page %s: %s", task.StartURL, result.Error)
+		a.out.Println(i18n.T(i18n.MsgActionError, result.Message, result.Error))
+		a.out.Println()
+		action.Error = result.Error
+		return action, nil
+	}
+
+	a.out.Printf("%s\n\n", result.Message)
+	task.Actions = append(task.Actions, *action)
+	return action, nil
+}
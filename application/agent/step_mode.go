@@ -0,0 +1,55 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"time"
+
+	"ai_automation/domain/entities"
+	"ai_automation/domain/i18n"
+)
+
+// slowDown - waits the slow motion delay so a person watching can follow the action
+func (a *Agent) slowDown(ctx context.Context) {
+	if a.slowMo <= 0 {
+		return
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(a.slowMo):
+	}
+}
+
+// bringToFront - with FOCUS_WINDOW raises the browser window so the user keeps sight of the agent.
+// Failures are only logged, the action runs either way
+func (a *Agent) bringToFront(ctx context.Context) {
+	if !a.focusWindow {
+		return
+	}
+	if err := a.browser.BringToFront(ctx); err != nil {
+		a.logger.Debugf("Failed to bring browser window to front: %v", err)
+	}
+}
+
+// switchesTab - reports whether the action leaves another tab active
+func switchesTab(action *entities.Action) bool {
+	switch action.Type {
+	case entities.ActionPrevTab, entities.ActionCloseTab:
+		return true
+	case entities.ActionNavigate:
+		return action.NewTab
+	}
+	return false
+}
+
+// pauseStep - in step mode waits for Enter after every action, consuming exactly one line
+func (a *Agent) pauseStep(reader *bufio.Reader) {
+	if !a.stepMode {
+		return
+	}
+	a.out.Print(i18n.T(i18n.MsgStepPause))
+	if _, err := reader.ReadString('\n'); err != nil {
+		a.logger.Warnf("Failed to read step confirmation: %v", err)
+	}
+	a.out.Println()
+}
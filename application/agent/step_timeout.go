@@ -0,0 +1,41 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"ai_automation/domain/entities"
+	"ai_automation/domain/interfaces"
+)
+
+// executeStep - runs action with the per-step deadline. Browser calls check the context, so a timed
+// out action stops at its next call and returns before the next step touches the browser
+func (a *Agent) executeStep(ctx context.Context, action *entities.Action) *entities.ActionResult {
+	a.slowDown(ctx)
+	a.bringToFront(ctx)
+	if switchesTab(action) {
+		// The tab that becomes active is raised too
+		defer a.bringToFront(ctx)
+	}
+	if a.stepTimeout <= 0 {
+		return a.executeAction(ctx, action)
+	}
+
+	stepCtx, cancel := context.WithTimeout(ctx, a.stepTimeout)
+	defer cancel()
+
+	result := a.executeAction(stepCtx, action)
+	if result.Success || ctx.Err() != nil || !errors.Is(stepCtx.Err(), context.DeadlineExceeded) {
+		return result
+	}
+
+	a.logger.Warnf("Action %s timed out after %s", action.Type, a.stepTimeout)
+	result.Fail(&interfaces.BrowserError{
+		Kind:    interfaces.ErrTimeout,
+		Message: fmt.Sprintf("step timed out after %s", a.stepTimeout),
+		Err:     stepCtx.Err(),
+	})
+	result.Message = fmt.Sprintf("Action %s did not finish in time", action.Type)
+	return result
+}
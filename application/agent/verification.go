@@ -0,0 +1,31 @@
+package agent
+
+import (
+	"context"
+
+	"ai_automation/domain/entities"
+	"ai_automation/domain/i18n"
+)
+
+// maxCompletionRejections - after that many rejected completions the next one is accepted,
+// so a verifier that is never satisfied can't keep a finished task running until max iterations
+const maxCompletionRejections = 2
+
+// confirmCompletion - re-reads the page and asks the AI whether the task is really done. The check is
+// a safety net, so a failed extraction or verification call accepts the completion
+func (a *Agent) confirmCompletion(ctx context.Context, task *entities.Task, action *entities.Action) (bool, string) {
+	a.out.Println(i18n.T(i18n.MsgVerifyingCompletion))
+
+	pageInfo, err := a.browser.ExtractPageInfoWithin(ctx, "", 0)
+	if err != nil {
+		a.logger.Warnf("Failed to extract page for completion check, accepting completion: %v", err)
+		return true, ""
+	}
+
+	complete, reason, err := a.ai.VerifyCompletion(ctx, task, pageInfo, action.Text)
+	if err != nil {
+		a.logger.Warnf("Failed to verify completion, accepting it: %v", err)
+		return true, ""
+	}
+	return complete, reason
+}
@@ -0,0 +1,45 @@
+package agent
+
+import (
+	"ai_automation/domain/entities"
+	"ai_automation/domain/i18n"
+)
+
+// visitNoteLength - visit notes are cut to this many characters, they are repeated in every prompt
+const visitNoteLength = 120
+
+// recordArrival - counts the page as a new visit when the task just arrived at it, lastURL is the
+// visited URL of the previous step and the returned one replaces it
+func recordArrival(task *entities.Task, pageURL, lastURL string) string {
+	if pageURL == "" || pageURL == "about:blank" {
+		return lastURL
+	}
+	if visit := task.Visit(pageURL); visit != nil && visit.URL == lastURL {
+		return lastURL
+	}
+	return task.RecordVisit(pageURL).URL
+}
+
+// revisitLimitReached - the visit of a navigate target the task already returned to MAX_URL_REVISITS
+// times, nil when the navigation may go ahead
+func (a *Agent) revisitLimitReached(task *entities.Task, action *entities.Action) *entities.PageVisit {
+	if action.Type != entities.ActionNavigate || a.maxURLRevisits <= 0 {
+		return nil
+	}
+	if visit := task.Visit(action.URL); visit != nil && visit.Count > a.maxURLRevisits {
+		return visit
+	}
+	return nil
+}
+
+// visitNote - what the action did on the page, for the list of visited pages in the prompt
+func visitNote(action *entities.Action, result *entities.ActionResult) string {
+	note := getActionDescription(action)
+	if !result.Success {
+		note = i18n.T(i18n.MsgActionError, note, result.Error)
+	}
+	if runes := []rune(note); len(runes) > visitNoteLength {
+		note = string(runes[:visitNoteLength]) + "..."
+	}
+	return note
+}
@@ -0,0 +1,44 @@
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"ai_automation/domain/entities"
+	"ai_automation/testing/mocks"
+)
+
+func TestRevisitsAreCountedAndCapped(t *testing.T) {
+	t.Setenv("MAX_URL_REVISITS", "1")
+	browser := mocks.NewBrowser()
+	ai := mocks.NewAI(
+		&entities.Action{Type: entities.ActionNavigate, URL: "https://shop.example/list", Description: "open the list"},
+		&entities.Action{Type: entities.ActionNavigate, URL: "https://shop.example/item/1", Description: "open an item"},
+		&entities.Action{Type: entities.ActionNavigate, URL: "https://shop.example/list#top", Description: "back to the list"},
+		&entities.Action{Type: entities.ActionNavigate, URL: "https://shop.example/list/", Description: "reload the list"},
+	)
+	ag, _ := newTestAgent(browser, ai, mocks.NewSecurity())
+	task := &entities.Task{ID: "task", Description: "find a cheap item"}
+
+	if err := ag.ExecuteTask(context.Background(), task, input()); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+
+	// The fragment and trailing slash name the same page, the fourth navigation is one revisit too many
+	if n := browser.CallCount("Navigate"); n != 3 {
+		t.Errorf("navigated %d times, want 3", n)
+	}
+	list := task.Visit("https://shop.example/list")
+	if list == nil || list.Count != 2 || list.Note == "" {
+		t.Fatalf("visits = %+v, want the list visited twice with a note", task.Visits)
+	}
+	if item := task.Visit("https://shop.example/item/1"); item == nil || item.Count != 1 {
+		t.Errorf("visits = %+v, want the item visited once", task.Visits)
+	}
+
+	calls := ai.CallsTo("DecideNextAction")
+	history := calls[len(calls)-1].Args[2].([]entities.Action)
+	if len(history) != 4 || history[3].Error == "" {
+		t.Errorf("history = %+v, want the blocked navigation with an error", history)
+	}
+}
@@ -0,0 +1,399 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"ai_automation/domain/entities"
+)
+
+// Config - all agent settings, parsed from environment variables after the env files are loaded.
+// Invalid values keep their defaults and are listed in Warnings
+type Config struct {
+	// Lang is the output language (AGENT_LANG)
+	Lang     string
+	Log      LogConfig
+	AI       AIConfig
+	Browser  BrowserConfig
+	Agent    AgentConfig
+	Security SecurityConfig
+	// Files are the env files that were loaded, in load order
+	Files []string
+	// Warnings describe ignored invalid values
+	Warnings []string
+}
+
+// LogConfig - LOG_LEVEL and LOG_FORMAT, checked by the logger itself
+type LogConfig struct {
+	Level  string
+	Format string
+}
+
+// AIConfig - settings of the AI provider
+type AIConfig struct {
+	Provider      string
+	APIKey        string
+	Model         string
+	StrongModel   string
+	EscalateAfter int
+	// ValidateKey checks the key and models at startup
+	ValidateKey bool
+	// MaxPageContext limits page element characters per step, zero is unlimited
+	MaxPageContext int
+	// MaxTextContent limits page text sent for extraction, MaxTextPreview the text shown with each step
+	MaxTextContent int
+	MaxTextPreview int
+	// HistoryWindow is how many recent actions are listed in detail, older ones are counted in one line; zero lists all
+	HistoryWindow int
+	Temperature   float64
+	// TopP is nil when the API default is used
+	TopP *float64
+	// MaxTokens is zero when the API default is used
+	MaxTokens int
+	// Retries repeat calls failed by the network, rate limits or server errors, RetryJitter is
+	// the max random delay added to the doubling RetryBackoff
+	Retries      int
+	RetryBackoff time.Duration
+	RetryJitter  time.Duration
+	// BreakerThreshold failed calls in a row stop calls for BreakerCooldown, zero disables the breaker
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	Stream           bool
+	JSONMode         bool
+	SystemPrompt     string
+	SystemPromptMode string
+	// PromptTemplatePath is a text/template file overriding sections of the decision prompt
+	PromptTemplatePath string
+	Cache              bool
+	CacheTTL           time.Duration
+	JSEnabled          bool
+	// PDFEnabled offers save_pdf, which works only in a headless or remote browser
+	PDFEnabled     bool
+	AllowedActions entities.ActionSet
+}
+
+// BrowserConfig - settings of the browser and its WebDriver
+type BrowserConfig struct {
+	// Name is chrome, firefox or edge (BROWSER_NAME), other than chrome needs RemoteURL
+	Name string
+	// RemoteURL is a Selenium Grid / WebDriver endpoint used instead of a local ChromeDriver
+	RemoteURL            string
+	DriverPath           string
+	DriverPort           int
+	ChromeBinary         string
+	Proxy                string
+	UserAgent            string
+	Stealth              bool
+	HTTPCredentials      string
+	HTTPCredentialsHosts string
+	// DownloadDir is empty for ~/.ai_automation/downloads
+	DownloadDir string
+	// Persistent keeps the Chrome profile (logins, cookies) between runs, false uses a temporary one
+	Persistent bool
+	// Headless runs Chrome and Edge without a window (BROWSER_HEADLESS), needed for save_pdf
+	Headless    bool
+	MaxElements int
+	MaxLinks    int
+	MaxButtons  int
+	// MaxTextContent limits visible text extracted per page, in characters
+	MaxTextContent int
+	// ExtractAttributes are the lower-cased element attributes kept for the AI, "data-*" keeps a prefix
+	ExtractAttributes []string
+	// WaitStrategy is when a page counts as loaded: domcontentloaded, load or networkidle,
+	// the last one ignores websockets, long polls and analytics beacons
+	WaitStrategy string
+	// FindElementTimeout is how long element lookups poll for an element that is not there yet, zero looks once
+	FindElementTimeout time.Duration
+	RestoreLastURL     bool
+}
+
+// AgentConfig - settings of the task loop
+type AgentConfig struct {
+	CaptchaDetection bool
+	CaptureOnFailure bool
+	TaskPlanning     bool
+	// VerifyCompletion asks the AI to confirm a completed task against the page
+	VerifyCompletion bool
+	JSEnabled        bool
+	AllowedActions   entities.ActionSet
+	DismissBanners   bool
+	// MinNavigateInterval and MaxNavigationsPerDomain (per minute) are zero when disabled
+	MinNavigateInterval     time.Duration
+	MaxNavigationsPerDomain int
+	// MaxNoopIterations stops a task after that many steps that left the page unchanged, zero disables it
+	MaxNoopIterations int
+	// MaxURLRevisits is how many times navigate may return to a page the task already visited, zero disables the cap
+	MaxURLRevisits int
+	// StepTimeout and TaskTimeout are zero when disabled
+	StepTimeout  time.Duration
+	TaskTimeout  time.Duration
+	DialogPolicy string
+	StepMode     bool
+	SlowMo       time.Duration
+	// FocusWindow brings the browser window to the front before every action and after tab switches
+	FocusWindow bool
+	// ScreenshotDir is empty for ~/.ai_automation/screenshots
+	ScreenshotDir string
+}
+
+// SecurityConfig - approval settings
+type SecurityConfig struct {
+	// Policy is strict, normal or yolo
+	Policy string
+	// SensitiveDomains are lower-cased hosts where every action needs approval, subdomains included
+	SensitiveDomains []string
+}
+
+const (
+	defaultModel             = "gpt-4o-mini" // Cheap model by default, see OPENAI_MODEL_STRONG
+	defaultStrongModel       = "gpt-4o"
+	defaultEscalateAfter     = 2
+	defaultPageContext       = 12000
+	defaultTextContent       = 2000
+	defaultTextPreview       = 500
+	defaultHistoryWindow     = 15
+	defaultTemperature       = 0.7
+	defaultCacheTTL          = 24 * time.Hour
+	defaultRetries           = 2
+	defaultRetryBackoff      = time.Second
+	defaultRetryJitter       = 500 * time.Millisecond
+	defaultBreakerThreshold  = 5
+	defaultBreakerCooldown   = 30 * time.Second
+	defaultDriverPort        = 9515
+	defaultBrowserName       = "chrome"
+	defaultWaitStrategy      = "load"
+	defaultFindTimeout       = 3 * time.Second
+	defaultMaxNoopIterations = 5
+	defaultMaxURLRevisits    = 2
+	defaultSecurityPolicy    = "normal"
+	defaultDialogPolicy      = "ask"
+)
+
+// defaultExtractAttributes - attributes that tell the AI what an element is or where it leads
+var defaultExtractAttributes = []string{"id", "class", "name", "type", "data-*", "href", "aria-label", "title", "alt", "role", "placeholder"}
+
+// FromEnv - parses the current environment. Components read their section with it,
+// so values loaded from env files and ones set directly in the environment behave the same
+func FromEnv() *Config {
+	return parse(os.Getenv)
+}
+
+// parse - builds Config from lookup, which returns "" for unset variables
+func parse(lookup func(string) string) *Config {
+	p := &parser{lookup: lookup}
+	allowedActions := entities.ParseActionSet(lookup("ALLOWED_ACTIONS"))
+	jsEnabled := p.flag("ENABLE_JS_ACTION")
+	remoteURL := strings.TrimSpace(lookup("SELENIUM_REMOTE_URL"))
+	headless := p.flag("BROWSER_HEADLESS")
+	maxTextContent := p.positiveInt("MAX_TEXT_CONTENT", defaultTextContent)
+
+	cfg := &Config{
+		Lang: lookup("AGENT_LANG"),
+		Log: LogConfig{
+			Level:  lookup("LOG_LEVEL"),
+			Format: strings.ToLower(lookup("LOG_FORMAT")),
+		},
+		AI: AIConfig{
+			Provider:           lookup("AI_PROVIDER"),
+			APIKey:             lookup("OPENAI_API_KEY"),
+			Model:              p.str("OPENAI_MODEL", defaultModel),
+			StrongModel:        p.str("OPENAI_MODEL_STRONG", defaultStrongModel),
+			EscalateAfter:      p.positiveInt("OPENAI_ESCALATE_AFTER", defaultEscalateAfter),
+			ValidateKey:        p.flag("VALIDATE_API_KEY"),
+			MaxPageContext:     p.nonNegativeInt("MAX_PAGE_CONTEXT_CHARS", defaultPageContext),
+			MaxTextContent:     maxTextContent,
+			MaxTextPreview:     p.positiveInt("MAX_TEXT_PREVIEW", defaultTextPreview),
+			HistoryWindow:      p.nonNegativeInt("HISTORY_RECENT_ACTIONS", defaultHistoryWindow),
+			Temperature:        defaultTemperature,
+			MaxTokens:          p.positiveInt("OPENAI_MAX_TOKENS", 0),
+			Retries:            p.nonNegativeInt("OPENAI_MAX_RETRIES", defaultRetries),
+			RetryBackoff:       time.Duration(p.positiveInt("OPENAI_RETRY_BACKOFF_MS", int(defaultRetryBackoff/time.Millisecond))) * time.Millisecond,
+			RetryJitter:        time.Duration(p.nonNegativeInt("OPENAI_RETRY_JITTER_MS", int(defaultRetryJitter/time.Millisecond))) * time.Millisecond,
+			BreakerThreshold:   p.nonNegativeInt("AI_BREAKER_THRESHOLD", defaultBreakerThreshold),
+			BreakerCooldown:    time.Duration(p.positiveInt("AI_BREAKER_COOLDOWN_SECONDS", int(defaultBreakerCooldown/time.Second))) * time.Second,
+			Stream:             p.flag("OPENAI_STREAM"),
+			JSONMode:           p.flag("OPENAI_JSON_MODE"),
+			SystemPrompt:       strings.TrimSpace(lookup("AGENT_SYSTEM_PROMPT")),
+			SystemPromptMode:   strings.ToLower(lookup("AGENT_SYSTEM_PROMPT_MODE")),
+			PromptTemplatePath: lookup("PROMPT_TEMPLATE_PATH"),
+			Cache:              p.flag("AI_CACHE"),
+			CacheTTL:           time.Duration(p.positiveInt("AI_CACHE_TTL_MINUTES", int(defaultCacheTTL/time.Minute))) * time.Minute,
+			JSEnabled:          jsEnabled,
+			PDFEnabled:         headless || remoteURL != "",
+			AllowedActions:     allowedActions,
+		},
+		Browser: BrowserConfig{
+			Name:                 p.oneOf("BROWSER_NAME", defaultBrowserName, "chrome", "firefox", "edge"),
+			RemoteURL:            remoteURL,
+			DriverPath:           lookup("BROWSER_DRIVER_PATH"),
+			DriverPort:           p.port("CHROMEDRIVER_PORT", defaultDriverPort),
+			ChromeBinary:         lookup("CHROME_BINARY_PATH"),
+			Proxy:                lookup("BROWSER_PROXY"),
+			UserAgent:            lookup("BROWSER_USER_AGENT"),
+			Stealth:              p.flag("STEALTH"),
+			HTTPCredentials:      lookup("BROWSER_HTTP_CREDENTIALS"),
+			HTTPCredentialsHosts: lookup("BROWSER_HTTP_CREDENTIALS_HOSTS"),
+			DownloadDir:          lookup("DOWNLOAD_DIR"),
+			Persistent:           lookup("BROWSER_PERSISTENT") != "false",
+			Headless:             headless,
+			MaxElements:          p.positiveInt("MAX_ELEMENTS", 100),
+			MaxLinks:             p.positiveInt("MAX_LINKS", 100),
+			MaxButtons:           p.positiveInt("MAX_BUTTONS", 80),
+			MaxTextContent:       maxTextContent,
+			ExtractAttributes:    p.list("EXTRACT_ATTRIBUTES", defaultExtractAttributes),
+			WaitStrategy:         p.oneOf("WAIT_STRATEGY", defaultWaitStrategy, "domcontentloaded", "load", "networkidle"),
+			FindElementTimeout:   time.Duration(p.nonNegativeInt("FIND_ELEMENT_TIMEOUT_MS", int(defaultFindTimeout/time.Millisecond))) * time.Millisecond,
+			RestoreLastURL:       p.flag("RESTORE_LAST_URL"),
+		},
+		Agent: AgentConfig{
+			CaptchaDetection:        lookup("CAPTCHA_DETECTION") != "false",
+			CaptureOnFailure:        p.flag("CAPTURE_ON_FAILURE"),
+			TaskPlanning:            p.flag("TASK_PLANNING"),
+			VerifyCompletion:        p.flag("VERIFY_COMPLETION"),
+			JSEnabled:               jsEnabled,
+			AllowedActions:          allowedActions,
+			DismissBanners:          p.flag("AUTO_DISMISS_BANNERS"),
+			MinNavigateInterval:     time.Duration(p.positiveInt("MIN_NAVIGATE_INTERVAL_MS", 0)) * time.Millisecond,
+			MaxNavigationsPerDomain: p.positiveInt("MAX_NAVIGATIONS_PER_DOMAIN", 0),
+			MaxNoopIterations:       p.nonNegativeInt("MAX_NOOP_ITERATIONS", defaultMaxNoopIterations),
+			MaxURLRevisits:          p.nonNegativeInt("MAX_URL_REVISITS", defaultMaxURLRevisits),
+			StepTimeout:             time.Duration(p.positiveInt("STEP_TIMEOUT_SECONDS", 0)) * time.Second,
+			TaskTimeout:             time.Duration(p.positiveInt("TASK_TIMEOUT_SECONDS", 0)) * time.Second,
+			DialogPolicy:            p.oneOf("DIALOG_POLICY", defaultDialogPolicy, "accept", "dismiss", "ask"),
+			StepMode:                p.flag("STEP_MODE"),
+			SlowMo:                  time.Duration(p.positiveInt("SLOWMO_MS", 0)) * time.Millisecond,
+			FocusWindow:             p.flag("FOCUS_WINDOW"),
+			ScreenshotDir:           lookup("SCREENSHOT_DIR"),
+		},
+		Security: SecurityConfig{
+			Policy:           p.oneOf("SECURITY_POLICY", defaultSecurityPolicy, "strict", "normal", "yolo"),
+			SensitiveDomains: p.domains("SENSITIVE_DOMAINS"),
+		},
+	}
+
+	if value := lookup("OPENAI_TEMPERATURE"); value != "" {
+		if temperature, err := strconv.ParseFloat(value, 64); err == nil && temperature >= 0 && temperature <= 2 {
+			cfg.AI.Temperature = temperature
+		} else {
+			p.warnf("Ignoring invalid OPENAI_TEMPERATURE value: %s (expected 0-2)", value)
+		}
+	}
+	if value := lookup("OPENAI_TOP_P"); value != "" {
+		if topP, err := strconv.ParseFloat(value, 64); err == nil && topP > 0 && topP <= 1 {
+			cfg.AI.TopP = &topP
+		} else {
+			p.warnf("Ignoring invalid OPENAI_TOP_P value: %s (expected 0-1)", value)
+		}
+	}
+	p.fitContextWindow(cfg)
+
+	cfg.Warnings = p.warnings
+	return cfg
+}
+
+// parser - typed reads of environment values that collect warnings instead of failing
+type parser struct {
+	lookup   func(string) string
+	warnings []string
+}
+
+func (p *parser) warnf(format string, args ...interface{}) {
+	p.warnings = append(p.warnings, fmt.Sprintf(format, args...))
+}
+
+// str - returns the value or defaultValue when unset
+func (p *parser) str(name, defaultValue string) string {
+	if value := p.lookup(name); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// flag - reports name=true
+func (p *parser) flag(name string) bool {
+	return p.lookup(name) == "true"
+}
+
+// positiveInt - returns a value above zero, unset and invalid values keep defaultValue
+func (p *parser) positiveInt(name string, defaultValue int) int {
+	value := p.lookup(name)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		p.warnf("Ignoring invalid %s value: %s", name, value)
+		return defaultValue
+	}
+	return parsed
+}
+
+// nonNegativeInt - like positiveInt but zero is allowed
+func (p *parser) nonNegativeInt(name string, defaultValue int) int {
+	value := p.lookup(name)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		p.warnf("Ignoring invalid %s value: %s", name, value)
+		return defaultValue
+	}
+	return parsed
+}
+
+// port - positiveInt limited to the TCP port range
+func (p *parser) port(name string, defaultValue int) int {
+	port := p.positiveInt(name, defaultValue)
+	if port > 65535 {
+		p.warnf("Ignoring invalid %s value: %d", name, port)
+		return defaultValue
+	}
+	return port
+}
+
+// domains - parses a comma-separated list of hosts ("bank.com, *.pay.example"), lower-cased
+// and without wildcard prefixes
+func (p *parser) domains(name string) []string {
+	var hosts []string
+	for _, host := range strings.Split(p.lookup(name), ",") {
+		host = strings.ToLower(strings.TrimSpace(host))
+		host = strings.TrimPrefix(strings.TrimPrefix(host, "*"), ".")
+		if host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// list - parses a comma-separated list of names, lower-cased; unset returns defaults
+func (p *parser) list(name string, defaults []string) []string {
+	var values []string
+	for _, value := range strings.Split(p.lookup(name), ",") {
+		if value = strings.ToLower(strings.TrimSpace(value)); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return defaults
+	}
+	return values
+}
+
+// oneOf - returns the lower-cased value when it is one of allowed, otherwise defaultValue
+func (p *parser) oneOf(name, defaultValue string, allowed ...string) string {
+	value := strings.ToLower(strings.TrimSpace(p.lookup(name)))
+	if value == "" {
+		return defaultValue
+	}
+	for _, candidate := range allowed {
+		if value == candidate {
+			return value
+		}
+	}
+	p.warnf("Unknown %s %q, using %s", name, value, defaultValue)
+	return defaultValue
+}
@@ -0,0 +1,227 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"ai_automation/domain/entities"
+)
+
+// unsetEnv - clears variables for the test, Load then sets them and they are restored afterwards
+func unsetEnv(t *testing.T, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	unsetEnv(t, "OPENAI_MODEL", "AGENT_LANG", "SECURITY_POLICY", "LOG_LEVEL")
+	t.Setenv("LOG_FORMAT", "json")
+
+	writeFile(t, ".env", "OPENAI_MODEL=from-env\nAGENT_LANG=ru\nSECURITY_POLICY=strict\nLOG_LEVEL=debug\nLOG_FORMAT=text\n")
+	writeFile(t, ".env.local", "AGENT_LANG=en\nSECURITY_POLICY=yolo\n")
+	explicit := filepath.Join(dir, "prod.env")
+	writeFile(t, explicit, "SECURITY_POLICY=normal\n")
+
+	cfg, err := Load(explicit)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	tests := []struct {
+		name, got, want string
+	}{
+		{"only in .env", cfg.AI.Model, "from-env"},
+		{".env.local over .env", cfg.Lang, "en"},
+		{"--config over .env.local", cfg.Security.Policy, "normal"},
+		{"untouched by later files", cfg.Log.Level, "debug"},
+		{"process environment over files", cfg.Log.Format, "json"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+	if want := []string{".env", ".env.local", explicit}; len(cfg.Files) != len(want) || cfg.Files[2] != explicit {
+		t.Errorf("Files = %v, want %v", cfg.Files, want)
+	}
+	// Components parse the environment themselves, so loaded values must be visible there
+	if os.Getenv("SECURITY_POLICY") != "normal" {
+		t.Errorf("SECURITY_POLICY in environment = %q, want normal", os.Getenv("SECURITY_POLICY"))
+	}
+}
+
+func TestLoadWithoutFiles(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(cfg.Files) != 0 {
+		t.Errorf("Files = %v, want none", cfg.Files)
+	}
+
+	if _, err := Load("missing.env"); err == nil {
+		t.Error("Load succeeded with a missing --config file")
+	}
+}
+
+func TestParseSampleConfig(t *testing.T) {
+	sample := map[string]string{
+		"AI_PROVIDER":                 "openai",
+		"OPENAI_API_KEY":              "sk-test",
+		"OPENAI_MODEL":                "gpt-4o",
+		"OPENAI_ESCALATE_AFTER":       "3",
+		"OPENAI_TEMPERATURE":          "0.2",
+		"OPENAI_TOP_P":                "0.9",
+		"MAX_PAGE_CONTEXT_CHARS":      "0",
+		"AI_CACHE":                    "true",
+		"AI_CACHE_TTL_MINUTES":        "30",
+		"ALLOWED_ACTIONS":             "navigate,extract",
+		"CHROMEDRIVER_PORT":           "9600",
+		"MAX_LINKS":                   "20",
+		"STEALTH":                     "true",
+		"STEP_TIMEOUT_SECONDS":        "45",
+		"MIN_NAVIGATE_INTERVAL_MS":    "500",
+		"MAX_NAVIGATIONS_PER_DOMAIN":  "10",
+		"DIALOG_POLICY":               "Dismiss",
+		"CAPTCHA_DETECTION":           "false",
+		"SECURITY_POLICY":             " STRICT ",
+		"SENSITIVE_DOMAINS":           "MyBank.com, *.pay.example,",
+		"BROWSER_PERSISTENT":          "false",
+		"MAX_TEXT_CONTENT":            "5000",
+		"MAX_TEXT_PREVIEW":            "800",
+		"BROWSER_NAME":                "Edge",
+		"OPENAI_MAX_RETRIES":          "0",
+		"AI_BREAKER_COOLDOWN_SECONDS": "10",
+		"SELENIUM_REMOTE_URL":         "http://grid:4444/wd/hub",
+		"EXTRACT_ATTRIBUTES":          "href, Data-Track-*,",
+		"WAIT_STRATEGY":               "NetworkIdle",
+		"FIND_ELEMENT_TIMEOUT_MS":     "0",
+		"MAX_URL_REVISITS":            "4",
+	}
+	cfg := parse(func(name string) string { return sample[name] })
+
+	if len(cfg.Warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", cfg.Warnings)
+	}
+	if cfg.AI.Model != "gpt-4o" || cfg.AI.StrongModel != defaultStrongModel || cfg.AI.EscalateAfter != 3 {
+		t.Errorf("models = %s/%s after %d", cfg.AI.Model, cfg.AI.StrongModel, cfg.AI.EscalateAfter)
+	}
+	if cfg.AI.Temperature != 0.2 || cfg.AI.TopP == nil || *cfg.AI.TopP != 0.9 || cfg.AI.MaxTokens != 0 {
+		t.Errorf("sampling = %v/%v/%d", cfg.AI.Temperature, cfg.AI.TopP, cfg.AI.MaxTokens)
+	}
+	if cfg.AI.MaxPageContext != 0 || !cfg.AI.Cache || cfg.AI.CacheTTL != 30*time.Minute {
+		t.Errorf("context/cache = %d/%v/%s", cfg.AI.MaxPageContext, cfg.AI.Cache, cfg.AI.CacheTTL)
+	}
+	if !cfg.Agent.AllowedActions.Allows(entities.ActionNavigate) || cfg.Agent.AllowedActions.Allows(entities.ActionClick) {
+		t.Errorf("allowed actions = %s", cfg.Agent.AllowedActions)
+	}
+	if cfg.Browser.DriverPort != 9600 || cfg.Browser.MaxLinks != 20 || cfg.Browser.MaxElements != 100 || !cfg.Browser.Stealth || cfg.Browser.Persistent {
+		t.Errorf("browser = %+v", cfg.Browser)
+	}
+	if cfg.Agent.StepTimeout != 45*time.Second || cfg.Agent.TaskTimeout != 0 ||
+		cfg.Agent.MinNavigateInterval != 500*time.Millisecond || cfg.Agent.MaxNavigationsPerDomain != 10 {
+		t.Errorf("agent limits = %+v", cfg.Agent)
+	}
+	if cfg.Agent.MaxURLRevisits != 4 || cfg.Agent.MaxNoopIterations != defaultMaxNoopIterations {
+		t.Errorf("loop limits = %d revisits, %d noops", cfg.Agent.MaxURLRevisits, cfg.Agent.MaxNoopIterations)
+	}
+	if cfg.Agent.DialogPolicy != "dismiss" || cfg.Agent.CaptchaDetection || cfg.Security.Policy != "strict" {
+		t.Errorf("policies = %s/%v/%s", cfg.Agent.DialogPolicy, cfg.Agent.CaptchaDetection, cfg.Security.Policy)
+	}
+	if cfg.AI.Retries != 0 || cfg.AI.RetryBackoff != time.Second || cfg.AI.BreakerThreshold != 5 || cfg.AI.BreakerCooldown != 10*time.Second {
+		t.Errorf("retries = %d/%s, breaker = %d/%s", cfg.AI.Retries, cfg.AI.RetryBackoff, cfg.AI.BreakerThreshold, cfg.AI.BreakerCooldown)
+	}
+	if attrs := cfg.Browser.ExtractAttributes; len(attrs) != 2 || attrs[0] != "href" || attrs[1] != "data-track-*" {
+		t.Errorf("extract attributes = %v", attrs)
+	}
+	if cfg.Browser.WaitStrategy != "networkidle" || cfg.Browser.FindElementTimeout != 0 {
+		t.Errorf("wait strategy = %s, find timeout = %s", cfg.Browser.WaitStrategy, cfg.Browser.FindElementTimeout)
+	}
+	if cfg.Browser.Name != "edge" || cfg.Browser.RemoteURL != "http://grid:4444/wd/hub" {
+		t.Errorf("browser = %s at %s", cfg.Browser.Name, cfg.Browser.RemoteURL)
+	}
+	// A remote browser prints to PDF whether it has a window or not
+	if cfg.Browser.Headless || !cfg.AI.PDFEnabled {
+		t.Errorf("headless = %v, PDF enabled = %v", cfg.Browser.Headless, cfg.AI.PDFEnabled)
+	}
+	if cfg.AI.MaxTextContent != 5000 || cfg.Browser.MaxTextContent != 5000 || cfg.AI.MaxTextPreview != 800 {
+		t.Errorf("text limits = %d/%d/%d", cfg.AI.MaxTextContent, cfg.Browser.MaxTextContent, cfg.AI.MaxTextPreview)
+	}
+	if domains := cfg.Security.SensitiveDomains; len(domains) != 2 || domains[0] != "mybank.com" || domains[1] != "pay.example" {
+		t.Errorf("sensitive domains = %v", domains)
+	}
+}
+
+func TestParseInvalidValuesKeepDefaults(t *testing.T) {
+	sample := map[string]string{
+		"OPENAI_TEMPERATURE": "5",
+		"MAX_ELEMENTS":       "-1",
+		"CHROMEDRIVER_PORT":  "70000",
+		"SECURITY_POLICY":    "paranoid",
+	}
+	cfg := parse(func(name string) string { return sample[name] })
+
+	if cfg.AI.Temperature != defaultTemperature || cfg.Browser.MaxElements != 100 ||
+		cfg.Browser.DriverPort != defaultDriverPort || cfg.Security.Policy != defaultSecurityPolicy || !cfg.Browser.Persistent {
+		t.Errorf("invalid values were not replaced by defaults: %+v", cfg)
+	}
+	if len(cfg.Warnings) != len(sample) {
+		t.Errorf("warnings = %v, want one per invalid value", cfg.Warnings)
+	}
+}
+
+func TestTextLimitsFitContextWindow(t *testing.T) {
+	sample := map[string]string{
+		"OPENAI_MODEL":     "gpt-4",
+		"MAX_TEXT_CONTENT": "100000",
+		"MAX_TEXT_PREVIEW": "50000",
+	}
+	cfg := parse(func(name string) string { return sample[name] })
+
+	// Half of the 8192 token window at four characters per token
+	if cfg.AI.MaxTextContent != 16384 || cfg.Browser.MaxTextContent != 16384 || cfg.AI.MaxTextPreview != 16384 {
+		t.Errorf("text limits = %d/%d/%d, want 16384", cfg.AI.MaxTextContent, cfg.Browser.MaxTextContent, cfg.AI.MaxTextPreview)
+	}
+	if len(cfg.Warnings) != 2 {
+		t.Errorf("warnings = %v, want one per lowered limit", cfg.Warnings)
+	}
+
+	sample["OPENAI_MODEL"] = "local-llama"
+	cfg = parse(func(name string) string { return sample[name] })
+	if cfg.AI.MaxTextContent != 100000 {
+		t.Errorf("limit of an unknown model was changed to %d", cfg.AI.MaxTextContent)
+	}
+}
+
+func TestSavePDFNeedsHeadlessOrRemoteBrowser(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want bool
+	}{
+		{map[string]string{}, false},
+		{map[string]string{"BROWSER_HEADLESS": "true"}, true},
+		{map[string]string{"SELENIUM_REMOTE_URL": "http://grid:4444/wd/hub"}, true},
+	}
+	for _, tt := range tests {
+		cfg := parse(func(name string) string { return tt.env[name] })
+		if cfg.AI.PDFEnabled != tt.want || cfg.Browser.Headless != (tt.env["BROWSER_HEADLESS"] == "true") {
+			t.Errorf("%v: PDF enabled = %v, headless = %v", tt.env, cfg.AI.PDFEnabled, cfg.Browser.Headless)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package config
+
+import "strings"
+
+// charsPerToken - rough size of a token in characters, enough to compare limits with a context window
+const charsPerToken = 4
+
+// contextWindows - context window in tokens by model name prefix, more specific prefixes first
+var contextWindows = []struct {
+	prefix string
+	tokens int
+}{
+	{"gpt-4.1", 1047576},
+	{"gpt-4o", 128000},
+	{"gpt-4-turbo", 128000},
+	{"gpt-4", 8192},
+	{"gpt-3.5-turbo", 16385},
+	{"o1", 200000},
+	{"o3", 200000},
+	{"o4", 200000},
+}
+
+// contextWindow - context window of the model in tokens, zero when the model is unknown
+func contextWindow(model string) int {
+	model = strings.ToLower(model)
+	for _, window := range contextWindows {
+		if strings.HasPrefix(model, window.prefix) {
+			return window.tokens
+		}
+	}
+	return 0
+}
+
+// fitContextWindow - lowers text limits that would not fit the model context. Page text may take
+// at most half of the window, the rest is left for elements, history and the answer
+func (p *parser) fitContextWindow(cfg *Config) {
+	if window := contextWindow(cfg.AI.Model); window > 0 {
+		budget := window * charsPerToken / 2
+		if cfg.AI.MaxTextContent > budget {
+			p.warnf("MAX_TEXT_CONTENT %d does not fit the %s context window, using %d", cfg.AI.MaxTextContent, cfg.AI.Model, budget)
+			cfg.AI.MaxTextContent = budget
+			cfg.Browser.MaxTextContent = budget
+		}
+	}
+	if cfg.AI.MaxTextPreview > cfg.AI.MaxTextContent {
+		p.warnf("MAX_TEXT_PREVIEW %d exceeds MAX_TEXT_CONTENT, using %d", cfg.AI.MaxTextPreview, cfg.AI.MaxTextContent)
+		cfg.AI.MaxTextPreview = cfg.AI.MaxTextContent
+	}
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/joho/godotenv"
+)
+
+// defaultEnvFiles - env files read from the working directory, later ones override earlier
+var defaultEnvFiles = []string{".env", ".env.local"}
+
+// Load - reads .env, .env.local and then the explicit config file (--config) into the environment
+// and parses the result. Precedence from highest: variables already set in the process environment,
+// the explicit config file, .env.local, .env. Missing default files are skipped, a missing explicit
+// file is an error
+func Load(path string) (*Config, error) {
+	values := map[string]string{}
+	var files []string
+
+	for _, file := range defaultEnvFiles {
+		loaded, err := readEnvFile(file, values)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		if loaded {
+			files = append(files, file)
+		}
+	}
+	if path != "" {
+		if _, err := readEnvFile(path, values); err != nil {
+			return nil, err
+		}
+		files = append(files, path)
+	}
+
+	for key, value := range values {
+		if _, set := os.LookupEnv(key); set {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return nil, fmt.Errorf("failed to set %s: %w", key, err)
+		}
+	}
+
+	cfg := FromEnv()
+	cfg.Files = files
+	return cfg, nil
+}
+
+// readEnvFile - merges variables of the file into values, overriding ones from earlier files
+func readEnvFile(path string, values map[string]string) (bool, error) {
+	if _, err := os.Stat(path); err != nil {
+		return false, fmt.Errorf("config file %s: %w", path, err)
+	}
+	fileValues, err := godotenv.Read(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	for key, value := range fileValues {
+		values[key] = value
+	}
+	return true, nil
+}
@@ -4,13 +4,42 @@ package entities
 type ActionType string
 
 const (
-	ActionNavigate   ActionType = "navigate"
-	ActionClick      ActionType = "click"
-	ActionTypeText   ActionType = "type"
-	ActionExtract    ActionType = "extract"
-	ActionWait       ActionType = "wait"
-	ActionScroll     ActionType = "scroll"
-	ActionScreenshot ActionType = "screenshot"
+	ActionNavigate    ActionType = "navigate"
+	ActionClick       ActionType = "click"
+	ActionTypeText    ActionType = "type"
+	ActionSetDate     ActionType = "set_date"
+	ActionSetChecked  ActionType = "set_checked"
+	ActionSelect      ActionType = "select_option"
+	ActionGetOptions  ActionType = "get_select_options"
+	ActionExtract     ActionType = "extract"
+	ActionWait        ActionType = "wait"
+	ActionScroll      ActionType = "scroll"
+	ActionScreenshot  ActionType = "screenshot"
+	ActionSavePDF     ActionType = "save_pdf"
+	ActionCopy        ActionType = "copy"
+	ActionPaste       ActionType = "paste"
+	ActionReadElement ActionType = "read_element"
+	ActionComplete    ActionType = "complete"
+	ActionAskUser     ActionType = "ask_user"
+	ActionExtractData ActionType = "extract_data"
+	ActionRightClick  ActionType = "right_click"
+	ActionCount       ActionType = "count_elements"
+	ActionClickAt     ActionType = "click_at"
+	ActionWaitLoad    ActionType = "wait_for_navigation"
+	ActionFindByRole  ActionType = "find_by_role"
+	ActionFindNear    ActionType = "find_near_text"
+	ActionSubtaskDone ActionType = "complete_subtask"
+	ActionMoreItems   ActionType = "more_elements"
+	ActionExecuteJS   ActionType = "execute_js"
+	ActionDownload    ActionType = "download_file"
+	ActionWaitText    ActionType = "wait_for_text"
+	ActionWaitStable  ActionType = "wait_for_stable"
+	ActionCloseTab    ActionType = "close_tab"
+	ActionPrevTab     ActionType = "previous_tab"
+	ActionFocus       ActionType = "focus_on"
+	ActionSelectText  ActionType = "select_text"
+	ActionGetSelected ActionType = "get_selection"
+	ActionLoadAll     ActionType = "load_all"
 )
 
 // Action represents a single action the agent wants to perform
@@ -19,8 +48,31 @@ type Action struct {
 	Selector         string     `json:"selector,omitempty"`
 	Text             string     `json:"text,omitempty"`
 	URL              string     `json:"url,omitempty"`
+	Attribute        string     `json:"attribute,omitempty"`
+	Role             string     `json:"role,omitempty"`
+	X                int        `json:"x,omitempty"`
+	Y                int        `json:"y,omitempty"`
 	Description      string     `json:"description"`
 	RequiresApproval bool       `json:"requires_approval,omitempty"`
+	// Append types after the current value of the field instead of clearing it first
+	Append bool `json:"append,omitempty"`
+	// Values are the options select_option selects, several for a multi-select
+	Values []string `json:"values,omitempty"`
+	// Uncheck makes set_checked clear the checkbox instead of checking it
+	Uncheck bool `json:"uncheck,omitempty"`
+	// NewTab opens the navigate URL in a new tab, keeping the current page open
+	NewTab bool `json:"new_tab,omitempty"`
+	// Direction (up or down) and Amount in pixels of a scroll, empty and zero use the defaults
+	Direction string `json:"direction,omitempty"`
+	Amount    int    `json:"amount,omitempty"`
+	// Timeout of a wait in seconds, zero uses the default
+	Timeout int `json:"timeout,omitempty"`
+	// FollowUps are further actions the AI asked for in the same response, run in order after this one
+	FollowUps []Action `json:"follow_ups,omitempty"`
+	// Result is the data the executed action returned (read value, extracted JSON), shown to the AI in history
+	Result string `json:"-"`
+	// Error is set when the action failed, so the AI knows not to repeat it blindly
+	Error string `json:"-"`
 }
 
 // ActionResult represents the result of an action
@@ -30,4 +82,12 @@ type ActionResult struct {
 	Data     string    `json:"data,omitempty"`
 	Error    string    `json:"error,omitempty"`
 	PageInfo *PageInfo `json:"page_info,omitempty"`
+	// Err is the error behind Error, kept so callers can match its kind with errors.Is
+	Err error `json:"-"`
+}
+
+// Fail - records err as the reason the action failed
+func (r *ActionResult) Fail(err error) {
+	r.Err = err
+	r.Error = err.Error()
 }
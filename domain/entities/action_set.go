@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"sort"
+	"strings"
+)
+
+// ActionSet represents action types allowed to run, nil allows every action
+type ActionSet map[ActionType]bool
+
+// controlActions only steer the agent and never touch the page, so they are always allowed
+var controlActions = map[ActionType]bool{
+	ActionComplete:    true,
+	ActionAskUser:     true,
+	ActionSubtaskDone: true,
+	ActionMoreItems:   true,
+	ActionFocus:       true,
+}
+
+// ParseActionSet parses a comma-separated list of action types ("navigate,extract").
+// The type_text tool name is accepted for the type action. Empty input returns nil
+func ParseActionSet(value string) ActionSet {
+	set := ActionSet{}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if name == "type_text" {
+			name = string(ActionTypeText)
+		}
+		set[ActionType(name)] = true
+	}
+	if len(set) == 0 {
+		return nil
+	}
+	return set
+}
+
+// Allows reports whether actions of the type may run
+func (s ActionSet) Allows(actionType ActionType) bool {
+	return s == nil || s[actionType] || controlActions[actionType]
+}
+
+// String returns the allowed types sorted and comma-separated
+func (s ActionSet) String() string {
+	names := make([]string, 0, len(s))
+	for actionType := range s {
+		names = append(names, string(actionType))
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,13 @@
+package entities
+
+import "time"
+
+// LogEntry represents a console message or an uncaught error of the page
+type LogEntry struct {
+	// Level is error, warning, info or debug
+	Level   string `json:"level"`
+	Message string `json:"message"`
+	// Source is the script location (url line:column) when the browser reports one
+	Source string    `json:"source,omitempty"`
+	Time   time.Time `json:"time"`
+}
@@ -1,17 +1,24 @@
 package entities
 
+import "strings"
+
 // PageElement represents a single element on the page
 type PageElement struct {
-	TagName      string            `json:"tag_name"`
-	Text         string            `json:"text"`
-	Placeholder  string            `json:"placeholder,omitempty"`
-	Value        string            `json:"value,omitempty"`
-	Attributes   map[string]string `json:"attributes"`
-	Selector     string            `json:"selector"`
-	AllSelectors []string          `json:"all_selectors,omitempty"`
-	IsVisible    bool              `json:"is_visible"`
-	IsClickable  bool              `json:"is_clickable"`
-	XPath        string            `json:"xpath,omitempty"`
+	TagName        string            `json:"tag_name"`
+	Text           string            `json:"text"`
+	Placeholder    string            `json:"placeholder,omitempty"`
+	Value          string            `json:"value,omitempty"`
+	Attributes     map[string]string `json:"attributes"`
+	Selector       string            `json:"selector"`
+	AllSelectors   []string          `json:"all_selectors,omitempty"`
+	Role           string            `json:"role,omitempty"`
+	AccessibleName string            `json:"accessible_name,omitempty"`
+	IsVisible      bool              `json:"is_visible"`
+	IsClickable    bool              `json:"is_clickable"`
+	// IsDisabled is set for disabled controls (disabled attribute, aria-disabled, disabled fieldset),
+	// an element extracted without the flag counts as enabled
+	IsDisabled     bool              `json:"is_disabled,omitempty"`
+	XPath          string            `json:"xpath,omitempty"`
 }
 
 // PageInfo represents structured information about the current page
@@ -24,6 +31,33 @@ type PageInfo struct {
 	Links       []LinkInfo     `json:"links"`
 	Forms       []FormInfo     `json:"forms"`
 	Buttons     []PageElement  `json:"buttons"`
+	// Page is the index of the returned slice of elements, links and buttons
+	Page        int            `json:"page,omitempty"`
+	// HasMore reports that the page has more elements than were returned
+	HasMore     bool           `json:"has_more,omitempty"`
+	// Frames lists src URLs of iframes on the page
+	Frames      []string       `json:"frames,omitempty"`
+	// Scope is the selector of the container extraction was limited to, empty for the whole page
+	Scope       string         `json:"scope,omitempty"`
+}
+
+// FrameInfo represents a frame of the page frame tree
+type FrameInfo struct {
+	// Name is the frame name attribute, or its id when unnamed
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url"`
+	// SameOrigin reports the frame content is readable from the top page
+	SameOrigin bool `json:"same_origin"`
+	// Depth is 1 for frames of the top page, nested frames of same-origin frames go deeper
+	Depth int `json:"depth"`
+}
+
+// BoundingBox represents element position and size in viewport CSS pixels
+type BoundingBox struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
 }
 
 // LinkInfo represents a link on the page
@@ -49,5 +83,46 @@ type InputInfo struct {
 	Placeholder string `json:"placeholder,omitempty"`
 	Label       string `json:"label,omitempty"`
 	Value       string `json:"value,omitempty"`
+	// Options are the visible texts of a select list's options
+	Options []string `json:"options,omitempty"`
 }
 
+
+// RemoveDuplicates drops elements listed in more than one category, matched by selector and text.
+// Links are kept as links (they carry the URL), then buttons, and Elements keeps only what is left.
+// Items without a selector can't be told apart and are always kept
+func (p *PageInfo) RemoveDuplicates() {
+	seen := map[string]bool{}
+	key := func(selector, text string) string {
+		if selector == "" {
+			return ""
+		}
+		return selector + "\x00" + strings.Join(strings.Fields(text), " ")
+	}
+
+	for _, link := range p.Links {
+		if k := key(link.Selector, link.Text); k != "" {
+			seen[k] = true
+		}
+	}
+
+	buttons := p.Buttons[:0]
+	for _, button := range p.Buttons {
+		if k := key(button.Selector, button.Text); k != "" {
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+		}
+		buttons = append(buttons, button)
+	}
+	p.Buttons = buttons
+
+	elements := p.Elements[:0]
+	for _, elem := range p.Elements {
+		if k := key(elem.Selector, elem.Text); k == "" || !seen[k] {
+			elements = append(elements, elem)
+		}
+	}
+	p.Elements = elements
+}
@@ -1,5 +1,10 @@
 package entities
 
+import (
+	"strings"
+	"time"
+)
+
 // Task represents a user task
 type Task struct {
 	ID          string   `json:"id"`
@@ -7,6 +12,77 @@ type Task struct {
 	Status      TaskStatus `json:"status"`
 	Actions     []Action `json:"actions,omitempty"`
 	Context     string   `json:"context,omitempty"`
+	// StartURL is opened before the first decision, so the AI does not spend a step on it
+	StartURL string `json:"start_url,omitempty"`
+	Result      string   `json:"result,omitempty"`
+	Subtasks    []Subtask `json:"subtasks,omitempty"`
+	// Deadline caps total wall-clock time of the task, zero means no limit
+	Deadline    time.Time `json:"deadline,omitempty"`
+	// Visits are the pages the task arrived at, in order of the first arrival
+	Visits []PageVisit `json:"visits,omitempty"`
+}
+
+// PageVisit - a page the task arrived at and what was last done there
+type PageVisit struct {
+	URL string `json:"url"`
+	// Count is the number of arrivals, staying on the page is one visit
+	Count int `json:"count"`
+	// Note briefly tells what the last action on the page did
+	Note string `json:"note,omitempty"`
+}
+
+// Subtask represents one step of the task plan
+type Subtask struct {
+	Description string `json:"description"`
+	Done        bool   `json:"done"`
+}
+
+// CurrentSubtask returns index of the first unfinished subtask, -1 if there is none
+func (t *Task) CurrentSubtask() int {
+	for i, subtask := range t.Subtasks {
+		if !subtask.Done {
+			return i
+		}
+	}
+	return -1
+}
+
+// CompleteCurrentSubtask marks the current subtask as done and returns it, nil if all are done
+func (t *Task) CompleteCurrentSubtask() *Subtask {
+	index := t.CurrentSubtask()
+	if index < 0 {
+		return nil
+	}
+	t.Subtasks[index].Done = true
+	return &t.Subtasks[index]
+}
+
+// RecordVisit counts an arrival at url and returns its visit. The fragment is ignored,
+// so jumping to an anchor of the same page is not a new page
+func (t *Task) RecordVisit(url string) *PageVisit {
+	if visit := t.Visit(url); visit != nil {
+		visit.Count++
+		return visit
+	}
+	t.Visits = append(t.Visits, PageVisit{URL: visitKey(url), Count: 1})
+	return &t.Visits[len(t.Visits)-1]
+}
+
+// Visit returns the visit of url, nil if the task has not been there
+func (t *Task) Visit(url string) *PageVisit {
+	key := visitKey(url)
+	for i := range t.Visits {
+		if t.Visits[i].URL == key {
+			return &t.Visits[i]
+		}
+	}
+	return nil
+}
+
+// visitKey - url without the fragment and a trailing slash
+func visitKey(url string) string {
+	url, _, _ = strings.Cut(url, "#")
+	return strings.TrimSuffix(url, "/")
 }
 
 // TaskStatus represents the status of a task
@@ -18,5 +94,9 @@ const (
 	TaskStatusCompleted TaskStatus = "completed"
 	TaskStatusFailed    TaskStatus = "failed"
 	TaskStatusWaiting   TaskStatus = "waiting_user_input"
+	TaskStatusCancelled TaskStatus = "cancelled"
+	TaskStatusTimedOut  TaskStatus = "timed_out"
+	// TaskStatusNoProgress - the page stopped changing for MAX_NOOP_ITERATIONS steps
+	TaskStatusNoProgress TaskStatus = "no_progress"
 )
 
@@ -0,0 +1,705 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Language represents supported output language
+type Language string
+
+const (
+	LanguageRussian Language = "ru"
+	LanguageEnglish Language = "en"
+)
+
+// MessageID identifies a localized message
+type MessageID string
+
+const (
+	// Agent progress messages
+	MsgTaskHeader          MessageID = "task_header"
+	MsgStartingWork        MessageID = "starting_work"
+	MsgAnalyzingPage       MessageID = "analyzing_page"
+	MsgPageAnalysisError   MessageID = "page_analysis_error"
+	MsgCurrentPage         MessageID = "current_page"
+	MsgDecidingAction      MessageID = "deciding_action"
+	MsgQueuedAction        MessageID = "queued_action"
+	MsgDecisionError       MessageID = "decision_error"
+	MsgApprovalRequired    MessageID = "approval_required"
+	MsgApprovalAction      MessageID = "approval_action"
+	MsgApprovalDescription MessageID = "approval_description"
+	MsgApprovalWarning     MessageID = "approval_warning"
+	MsgApprovalPrompt      MessageID = "approval_prompt"
+	MsgActionApproved      MessageID = "action_approved"
+	MsgActionRejected      MessageID = "action_rejected"
+	MsgExecutingAction     MessageID = "executing_action"
+	MsgActionError         MessageID = "action_error"
+	MsgTryingAnotherWay    MessageID = "trying_another_way"
+	MsgElementMissing      MessageID = "element_missing"
+	MsgActionNotAllowed    MessageID = "action_not_allowed"
+	MsgRevisitBlocked      MessageID = "revisit_blocked"
+	MsgBannerDismissed     MessageID = "banner_dismissed"
+	MsgMaxIterations       MessageID = "max_iterations"
+	MsgNoProgress          MessageID = "no_progress"
+	MsgBrowserClosed       MessageID = "browser_closed"
+	MsgTaskInterrupted     MessageID = "task_interrupted"
+	MsgTaskTimedOut        MessageID = "task_timed_out"
+	MsgLoginDetected       MessageID = "login_detected"
+	MsgLoginInstructions   MessageID = "login_instructions"
+	MsgPressEnter          MessageID = "press_enter"
+	MsgTaskSummary         MessageID = "task_summary"
+	MsgAskUserQuestion     MessageID = "ask_user_question"
+	MsgAskUserPrompt       MessageID = "ask_user_prompt"
+	MsgVerifyingCompletion MessageID = "verifying_completion"
+	MsgCompletionRejected  MessageID = "completion_rejected"
+
+	// Action descriptions
+	MsgActionNavigate    MessageID = "action_navigate"
+	MsgActionNewTab      MessageID = "action_new_tab"
+	MsgActionClick       MessageID = "action_click"
+	MsgActionType        MessageID = "action_type"
+	MsgActionSetDate     MessageID = "action_set_date"
+	MsgActionCheck       MessageID = "action_check"
+	MsgActionUncheck     MessageID = "action_uncheck"
+	MsgActionSelect      MessageID = "action_select"
+	MsgActionGetOptions  MessageID = "action_get_options"
+	MsgActionScroll      MessageID = "action_scroll"
+	MsgActionExtract     MessageID = "action_extract"
+	MsgActionWait        MessageID = "action_wait"
+	MsgActionCopy        MessageID = "action_copy"
+	MsgActionPaste       MessageID = "action_paste"
+	MsgActionReadElement MessageID = "action_read_element"
+	MsgActionRightClick  MessageID = "action_right_click"
+	MsgActionCount       MessageID = "action_count"
+	MsgActionClickAt     MessageID = "action_click_at"
+	MsgActionWaitLoad    MessageID = "action_wait_load"
+	MsgActionFindByRole  MessageID = "action_find_by_role"
+	MsgActionFindNear    MessageID = "action_find_near"
+	MsgTaskPlan          MessageID = "task_plan"
+	MsgTaskPlanStep      MessageID = "task_plan_step"
+	MsgSubtaskCompleted  MessageID = "subtask_completed"
+	MsgMoreElements      MessageID = "more_elements"
+	MsgActionExecuteJS   MessageID = "action_execute_js"
+	MsgExecuteJSSuccess  MessageID = "execute_js_success"
+	MsgActionDownload    MessageID = "action_download"
+	MsgDownloadSuccess   MessageID = "download_success"
+	MsgActionWaitText    MessageID = "action_wait_text"
+	MsgWaitTextSuccess   MessageID = "wait_text_success"
+	MsgActionWaitStable  MessageID = "action_wait_stable"
+	MsgWaitStableSuccess MessageID = "wait_stable_success"
+	MsgDialogOpened      MessageID = "dialog_opened"
+	MsgDialogPrompt      MessageID = "dialog_prompt"
+	MsgDialogAccepted    MessageID = "dialog_accepted"
+	MsgDialogDismissed   MessageID = "dialog_dismissed"
+	MsgStepPause         MessageID = "step_pause"
+	MsgActionScreenshot  MessageID = "action_screenshot"
+	MsgScreenshotSaved   MessageID = "screenshot_saved"
+	MsgActionSavePDF     MessageID = "action_save_pdf"
+	MsgPDFSaved          MessageID = "pdf_saved"
+	MsgActionCloseTab    MessageID = "action_close_tab"
+	MsgActionPrevTab     MessageID = "action_prev_tab"
+	MsgTabClosed         MessageID = "tab_closed"
+	MsgTabSwitched       MessageID = "tab_switched"
+	MsgActionFocus       MessageID = "action_focus"
+	MsgFocusSet          MessageID = "focus_set"
+	MsgFocusCleared      MessageID = "focus_cleared"
+	MsgFocusNotFound     MessageID = "focus_not_found"
+	MsgActionSelectText  MessageID = "action_select_text"
+	MsgActionSelection   MessageID = "action_selection"
+	MsgActionLoadAll     MessageID = "action_load_all"
+	MsgCaptchaDetected   MessageID = "captcha_detected"
+	MsgCaptchaSolve      MessageID = "captcha_instructions"
+
+	// Action results
+	MsgNavigateSuccess    MessageID = "navigate_success"
+	MsgNewTabSuccess      MessageID = "new_tab_success"
+	MsgTabReused          MessageID = "tab_reused"
+	MsgClickSuccess       MessageID = "click_success"
+	MsgClickSubstituted   MessageID = "click_substituted"
+	MsgTypeSuccess        MessageID = "type_success"
+	MsgSetDateSuccess     MessageID = "set_date_success"
+	MsgCheckSuccess       MessageID = "check_success"
+	MsgUncheckSuccess     MessageID = "uncheck_success"
+	MsgSelectSuccess      MessageID = "select_success"
+	MsgOptionsSuccess     MessageID = "options_success"
+	MsgScrollSuccess      MessageID = "scroll_success"
+	MsgExtractSuccess     MessageID = "extract_success"
+	MsgWaitSuccess        MessageID = "wait_success"
+	MsgCopySuccess        MessageID = "copy_success"
+	MsgPasteSuccess       MessageID = "paste_success"
+	MsgReadElementSuccess MessageID = "read_element_success"
+	MsgSelectTextSuccess  MessageID = "select_text_success"
+	MsgSelectionSuccess   MessageID = "selection_success"
+	MsgLoadAllSuccess     MessageID = "load_all_success"
+	MsgExtractDataSuccess MessageID = "extract_data_success"
+	MsgRightClickSuccess  MessageID = "right_click_success"
+	MsgCountSuccess       MessageID = "count_success"
+	MsgClickAtSuccess     MessageID = "click_at_success"
+	MsgWaitLoadSuccess    MessageID = "wait_load_success"
+	MsgFindByRoleSuccess  MessageID = "find_by_role_success"
+	MsgFindNearSuccess    MessageID = "find_near_success"
+
+	// Terminal messages
+	MsgWelcomeTitle      MessageID = "welcome_title"
+	MsgWelcomeHint       MessageID = "welcome_hint"
+	MsgWelcomeCommands   MessageID = "welcome_commands"
+	MsgGoodbye           MessageID = "goodbye"
+	MsgRestorePrompt     MessageID = "restore_prompt"
+	MsgRestoreFailed     MessageID = "restore_failed"
+	MsgAnalyzeFailed     MessageID = "analyze_failed"
+	MsgAnalyzeDefault    MessageID = "analyze_default"
+	MsgStartingTask      MessageID = "starting_task"
+	MsgTaskCancelledExit MessageID = "task_cancelled_exit"
+	MsgTaskFailed        MessageID = "task_failed"
+	MsgTaskCompleted     MessageID = "task_completed"
+	MsgScriptSaved       MessageID = "script_saved"
+	MsgExportNoTask      MessageID = "export_no_task"
+	MsgExportFailed      MessageID = "export_failed"
+	MsgFramesHeader      MessageID = "frames_header"
+	MsgFramesNone        MessageID = "frames_none"
+	MsgFramesFailed      MessageID = "frames_failed"
+	MsgFrameCrossOrigin  MessageID = "frame_cross_origin"
+	MsgConsoleHeader     MessageID = "console_header"
+	MsgConsoleNone       MessageID = "console_none"
+	MsgConsoleFailed     MessageID = "console_failed"
+	MsgInspectHeader     MessageID = "inspect_header"
+	MsgInspectColumns    MessageID = "inspect_columns"
+	MsgInspectNone       MessageID = "inspect_none"
+	MsgInspectMore       MessageID = "inspect_more"
+	MsgInspectFailed     MessageID = "inspect_failed"
+	MsgReplayStart       MessageID = "replay_start"
+	MsgReplayStep        MessageID = "replay_step"
+	MsgReplayDone        MessageID = "replay_done"
+	MsgQueueStart        MessageID = "queue_start"
+	MsgQueueTask         MessageID = "queue_task"
+	MsgQueueSummary      MessageID = "queue_summary"
+	MsgProgressStep      MessageID = "progress_step"
+	MsgProgressTokens    MessageID = "progress_tokens"
+
+	// Prompt fragments sent to the AI
+	PromptVisibleText   MessageID = "prompt_visible_text"
+	PromptButtons       MessageID = "prompt_buttons"
+	PromptLinks         MessageID = "prompt_links"
+	PromptElements      MessageID = "prompt_elements"
+	PromptElementLine   MessageID = "prompt_element_line"
+	PromptTaggedLine    MessageID = "prompt_tagged_line"
+	PromptRelevantLine  MessageID = "prompt_relevant_line"
+	PromptRankedHeader  MessageID = "prompt_ranked_elements"
+	PromptScope         MessageID = "prompt_scope"
+	PromptNoText        MessageID = "prompt_no_text"
+	PromptDisabled      MessageID = "prompt_disabled"
+	PromptForms         MessageID = "prompt_forms"
+	PromptFormHeader    MessageID = "prompt_form_header"
+	PromptFormField     MessageID = "prompt_form_field"
+	PromptFormOptions   MessageID = "prompt_form_options"
+	PromptNoElements    MessageID = "prompt_no_elements"
+	PromptNoElementHint MessageID = "prompt_no_element_hint"
+	PromptNoHistory     MessageID = "prompt_no_history"
+	PromptHistoryResult MessageID = "prompt_history_result"
+	PromptHistoryError  MessageID = "prompt_history_error"
+	PromptEarlierSteps  MessageID = "prompt_earlier_steps"
+	PromptEarlierFailed MessageID = "prompt_earlier_failed"
+
+	// Action names used in history summary
+	HistoryNavigate    MessageID = "history_navigate"
+	HistoryClick       MessageID = "history_click"
+	HistoryType        MessageID = "history_type"
+	HistorySetDate     MessageID = "history_set_date"
+	HistorySetChecked  MessageID = "history_set_checked"
+	HistorySelect      MessageID = "history_select"
+	HistoryGetOptions  MessageID = "history_get_options"
+	HistoryScroll      MessageID = "history_scroll"
+	HistoryExtract     MessageID = "history_extract"
+	HistoryWait        MessageID = "history_wait"
+	HistoryCopy        MessageID = "history_copy"
+	HistoryPaste       MessageID = "history_paste"
+	HistoryReadElement MessageID = "history_read_element"
+	HistoryRightClick  MessageID = "history_right_click"
+	HistoryCount       MessageID = "history_count"
+	HistoryClickAt     MessageID = "history_click_at"
+	HistoryWaitLoad    MessageID = "history_wait_load"
+	HistoryFindByRole  MessageID = "history_find_by_role"
+	HistoryFindNear    MessageID = "history_find_near"
+	HistorySubtaskDone MessageID = "history_subtask_done"
+	HistoryMoreItems   MessageID = "history_more_items"
+	HistoryExecuteJS   MessageID = "history_execute_js"
+	HistoryDownload    MessageID = "history_download"
+	HistoryWaitText    MessageID = "history_wait_text"
+	HistoryWaitStable  MessageID = "history_wait_stable"
+	HistoryScreenshot  MessageID = "history_screenshot"
+	HistorySavePDF     MessageID = "history_save_pdf"
+	HistoryCloseTab    MessageID = "history_close_tab"
+	HistoryPrevTab     MessageID = "history_prev_tab"
+	HistoryFocus       MessageID = "history_focus"
+	HistorySelectText  MessageID = "history_select_text"
+	HistorySelection   MessageID = "history_selection"
+	HistoryLoadAll     MessageID = "history_load_all"
+	HistoryComplete    MessageID = "history_complete"
+)
+
+var messages = map[Language]map[MessageID]string{
+	LanguageRussian: {
+		MsgTaskHeader:          "Задача: %s",
+		MsgStartingWork:        "Начинаю работу...",
+		MsgAnalyzingPage:       "Анализирую текущую страницу...",
+		MsgPageAnalysisError:   "Ошибка при анализе страницы: %v",
+		MsgCurrentPage:         "Текущая страница: %s",
+		MsgDecidingAction:      "Определяю следующее действие...",
+		MsgQueuedAction:        "Следующее действие из того же ответа ИИ...",
+		MsgDecisionError:       "Ошибка при определении действия: %v",
+		MsgApprovalRequired:    "ВНИМАНИЕ: Требуется подтверждение деструктивного действия!",
+		MsgApprovalAction:      "Действие: %s",
+		MsgApprovalDescription: "Описание: %s",
+		MsgApprovalWarning:     "Это действие может быть необратимым (удаление, оплата и т.д.)",
+		MsgApprovalPrompt:      "Введите 'продолжить' или 'подтвердить' для выполнения, или 'отмена' для отмены: ",
+		MsgActionApproved:      "Действие подтверждено, продолжаю...",
+		MsgActionRejected:      "Действие отменено пользователем",
+		MsgExecutingAction:     "Выполняю действие: %s",
+		MsgActionError:         "Ошибка: %s - %s",
+		MsgTryingAnotherWay:    "Попробую другой подход...",
+		MsgElementMissing:      "Элемент %s не найден на странице, выбираю другой...",
+		MsgActionNotAllowed:    "Действие %s запрещено настройкой ALLOWED_ACTIONS, пропускаю",
+		MsgRevisitBlocked:      "Страница %s уже открывалась %d раз, не перехожу на неё снова",
+		MsgBannerDismissed:     "Закрыл баннер cookie: %s",
+		MsgMaxIterations:       "Достигнуто максимальное количество итераций (%d)",
+		MsgNoProgress:          "Страница не меняется уже %d шагов подряд, останавливаю задачу",
+		MsgBrowserClosed:       "Браузер закрыт, продолжить задачу невозможно",
+		MsgTaskInterrupted:     "Выполнение задачи прервано",
+		MsgTaskTimedOut:        "Превышено время выполнения задачи",
+		MsgLoginDetected:       "Обнаружена страница входа.",
+		MsgLoginInstructions:   "Пожалуйста, войдите в аккаунт вручную в открытом браузере.",
+		MsgPressEnter:          "Нажмите Enter, когда будете готовы продолжить: ",
+		MsgTaskSummary:         "Итог: %s",
+		MsgAskUserQuestion:     "Агенту нужно уточнение: %s",
+		MsgAskUserPrompt:       "Ваш ответ: ",
+		MsgVerifyingCompletion: "Проверяю, действительно ли задача выполнена...",
+		MsgCompletionRejected:  "Задача ещё не выполнена: %s. Продолжаю",
+
+		MsgActionNavigate:    "Переход на страницу: %s",
+		MsgActionNewTab:      "Открытие в новой вкладке: %s",
+		MsgActionClick:       "Клик на элемент: %s",
+		MsgActionType:        "Ввод текста '%s' в поле: %s",
+		MsgActionSetDate:     "Выбор даты %s в поле: %s",
+		MsgActionCheck:       "Отметить: %s",
+		MsgActionUncheck:     "Снять отметку: %s",
+		MsgActionSelect:      "Выбор %s в списке: %s",
+		MsgActionGetOptions:  "Получение вариантов списка: %s",
+		MsgActionScroll:      "Прокрутка страницы",
+		MsgActionExtract:     "Извлечение информации со страницы",
+		MsgActionWait:        "Ожидание",
+		MsgActionCopy:        "Копирование текста в буфер обмена",
+		MsgActionPaste:       "Вставка из буфера обмена в поле: %s",
+		MsgActionReadElement: "Чтение элемента: %s",
+		MsgActionRightClick:  "Правый клик на элемент: %s",
+		MsgActionCount:       "Подсчет элементов: %s",
+		MsgActionClickAt:     "Клик по координатам (%d, %d)",
+		MsgActionWaitLoad:    "Ожидание загрузки страницы",
+		MsgActionFindByRole:  "Поиск элементов с ролью %s: %s",
+		MsgActionFindNear:    "Поиск элемента с ролью %s рядом с текстом: %s",
+		MsgTaskPlan:          "План выполнения:",
+		MsgTaskPlanStep:      "  %d. %s",
+		MsgSubtaskCompleted:  "Шаг плана выполнен: %s",
+		MsgMoreElements:      "Загрузка следующей страницы элементов (%d)",
+		MsgActionExecuteJS:   "Выполнение JavaScript: %s",
+		MsgExecuteJSSuccess:  "Скрипт выполнен, результат: %s",
+		MsgActionDownload:    "Скачивание файла: %s",
+		MsgDownloadSuccess:   "Файл сохранен: %s",
+		MsgActionWaitText:    "Ожидание текста '%s' в элементе: %s",
+		MsgWaitTextSuccess:   "Текст '%s' появился в элементе: %s",
+		MsgActionWaitStable:  "Ожидание стабилизации элемента: %s",
+		MsgWaitStableSuccess: "Элемент стабилизировался: %s",
+		MsgDialogOpened:      "Страница показала диалог: %s",
+		MsgDialogPrompt:      "Введите 'да' чтобы подтвердить диалог, или что угодно чтобы отклонить: ",
+		MsgDialogAccepted:    "Диалог подтвержден",
+		MsgDialogDismissed:   "Диалог отклонен",
+		MsgStepPause:         "Пошаговый режим: нажмите Enter для следующего действия: ",
+		MsgActionScreenshot:  "Снимок экрана",
+		MsgScreenshotSaved:   "Снимок экрана сохранен: %s",
+		MsgActionSavePDF:     "Сохранение страницы в PDF",
+		MsgPDFSaved:          "PDF сохранен: %s",
+		MsgActionCloseTab:    "Закрытие текущей вкладки",
+		MsgActionPrevTab:     "Переход на предыдущую вкладку",
+		MsgTabClosed:         "Вкладка закрыта, текущая страница: %s",
+		MsgTabSwitched:       "Переключено на вкладку: %s",
+		MsgActionFocus:       "Сосредоточиться на области: %s",
+		MsgFocusSet:          "Анализ ограничен областью: %s",
+		MsgFocusCleared:      "Анализ снова охватывает всю страницу",
+		MsgFocusNotFound:     "Область %s не найдена, анализируется вся страница",
+		MsgActionSelectText:  "Выделение текста элемента: %s",
+		MsgActionSelection:   "Чтение выделенного текста",
+		MsgActionLoadAll:     "Подгрузка всего списка прокруткой",
+		MsgCaptchaDetected:   "Обнаружена CAPTCHA или проверка на робота.",
+		MsgCaptchaSolve:      "Пройдите проверку вручную в открытом окне браузера.",
+
+		MsgNavigateSuccess:    "Успешно перешел на страницу: %s",
+		MsgNewTabSuccess:      "Страница %s открыта в новой вкладке, предыдущая вкладка осталась открытой",
+		MsgTabReused:          "Страница %s уже открыта, выполнен переход на ее вкладку",
+		MsgClickSuccess:       "Успешно кликнул на элемент: %s",
+		MsgClickSubstituted:   "Элемент %s не найден, кликнул на похожий элемент: %s",
+		MsgTypeSuccess:        "Успешно ввел текст в поле: %s",
+		MsgSetDateSuccess:     "Дата %s установлена в поле: %s",
+		MsgCheckSuccess:       "Отмечено: %s",
+		MsgUncheckSuccess:     "Отметка снята: %s",
+		MsgSelectSuccess:      "В списке %s выбрано: %s",
+		MsgOptionsSuccess:     "Варианты списка %s (%d): %s",
+		MsgScrollSuccess:      "Успешно прокрутил страницу",
+		MsgExtractSuccess:     "Успешно извлек информацию со страницы",
+		MsgWaitSuccess:        "Ожидание %d секунд завершено",
+		MsgCopySuccess:        "Успешно скопировал текст в буфер обмена",
+		MsgPasteSuccess:       "Успешно вставил текст из буфера обмена в поле: %s",
+		MsgReadElementSuccess: "Значение элемента %s: %s",
+		MsgSelectTextSuccess:  "Выделен текст элемента %s: %s",
+		MsgSelectionSuccess:   "Выделенный текст: %s",
+		MsgLoadAllSuccess:     "Новые элементы подгружены %d раз, на странице элементов: %d, ссылок: %d, кнопок: %d",
+		MsgExtractDataSuccess: "Извлеченные данные:\n%s",
+		MsgRightClickSuccess:  "Успешно открыл контекстное меню элемента: %s",
+		MsgCountSuccess:       "Найдено элементов %s: %d",
+		MsgClickAtSuccess:     "Успешно кликнул по координатам (%d, %d)",
+		MsgWaitLoadSuccess:    "Страница загружена: %s",
+		MsgFindByRoleSuccess:  "Найдено элементов с ролью %s: %d\n%s",
+		MsgFindNearSuccess:    "Рядом с текстом %q найден элемент %q (селектор: %s)",
+
+		MsgWelcomeTitle:      "AI Браузер Агент",
+		MsgWelcomeHint:       "Введите задачу для агента, или 'quit' для выхода",
+		MsgWelcomeCommands:   "Команды: /analyze [фокус] - краткий анализ текущей страницы, /frames - фреймы страницы, /console - консоль и ошибки JavaScript страницы, /inspect [селектор] - элементы, которые видит агент, /export <файл> - сохранить шаги последней задачи для повтора",
+		MsgGoodbye:           "До свидания!",
+		MsgRestorePrompt:     "Открыть страницу с прошлого запуска %s (вкладок: %d)? [да/нет]: ",
+		MsgRestoreFailed:     "Не удалось открыть прошлую страницу: %v",
+		MsgAnalyzeFailed:     "Не удалось проанализировать страницу: %v",
+		MsgAnalyzeDefault:    "Кратко опиши, что находится на текущей странице",
+		MsgStartingTask:      "Начинаю выполнение задачи: %s",
+		MsgTaskCancelledExit: "Задача отменена, завершаю работу...",
+		MsgTaskFailed:        "Задача не выполнена: %v",
+		MsgTaskCompleted:     "Задача выполнена",
+		MsgScriptSaved:       "Сценарий сохранен: %s (шагов: %d)",
+		MsgExportNoTask:      "Нет выполненных шагов для сохранения, сначала выполните задачу",
+		MsgExportFailed:      "Не удалось сохранить сценарий: %v",
+		MsgFramesHeader:      "Фреймы на странице (%d):",
+		MsgFramesNone:        "На странице нет фреймов",
+		MsgFramesFailed:      "Не удалось получить список фреймов: %v",
+		MsgConsoleHeader:     "Консоль страницы (записей: %d):",
+		MsgConsoleNone:       "В консоли страницы нет записей",
+		MsgConsoleFailed:     "Не удалось прочитать консоль страницы: %v",
+		MsgInspectHeader:     "Элементы страницы %s (%d):",
+		MsgInspectColumns:    "ТЕГ\tТЕКСТ\tСЕЛЕКТОР\tВИДИМ\tКЛИКАБЕЛЕН",
+		MsgInspectNone:       "Подходящих элементов на странице нет",
+		MsgInspectMore:       "На странице есть еще элементы, агент получает их постранично",
+		MsgInspectFailed:     "Не удалось получить элементы страницы: %v",
+		MsgFrameCrossOrigin:  "(другой источник, содержимое недоступно)",
+		MsgReplayStart:       "Повтор сценария: %s (шагов: %d)",
+		MsgReplayStep:        "Шаг %d/%d: %s",
+		MsgReplayDone:        "Сценарий выполнен",
+		MsgQueueStart:        "Очередь задач: %s (задач: %d)",
+		MsgQueueTask:         "Задача %d/%d: %s",
+		MsgQueueSummary:      "Очередь завершена: выполнено %d, с ошибкой %d, пропущено %d. Результаты: %s",
+		MsgProgressStep:      "Шаг %d/%d",
+		MsgProgressTokens:    "токенов: %d",
+
+		PromptVisibleText:   "Видимый текст на странице (первые %d символов):",
+		PromptButtons:       "Кнопки:",
+		PromptLinks:         "Ссылки:",
+		PromptElements:      "Интерактивные элементы:",
+		PromptElementLine:   "  - \"%s\" (селектор: %s)",
+		PromptTaggedLine:    "  - %s: \"%s\" (селектор: %s)",
+		PromptRelevantLine:  "  ★ %s: \"%s\" (селектор: %s)",
+		PromptRankedHeader:  "Интерактивные элементы (сначала самые подходящие для задачи, ★ - лучшие совпадения):",
+		PromptScope:         "Показаны только элементы внутри %s (focus_on с пустым селектором вернет всю страницу)",
+		PromptNoText:        "без текста",
+		PromptDisabled:      " [неактивен, клик не сработает]",
+		PromptForms:         "Формы и поля ввода:",
+		PromptFormHeader:    "  Форма (метод: %s, действие: %s):",
+		PromptFormField:     "    - Поле \"%s\" (тип: %s, имя: %s)",
+		PromptFormOptions:   "      варианты: %s",
+		PromptNoElements:    "Интерактивные элементы не найдены. Попробуйте прокрутить страницу.",
+		PromptNoElementHint: "Попробуйте прокрутить страницу или использовать поиск по тексту элементов",
+		PromptNoHistory:     "Нет выполненных действий",
+		PromptHistoryResult: "   Результат: %s",
+		PromptHistoryError:  "   Не удалось: %s",
+		PromptEarlierSteps:  "Ранее (шаги 1-%d): %s",
+		PromptEarlierFailed: "из них не удалось: %d",
+
+		HistoryNavigate:    "Переход на страницу",
+		HistoryClick:       "Клик",
+		HistoryType:        "Ввод текста",
+		HistorySetDate:     "Выбор даты",
+		HistorySetChecked:  "Отметка флажка",
+		HistorySelect:      "Выбор в списке",
+		HistoryGetOptions:  "Получение вариантов списка",
+		HistoryScroll:      "Прокрутка",
+		HistoryExtract:     "Извлечение информации",
+		HistoryWait:        "Ожидание",
+		HistoryCopy:        "Копирование в буфер обмена",
+		HistoryPaste:       "Вставка из буфера обмена",
+		HistoryReadElement: "Чтение элемента",
+		HistoryRightClick:  "Правый клик",
+		HistoryCount:       "Подсчет элементов",
+		HistoryClickAt:     "Клик по координатам",
+		HistoryWaitLoad:    "Ожидание загрузки страницы",
+		HistoryFindByRole:  "Поиск элементов по роли",
+		HistoryFindNear:    "Поиск элемента рядом с текстом",
+		HistorySubtaskDone: "Шаг плана выполнен",
+		HistoryMoreItems:   "Следующая страница элементов",
+		HistoryExecuteJS:   "Выполнение JavaScript",
+		HistoryDownload:    "Скачивание файла",
+		HistoryWaitText:    "Ожидание текста",
+		HistoryWaitStable:  "Ожидание стабилизации элемента",
+		HistoryScreenshot:  "Снимок экрана",
+		HistorySavePDF:     "Сохранение в PDF",
+		HistoryCloseTab:    "Закрытие вкладки",
+		HistoryPrevTab:     "Переход на предыдущую вкладку",
+		HistoryFocus:       "Фокус на области страницы",
+		HistorySelectText:  "Выделение текста",
+		HistorySelection:   "Чтение выделения",
+		HistoryLoadAll:     "Подгрузка всего списка",
+		HistoryComplete:    "Завершение задачи",
+	},
+	LanguageEnglish: {
+		MsgTaskHeader:          "Task: %s",
+		MsgStartingWork:        "Starting work...",
+		MsgAnalyzingPage:       "Analyzing current page...",
+		MsgPageAnalysisError:   "Failed to analyze page: %v",
+		MsgCurrentPage:         "Current page: %s",
+		MsgDecidingAction:      "Deciding next action...",
+		MsgQueuedAction:        "Next action from the same AI response...",
+		MsgDecisionError:       "Failed to decide next action: %v",
+		MsgApprovalRequired:    "WARNING: Destructive action requires confirmation!",
+		MsgApprovalAction:      "Action: %s",
+		MsgApprovalDescription: "Description: %s",
+		MsgApprovalWarning:     "This action may be irreversible (deletion, payment, etc.)",
+		MsgApprovalPrompt:      "Type 'continue' or 'confirm' to proceed, or 'cancel' to abort: ",
+		MsgActionApproved:      "Action confirmed, continuing...",
+		MsgActionRejected:      "Action cancelled by user",
+		MsgExecutingAction:     "Executing action: %s",
+		MsgActionError:         "Error: %s - %s",
+		MsgTryingAnotherWay:    "Trying another approach...",
+		MsgElementMissing:      "Element %s is not on the page, choosing another one...",
+		MsgActionNotAllowed:    "Action %s is not allowed by ALLOWED_ACTIONS, skipping it",
+		MsgRevisitBlocked:      "Page %s was already opened %d times, not going back to it",
+		MsgBannerDismissed:     "Dismissed cookie banner: %s",
+		MsgMaxIterations:       "Reached maximum number of iterations (%d)",
+		MsgNoProgress:          "The page has not changed for %d steps in a row, stopping the task",
+		MsgBrowserClosed:       "The browser was closed, the task cannot continue",
+		MsgTaskInterrupted:     "Task execution interrupted",
+		MsgTaskTimedOut:        "Task ran out of time",
+		MsgLoginDetected:       "Login page detected.",
+		MsgLoginInstructions:   "Please log in manually in the opened browser.",
+		MsgPressEnter:          "Press Enter when you are ready to continue: ",
+		MsgTaskSummary:         "Result: %s",
+		MsgAskUserQuestion:     "The agent needs clarification: %s",
+		MsgAskUserPrompt:       "Your answer: ",
+		MsgVerifyingCompletion: "Checking that the task is really done...",
+		MsgCompletionRejected:  "The task is not done yet: %s. Continuing",
+
+		MsgActionNavigate:    "Navigate to: %s",
+		MsgActionNewTab:      "Open in a new tab: %s",
+		MsgActionClick:       "Click on element: %s",
+		MsgActionType:        "Type '%s' into field: %s",
+		MsgActionSetDate:     "Set date %s in field: %s",
+		MsgActionCheck:       "Check: %s",
+		MsgActionUncheck:     "Uncheck: %s",
+		MsgActionSelect:      "Select %s in list: %s",
+		MsgActionGetOptions:  "Get options of list: %s",
+		MsgActionScroll:      "Scroll page",
+		MsgActionExtract:     "Extract page information",
+		MsgActionWait:        "Wait",
+		MsgActionCopy:        "Copy text to clipboard",
+		MsgActionPaste:       "Paste clipboard into field: %s",
+		MsgActionReadElement: "Read element: %s",
+		MsgActionRightClick:  "Right-click on element: %s",
+		MsgActionCount:       "Count elements: %s",
+		MsgActionClickAt:     "Click at coordinates (%d, %d)",
+		MsgActionWaitLoad:    "Wait for page to load",
+		MsgActionFindByRole:  "Find elements with role %s: %s",
+		MsgActionFindNear:    "Find element with role %s near text: %s",
+		MsgTaskPlan:          "Plan:",
+		MsgTaskPlanStep:      "  %d. %s",
+		MsgSubtaskCompleted:  "Plan step done: %s",
+		MsgMoreElements:      "Loading next page of elements (%d)",
+		MsgActionExecuteJS:   "Run JavaScript: %s",
+		MsgExecuteJSSuccess:  "Script finished, result: %s",
+		MsgActionDownload:    "Download file: %s",
+		MsgDownloadSuccess:   "File saved: %s",
+		MsgActionWaitText:    "Wait for text '%s' in element: %s",
+		MsgWaitTextSuccess:   "Text '%s' appeared in element: %s",
+		MsgActionWaitStable:  "Wait for element to settle: %s",
+		MsgWaitStableSuccess: "Element settled: %s",
+		MsgDialogOpened:      "The page opened a dialog: %s",
+		MsgDialogPrompt:      "Type 'yes' to accept the dialog, anything else to dismiss it: ",
+		MsgDialogAccepted:    "Dialog accepted",
+		MsgDialogDismissed:   "Dialog dismissed",
+		MsgStepPause:         "Step mode: press Enter for the next action: ",
+		MsgActionScreenshot:  "Take screenshot",
+		MsgScreenshotSaved:   "Screenshot saved: %s",
+		MsgActionSavePDF:     "Save page as PDF",
+		MsgPDFSaved:          "PDF saved: %s",
+		MsgActionCloseTab:    "Close current tab",
+		MsgActionPrevTab:     "Switch to previous tab",
+		MsgTabClosed:         "Tab closed, current page: %s",
+		MsgTabSwitched:       "Switched to tab: %s",
+		MsgActionFocus:       "Focus on area: %s",
+		MsgFocusSet:          "Page analysis limited to: %s",
+		MsgFocusCleared:      "Page analysis covers the whole page again",
+		MsgFocusNotFound:     "Area %s not found, analysing the whole page",
+		MsgActionSelectText:  "Select text of element: %s",
+		MsgActionSelection:   "Read selected text",
+		MsgActionLoadAll:     "Load the whole list by scrolling",
+		MsgCaptchaDetected:   "A CAPTCHA or bot check was detected.",
+		MsgCaptchaSolve:      "Please solve it manually in the open browser window.",
+
+		MsgNavigateSuccess:    "Navigated to: %s",
+		MsgNewTabSuccess:      "Opened %s in a new tab, the previous tab is still open",
+		MsgTabReused:          "%s is already open, switched to its tab",
+		MsgClickSuccess:       "Clicked on element: %s",
+		MsgClickSubstituted:   "Element %s was not found, clicked the closest match instead: %s",
+		MsgTypeSuccess:        "Typed text into field: %s",
+		MsgSetDateSuccess:     "Set date %s in field: %s",
+		MsgCheckSuccess:       "Checked: %s",
+		MsgUncheckSuccess:     "Unchecked: %s",
+		MsgSelectSuccess:      "Selected in %s: %s",
+		MsgOptionsSuccess:     "Options of %s (%d): %s",
+		MsgScrollSuccess:      "Scrolled the page",
+		MsgExtractSuccess:     "Extracted page information",
+		MsgWaitSuccess:        "Waited %d seconds",
+		MsgCopySuccess:        "Copied text to clipboard",
+		MsgPasteSuccess:       "Pasted clipboard into field: %s",
+		MsgReadElementSuccess: "Value of element %s: %s",
+		MsgSelectTextSuccess:  "Selected text of element %s: %s",
+		MsgSelectionSuccess:   "Selected text: %s",
+		MsgLoadAllSuccess:     "Loaded more content %d times, the page now has %d elements, %d links and %d buttons",
+		MsgExtractDataSuccess: "Extracted data:\n%s",
+		MsgRightClickSuccess:  "Opened context menu of element: %s",
+		MsgCountSuccess:       "Elements matching %s: %d",
+		MsgClickAtSuccess:     "Clicked at coordinates (%d, %d)",
+		MsgWaitLoadSuccess:    "Page loaded: %s",
+		MsgFindByRoleSuccess:  "Elements with role %s: %d\n%s",
+		MsgFindNearSuccess:    "Next to %q found %q (selector: %s)",
+
+		MsgWelcomeTitle:      "AI Browser Agent",
+		MsgWelcomeHint:       "Enter a task for the agent, or 'quit' to exit",
+		MsgWelcomeCommands:   "Commands: /analyze [focus] - short analysis of the current page, /frames - frames of the page, /console - console and JavaScript errors of the page, /inspect [selector] - elements the agent sees, /export <file> - save steps of the last task for replay",
+		MsgGoodbye:           "Goodbye!",
+		MsgRestorePrompt:     "Reopen the page from the last run %s (%d tabs)? [yes/no]: ",
+		MsgRestoreFailed:     "Failed to reopen the last page: %v",
+		MsgAnalyzeFailed:     "Failed to analyze page: %v",
+		MsgAnalyzeDefault:    "Briefly describe what is on the current page",
+		MsgStartingTask:      "Starting task: %s",
+		MsgTaskCancelledExit: "Task cancelled, shutting down...",
+		MsgTaskFailed:        "Task failed: %v",
+		MsgTaskCompleted:     "Task completed",
+		MsgScriptSaved:       "Script saved: %s (%d steps)",
+		MsgExportNoTask:      "No executed steps to save, run a task first",
+		MsgExportFailed:      "Failed to save script: %v",
+		MsgFramesHeader:      "Frames on the page (%d):",
+		MsgFramesNone:        "The page has no frames",
+		MsgFramesFailed:      "Failed to list frames: %v",
+		MsgConsoleHeader:     "Console of the page (%d entries):",
+		MsgConsoleNone:       "The page console is empty",
+		MsgConsoleFailed:     "Failed to read the page console: %v",
+		MsgInspectHeader:     "Elements of %s (%d):",
+		MsgInspectColumns:    "TAG\tTEXT\tSELECTOR\tVISIBLE\tCLICKABLE",
+		MsgInspectNone:       "No matching elements on the page",
+		MsgInspectMore:       "The page has more elements, the agent gets them page by page",
+		MsgInspectFailed:     "Failed to extract page elements: %v",
+		MsgFrameCrossOrigin:  "(cross-origin, content not accessible)",
+		MsgReplayStart:       "Replaying script: %s (%d steps)",
+		MsgReplayStep:        "Step %d/%d: %s",
+		MsgReplayDone:        "Script finished",
+		MsgQueueStart:        "Task queue: %s (%d tasks)",
+		MsgQueueTask:         "Task %d/%d: %s",
+		MsgQueueSummary:      "Queue finished: %d completed, %d failed, %d skipped. Results: %s",
+		MsgProgressStep:      "Step %d/%d",
+		MsgProgressTokens:    "%d tokens",
+
+		PromptVisibleText:   "Visible text on the page (first %d characters):",
+		PromptButtons:       "Buttons:",
+		PromptLinks:         "Links:",
+		PromptElements:      "Interactive elements:",
+		PromptElementLine:   "  - \"%s\" (selector: %s)",
+		PromptTaggedLine:    "  - %s: \"%s\" (selector: %s)",
+		PromptRelevantLine:  "  ★ %s: \"%s\" (selector: %s)",
+		PromptRankedHeader:  "Interactive elements (most relevant to the task first, ★ marks the best matches):",
+		PromptScope:         "Only elements inside %s are shown (focus_on with an empty selector shows the whole page)",
+		PromptNoText:        "no text",
+		PromptDisabled:      " [disabled, clicking it does nothing]",
+		PromptForms:         "Forms and input fields:",
+		PromptFormHeader:    "  Form (method: %s, action: %s):",
+		PromptFormField:     "    - Field \"%s\" (type: %s, name: %s)",
+		PromptFormOptions:   "      options: %s",
+		PromptNoElements:    "No interactive elements found. Try scrolling the page.",
+		PromptNoElementHint: "Try scrolling the page or searching elements by text",
+		PromptNoHistory:     "No actions performed yet",
+		PromptHistoryResult: "   Result: %s",
+		PromptHistoryError:  "   Failed: %s",
+		PromptEarlierSteps:  "Earlier (steps 1-%d): %s",
+		PromptEarlierFailed: "%d of them failed",
+
+		HistoryNavigate:    "Navigate",
+		HistoryClick:       "Click",
+		HistoryType:        "Type text",
+		HistorySetDate:     "Set date",
+		HistorySetChecked:  "Set checkbox",
+		HistorySelect:      "Select option",
+		HistoryGetOptions:  "Get list options",
+		HistoryScroll:      "Scroll",
+		HistoryExtract:     "Extract information",
+		HistoryWait:        "Wait",
+		HistoryCopy:        "Copy to clipboard",
+		HistoryPaste:       "Paste from clipboard",
+		HistoryReadElement: "Read element",
+		HistoryRightClick:  "Right-click",
+		HistoryCount:       "Count elements",
+		HistoryClickAt:     "Click at coordinates",
+		HistoryWaitLoad:    "Wait for page load",
+		HistoryFindByRole:  "Find elements by role",
+		HistoryFindNear:    "Find element near text",
+		HistorySubtaskDone: "Plan step done",
+		HistoryMoreItems:   "Next page of elements",
+		HistoryExecuteJS:   "Run JavaScript",
+		HistoryDownload:    "Download file",
+		HistoryWaitText:    "Wait for text",
+		HistoryWaitStable:  "Wait for element to settle",
+		HistoryScreenshot:  "Take screenshot",
+		HistorySavePDF:     "Save as PDF",
+		HistoryCloseTab:    "Close tab",
+		HistoryPrevTab:     "Switch to previous tab",
+		HistoryFocus:       "Focus on page area",
+		HistorySelectText:  "Select text",
+		HistorySelection:   "Read selection",
+		HistoryLoadAll:     "Load the whole list",
+		HistoryComplete:    "Complete the task",
+	},
+}
+
+var current = LanguageRussian
+
+// SetLanguage switches output language; unknown values fall back to Russian
+func SetLanguage(lang string) {
+	switch Language(strings.ToLower(strings.TrimSpace(lang))) {
+	case LanguageEnglish:
+		current = LanguageEnglish
+	default:
+		current = LanguageRussian
+	}
+}
+
+// CurrentLanguage returns the active output language
+func CurrentLanguage() Language {
+	return current
+}
+
+// T returns localized message formatted with args
+func T(id MessageID, args ...interface{}) string {
+	format, ok := messages[current][id]
+	if !ok {
+		format, ok = messages[LanguageRussian][id]
+		if !ok {
+			return string(id)
+		}
+	}
+
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
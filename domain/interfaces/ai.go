@@ -13,5 +13,14 @@ type AIService interface {
 	
 	// AnalyzePage analyzes the page and extracts relevant information
 	AnalyzePage(ctx context.Context, pageInfo *entities.PageInfo, task *entities.Task) (string, error)
+	
+	// ExtractData extracts structured data described by schema from the page and returns it as JSON
+	ExtractData(ctx context.Context, pageInfo *entities.PageInfo, schema string) (string, error)
+	
+	// PlanTask breaks the task into ordered subtasks
+	PlanTask(ctx context.Context, task *entities.Task) ([]string, error)
+	
+	// VerifyCompletion checks a completion claim against the page and explains the verdict
+	VerifyCompletion(ctx context.Context, task *entities.Task, pageInfo *entities.PageInfo, summary string) (complete bool, reason string, err error)
 }
 
@@ -3,9 +3,11 @@ package interfaces
 import (
 	"ai_automation/domain/entities"
 	"context"
+	"time"
 )
 
-// BrowserController defines the interface for browser automation
+// BrowserController defines the interface for browser automation.
+// Failures are *BrowserError values, match their kind with errors.Is (see browser_errors.go)
 type BrowserController interface {
 	// Navigate navigates to a URL
 	Navigate(ctx context.Context, url string) error
@@ -13,11 +15,37 @@ type BrowserController interface {
 	// Click clicks on an element by selector
 	Click(ctx context.Context, selector string) error
 	
+	// ClickClosest clicks on an element by selector, falling back to the most similar element
+	// when the selector matches nothing; returns the selector that was clicked
+	ClickClosest(ctx context.Context, selector string) (string, error)
+	
 	// TypeText types text into an element
 	TypeText(ctx context.Context, selector string, text string) error
 	
-	// ExtractPageInfo extracts structured information from the current page
-	ExtractPageInfo(ctx context.Context) (*entities.PageInfo, error)
+	// AppendText types text after the current content of an element, without clearing it
+	AppendText(ctx context.Context, selector string, text string) error
+	
+	// SetDate sets a date field to date (YYYY-MM-DD, optionally with THH:MM): native date inputs
+	// get the ISO value with change events, calendar widgets get it typed
+	SetDate(ctx context.Context, selector string, date string) error
+
+	// SetChecked checks or unchecks a checkbox, radio button or switch found by selector or label text.
+	// A control already in that state is not clicked
+	SetChecked(ctx context.Context, selector string, checked bool) error
+
+	// SelectOption selects options of a select list by value or visible text; a multi-select ends up
+	// with exactly values selected, a single select takes one value
+	SelectOption(ctx context.Context, selector string, values []string) error
+
+	// GetSelectOptions returns the visible texts of the options of a select list
+	GetSelectOptions(ctx context.Context, selector string) ([]string, error)
+	
+	// ExtractPageInfo extracts structured information from the current page.
+	// page selects the next slice of elements beyond the extraction limits (0 - first page)
+	ExtractPageInfo(ctx context.Context, page int) (*entities.PageInfo, error)
+	
+	// ExtractPageInfoWithin extracts page information only from inside the element matched by rootSelector
+	ExtractPageInfoWithin(ctx context.Context, rootSelector string, page int) (*entities.PageInfo, error)
 	
 	// Wait waits for a condition or time
 	Wait(ctx context.Context, condition string, timeout int) error
@@ -25,6 +53,10 @@ type BrowserController interface {
 	// Scroll scrolls the page
 	Scroll(ctx context.Context, direction string, amount int) error
 	
+	// LoadAll scrolls an infinite list (or presses "load more") until nothing new appears or maxScrolls
+	// is reached (zero uses the default cap), returns how many times more content was loaded
+	LoadAll(ctx context.Context, maxScrolls int) (int, error)
+	
 	// GetCurrentURL returns the current page URL
 	GetCurrentURL(ctx context.Context) (string, error)
 	
@@ -34,13 +66,107 @@ type BrowserController interface {
 	// TakeScreenshot takes a screenshot
 	TakeScreenshot(ctx context.Context) ([]byte, error)
 	
+	// SavePDF prints the whole current page to a PDF file at path (headless Chrome and Edge only)
+	SavePDF(ctx context.Context, path string) error
+	
 	// Close closes the browser
 	Close() error
 	
 	// IsElementVisible checks if an element is visible
 	IsElementVisible(ctx context.Context, selector string) (bool, error)
 	
+	// IsElementEnabled checks if an element is enabled (not disabled or aria-disabled)
+	IsElementEnabled(ctx context.Context, selector string) (bool, error)
+	
+	// ListFrames returns the frame tree of the current page in document order
+	ListFrames(ctx context.Context) ([]entities.FrameInfo, error)
+	
+	// GetConsoleLogs returns console messages and uncaught errors of the current page, oldest first
+	GetConsoleLogs(ctx context.Context) ([]entities.LogEntry, error)
+	
+	// DismissConsentBanner clicks the accept button of a cookie consent banner,
+	// returns its label or empty string when the page has no banner
+	DismissConsentBanner(ctx context.Context) (string, error)
+	
 	// FindElementsByText finds elements containing specific text
 	FindElementsByText(ctx context.Context, text string) ([]entities.PageElement, error)
+	
+	// FindElementsByRole finds elements by ARIA role and accessible name (empty name matches any)
+	FindElementsByRole(ctx context.Context, role string, name string) ([]entities.PageElement, error)
+	
+	// FindElementNearText finds the element with the ARIA role closest in the DOM to the anchor text
+	FindElementNearText(ctx context.Context, anchorText string, role string) (*entities.PageElement, error)
+	
+	// ReadClipboard returns the current clipboard text
+	ReadClipboard(ctx context.Context) (string, error)
+	
+	// WriteClipboard puts text into the clipboard
+	WriteClipboard(ctx context.Context, text string) error
+	
+	// SelectText selects the whole text of an element
+	SelectText(ctx context.Context, selector string) error
+	
+	// GetSelectedText returns the text currently selected on the page
+	GetSelectedText(ctx context.Context) (string, error)
+	
+	// GetAttribute returns the value of an element attribute
+	GetAttribute(ctx context.Context, selector string, attr string) (string, error)
+	
+	// GetElementText returns the visible text of an element
+	GetElementText(ctx context.Context, selector string) (string, error)
+	
+	// RightClick opens the context menu of an element (web-rendered menus only)
+	RightClick(ctx context.Context, selector string) error
+	
+	// ElementExists checks whether at least one element matches the selector
+	ElementExists(ctx context.Context, selector string) (bool, error)
+	
+	// CountElements returns the number of elements matching the selector
+	CountElements(ctx context.Context, selector string) (int, error)
+	
+	// DownloadFile clicks a download trigger, waits for the file and returns its saved path
+	DownloadFile(ctx context.Context, selector string) (string, error)
+	
+	// ExecuteScript runs JavaScript on the page and returns its result as text (JSON for non-strings)
+	ExecuteScript(ctx context.Context, script string) (string, error)
+	
+	// ClickAt clicks at viewport coordinates, for canvas or otherwise untargetable UIs
+	ClickAt(ctx context.Context, x, y int) error
+	
+	// GetBoundingBox returns element position and size in the viewport
+	GetBoundingBox(ctx context.Context, selector string) (*entities.BoundingBox, error)
+	
+	// WaitForURLChange waits until the URL differs from previousURL and settles, returns the new URL
+	WaitForURLChange(ctx context.Context, previousURL string, timeout time.Duration) (string, error)
+	
+	// WaitForNavigation waits until the current page has finished loading
+	WaitForNavigation(ctx context.Context, timeout time.Duration) error
+	
+	// WaitForTextContains waits until the element text contains the given text
+	WaitForTextContains(ctx context.Context, selector string, text string, timeout time.Duration) error
+	
+	// WaitForElementStable waits until the element exists and its position and size stop changing
+	WaitForElementStable(ctx context.Context, selector string, timeout time.Duration) error
+	
+	// GetOpenDialog returns the message of an open JS dialog, if any
+	GetOpenDialog(ctx context.Context) (string, bool)
+	
+	// HandleDialog accepts or dismisses the open JS dialog
+	HandleDialog(ctx context.Context, accept bool) error
+	
+	// OpenNewTab opens url in a new tab and switches to it, keeping the current tab open
+	OpenNewTab(ctx context.Context, url string) error
+	
+	// SwitchToTab switches to an open tab already showing url, reports false when there is none
+	SwitchToTab(ctx context.Context, url string) (bool, error)
+	
+	// BringToFront raises the browser window with the active tab above other windows
+	BringToFront(ctx context.Context) error
+	
+	// SwitchToPreviousTab returns to the tab that was active before the current one
+	SwitchToPreviousTab(ctx context.Context) error
+	
+	// CloseCurrentTab closes the active tab and returns to the previous one (the last tab is reset to a blank page)
+	CloseCurrentTab(ctx context.Context) error
 }
 
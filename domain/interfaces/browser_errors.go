@@ -0,0 +1,39 @@
+package interfaces
+
+import "errors"
+
+// Kinds of BrowserController failures, match them with errors.Is
+var (
+	// ErrElementNotFound - no element matches the selector
+	ErrElementNotFound = errors.New("element not found")
+	// ErrElementDisabled - the element is there but disabled, so it ignores the action
+	ErrElementDisabled = errors.New("element disabled")
+	// ErrTimeout - a wait ran out of time before its condition was met
+	ErrTimeout = errors.New("timed out")
+	// ErrNavigation - the page could not be loaded or loaded as an error page
+	ErrNavigation = errors.New("navigation failed")
+	// ErrBrowserClosed - the browser window or WebDriver session is gone
+	ErrBrowserClosed = errors.New("browser closed")
+	// ErrCommand - a WebDriver command (a script, a click, a tab switch) failed for another reason
+	ErrCommand = errors.New("browser command failed")
+)
+
+// BrowserError - failure of a BrowserController call. Error returns Message,
+// errors.Is matches Kind and everything Err wraps
+type BrowserError struct {
+	Kind    error
+	Message string
+	Err     error
+}
+
+func (e *BrowserError) Error() string {
+	return e.Message
+}
+
+func (e *BrowserError) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *BrowserError) Unwrap() error {
+	return e.Err
+}
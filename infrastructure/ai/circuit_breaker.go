@@ -0,0 +1,98 @@
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrAIUnavailable - returned without calling the API while the circuit breaker is open
+var ErrAIUnavailable = errors.New("AI temporarily unavailable")
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	// breakerHalfOpen lets a single probe call through after the cooldown
+	breakerHalfOpen
+)
+
+// circuitBreaker - stops calling the API after threshold failed calls in a row, so an outage is not
+// hammered by every step of every task. After cooldown one probe call goes through: success closes
+// the breaker, failure opens it for another cooldown. A nil breaker lets every call through
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+// newCircuitBreaker - creates a breaker, nil when threshold is zero (disabled)
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	if threshold <= 0 {
+		return nil
+	}
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// allow - returns ErrAIUnavailable while the breaker is open or a probe is already in flight
+func (b *circuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if wait := b.cooldown - b.now().Sub(b.openedAt); wait > 0 {
+			return fmt.Errorf("%w after %d failed calls, next try in %s", ErrAIUnavailable, b.failures, wait.Round(time.Second))
+		}
+		b.state = breakerHalfOpen
+		return nil
+	case breakerHalfOpen:
+		return fmt.Errorf("%w, checking whether the API is back", ErrAIUnavailable)
+	}
+	return nil
+}
+
+// record - counts the outcome of a call allow let through
+func (b *circuitBreaker) record(failed bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !failed {
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// release - ends a call allow let through without an outcome (the caller gave up). A probe in
+// flight is given back: the breaker opens again for a cooldown, the failures stay as they are
+func (b *circuitBreaker) release() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen {
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+}
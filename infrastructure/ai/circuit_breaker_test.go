@@ -0,0 +1,155 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// flakyServer - chat completions endpoint answering 503 while down is set
+func flakyServer(t *testing.T, down *atomic.Bool, calls *atomic.Int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if down.Load() {
+			http.Error(w, "overloaded", http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, `{"choices": [{"message": {"content": "ok"}}]}`)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func testClient(server *httptest.Server, retry retryPolicy, breaker *circuitBreaker) *OpenAIClient {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return &OpenAIClient{client: server.Client(), baseURL: server.URL, logger: logger, retry: retry, breaker: breaker}
+}
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	var down atomic.Bool
+	var calls atomic.Int32
+	server := flakyServer(t, &down, &calls)
+
+	now := time.Now()
+	breaker := newCircuitBreaker(3, time.Minute)
+	breaker.now = func() time.Time { return now }
+	c := testClient(server, retryPolicy{}, breaker)
+	ctx := context.Background()
+
+	// Closed: failures reach the API until the threshold
+	down.Store(true)
+	for i := 0; i < 3; i++ {
+		if _, err := c.callAPI(ctx, "prompt", nil); err == nil || errors.Is(err, ErrAIUnavailable) {
+			t.Fatalf("call %d: err = %v, want the API error", i+1, err)
+		}
+	}
+
+	// Open: calls fail fast without reaching the API
+	if _, err := c.callAPI(ctx, "prompt", nil); !errors.Is(err, ErrAIUnavailable) {
+		t.Fatalf("err = %v, want ErrAIUnavailable while open", err)
+	}
+	if n := calls.Load(); n != 3 {
+		t.Fatalf("API called %d times, want 3", n)
+	}
+
+	// Half-open after the cooldown: a failed probe opens the breaker again
+	now = now.Add(time.Minute)
+	if _, err := c.callAPI(ctx, "prompt", nil); err == nil || errors.Is(err, ErrAIUnavailable) {
+		t.Fatalf("probe err = %v, want the API error", err)
+	}
+	if _, err := c.callAPI(ctx, "prompt", nil); !errors.Is(err, ErrAIUnavailable) {
+		t.Fatalf("err = %v, want ErrAIUnavailable after a failed probe", err)
+	}
+
+	// A successful probe closes it
+	now = now.Add(time.Minute)
+	down.Store(false)
+	for i := 0; i < 2; i++ {
+		if response, err := c.callAPI(ctx, "prompt", nil); err != nil || response != "ok" {
+			t.Fatalf("call %d after recovery = %q, %v", i+1, response, err)
+		}
+	}
+	if n := calls.Load(); n != 6 {
+		t.Errorf("API called %d times, want 6", n)
+	}
+}
+
+func TestCircuitBreakerCancelledProbe(t *testing.T) {
+	var down atomic.Bool
+	var calls atomic.Int32
+	server := flakyServer(t, &down, &calls)
+
+	now := time.Now()
+	breaker := newCircuitBreaker(1, time.Minute)
+	breaker.now = func() time.Time { return now }
+	c := testClient(server, retryPolicy{}, breaker)
+
+	// A call running into its deadline counts as a failure and opens the breaker
+	expired, cancelExpired := context.WithDeadline(context.Background(), now.Add(-time.Second))
+	defer cancelExpired()
+	if _, err := c.callAPI(expired, "prompt", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want the deadline", err)
+	}
+	if _, err := c.callAPI(context.Background(), "prompt", nil); !errors.Is(err, ErrAIUnavailable) {
+		t.Fatalf("err = %v, want ErrAIUnavailable after a timed out call", err)
+	}
+
+	// The probe after the cooldown is cancelled: the breaker opens again instead of waiting for it forever
+	now = now.Add(time.Minute)
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.callAPI(cancelled, "prompt", nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("probe err = %v, want the cancellation", err)
+	}
+	if _, err := c.callAPI(context.Background(), "prompt", nil); err == nil || !strings.Contains(err.Error(), "next try in") {
+		t.Fatalf("err = %v, want the breaker open for another cooldown", err)
+	}
+	if breaker.failures != 1 {
+		t.Errorf("failures = %d, want the cancelled probe not counted", breaker.failures)
+	}
+
+	// The next probe gets through and closes it
+	now = now.Add(time.Minute)
+	if response, err := c.callAPI(context.Background(), "prompt", nil); err != nil || response != "ok" {
+		t.Fatalf("probe after the cooldown = %q, %v", response, err)
+	}
+}
+
+func TestRetryRepeatsTransientFailures(t *testing.T) {
+	var down atomic.Bool
+	var calls atomic.Int32
+	server := flakyServer(t, &down, &calls)
+	c := testClient(server, retryPolicy{retries: 2, backoff: time.Millisecond, jitter: time.Millisecond}, nil)
+
+	down.Store(true)
+	if _, err := c.callAPI(context.Background(), "prompt", nil); err == nil {
+		t.Fatal("call succeeded while the API is down")
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("API called %d times, want the first attempt and 2 retries", n)
+	}
+}
+
+func TestRetryDelayHasJitter(t *testing.T) {
+	policy := retryPolicy{backoff: 100 * time.Millisecond, jitter: 50 * time.Millisecond}
+	seen := map[time.Duration]bool{}
+	for i := 0; i < 20; i++ {
+		delay := policy.delay(2)
+		if delay < 400*time.Millisecond || delay >= 450*time.Millisecond {
+			t.Fatalf("delay(2) = %s, want 400ms plus up to 50ms", delay)
+		}
+		seen[delay] = true
+	}
+	if len(seen) < 2 {
+		t.Error("retry delays are all the same, jitter is missing")
+	}
+}
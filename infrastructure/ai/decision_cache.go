@@ -0,0 +1,105 @@
+package ai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"ai_automation/config"
+	"ai_automation/domain/entities"
+)
+
+// DecisionCache - stores AI decisions so identical requests are not paid for twice
+type DecisionCache interface {
+	Get(key string) (*entities.Action, bool)
+	Put(key string, action *entities.Action) error
+}
+
+// diskDecisionCache - keeps each decision as a JSON file named by its key
+type diskDecisionCache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+// cachedDecision - on-disk format of a cached decision
+type cachedDecision struct {
+	CreatedAt time.Time        `json:"created_at"`
+	Action    *entities.Action `json:"action"`
+}
+
+// newDecisionCache - creates disk cache in ~/.ai_automation/cache when AI_CACHE=true,
+// returns nil when caching is disabled
+func newDecisionCache(cfg config.AIConfig) (DecisionCache, error) {
+	if !cfg.Cache {
+		return nil, nil
+	}
+
+	homeDir := os.Getenv("HOME")
+	if homeDir == "" {
+		return nil, fmt.Errorf("HOME environment variable is not set")
+	}
+
+	dir := filepath.Join(homeDir, ".ai_automation", "cache", "decisions")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create AI cache directory: %w", err)
+	}
+
+	return &diskDecisionCache{dir: dir, ttl: cfg.CacheTTL, now: time.Now}, nil
+}
+
+// Get - returns cached action, expired entries are removed and reported as a miss
+func (d *diskDecisionCache) Get(key string) (*entities.Action, bool) {
+	path := filepath.Join(d.dir, key+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cachedDecision
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Action == nil {
+		return nil, false
+	}
+	if d.now().Sub(entry.CreatedAt) > d.ttl {
+		os.Remove(path)
+		return nil, false
+	}
+
+	return entry.Action, true
+}
+
+// Put - stores action under the key
+func (d *diskDecisionCache) Put(key string, action *entities.Action) error {
+	data, err := json.Marshal(cachedDecision{CreatedAt: d.now(), Action: action})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(d.dir, key+".json"), data, 0644)
+}
+
+// decisionCacheKey - hashes everything the decision depends on. Page elements and text are part
+// of the key, so any change of page content invalidates the entry. The active model is part of it
+// too, so after escalation a routine model's cached answer is not reused
+func (c *OpenAIClient) decisionCacheKey(task *entities.Task, pageInfo *entities.PageInfo, historySummary string) string {
+	hash := sha256.New()
+	for _, part := range []string{
+		c.activeModel(task.ID),
+		task.Description,
+		task.Context,
+		formatPlan(task),
+		pageInfo.URL,
+		strconv.Itoa(pageInfo.Page),
+		c.formatPageElements(pageInfo, taskKeywords(task.Description)),
+		pageInfo.TextContent,
+		historySummary,
+	} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
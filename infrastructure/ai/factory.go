@@ -0,0 +1,53 @@
+package ai
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"ai_automation/domain/interfaces"
+
+	"github.com/sirupsen/logrus"
+)
+
+// defaultProvider - provider used when AI_PROVIDER is not set
+const defaultProvider = "openai"
+
+// ProviderConstructor - creates an AI service for a provider, configuration comes from the environment
+type ProviderConstructor func(logger *logrus.Logger) (interfaces.AIService, error)
+
+// providers - registered AI providers by name
+var providers = map[string]ProviderConstructor{
+	"openai": func(logger *logrus.Logger) (interfaces.AIService, error) {
+		return NewOpenAIClient(logger)
+	},
+}
+
+// RegisterProvider - adds or replaces an AI provider available to NewClient
+func RegisterProvider(name string, constructor ProviderConstructor) {
+	providers[strings.ToLower(name)] = constructor
+}
+
+// SupportedProviders - returns names of registered providers in alphabetical order
+func SupportedProviders() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewClient - creates the AI service for provider (case-insensitive, empty means openai)
+func NewClient(provider string, logger *logrus.Logger) (interfaces.AIService, error) {
+	name := strings.ToLower(strings.TrimSpace(provider))
+	if name == "" {
+		name = defaultProvider
+	}
+
+	constructor, ok := providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown AI provider %q, supported: %s", provider, strings.Join(SupportedProviders(), ", "))
+	}
+	return constructor(logger)
+}
@@ -0,0 +1,35 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"ai_automation/domain/entities"
+	"ai_automation/domain/i18n"
+)
+
+// rollUpHistory - one line counting older actions by type in the order they first happened,
+// e.g. "Earlier (steps 1-35): Navigate x3, Click x20, Scroll x12, 4 of them failed"
+func rollUpHistory(actions []entities.Action) string {
+	counts := map[entities.ActionType]int{}
+	var order []entities.ActionType
+	failed := 0
+	for _, action := range actions {
+		if counts[action.Type] == 0 {
+			order = append(order, action.Type)
+		}
+		counts[action.Type]++
+		if action.Error != "" {
+			failed++
+		}
+	}
+
+	items := make([]string, 0, len(order)+1)
+	for _, actionType := range order {
+		items = append(items, fmt.Sprintf("%s x%d", getActionTypeDescription(actionType), counts[actionType]))
+	}
+	if failed > 0 {
+		items = append(items, i18n.T(i18n.PromptEarlierFailed, failed))
+	}
+	return i18n.T(i18n.PromptEarlierSteps, len(actions), strings.Join(items, ", "))
+}
@@ -0,0 +1,50 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"ai_automation/domain/entities"
+	"ai_automation/domain/i18n"
+)
+
+func TestHistorySummaryStaysBounded(t *testing.T) {
+	i18n.SetLanguage("en")
+	defer i18n.SetLanguage("ru")
+
+	history := func(n int) []entities.Action {
+		actions := make([]entities.Action, n)
+		for i := range actions {
+			actions[i] = entities.Action{Type: entities.ActionClick, Description: fmt.Sprintf("open result %d", i+1), Result: strings.Repeat("r", 200)}
+		}
+		actions[0] = entities.Action{Type: entities.ActionNavigate, Description: "open the shop"}
+		actions[1].Error = "element not found"
+		return actions
+	}
+
+	c := &OpenAIClient{historyWindow: 10}
+	formatted := c.formatHistorySummary(history(50))
+	longer := c.formatHistorySummary(history(500))
+
+	if len(longer) > len(formatted)+50 {
+		t.Errorf("history grows with its length: %d characters for 50 actions, %d for 500", len(formatted), len(longer))
+	}
+	rollup := strings.SplitN(formatted, "\n", 2)[0]
+	if want := "Earlier (steps 1-40): Navigate x1, Click x39, 1 of them failed"; rollup != want {
+		t.Errorf("rollup = %q, want %q", rollup, want)
+	}
+	// The recent steps keep their numbers, descriptions and results
+	for _, want := range []string{"41. ", "open result 41", "50. ", "open result 50", strings.Repeat("r", 200)} {
+		if !strings.Contains(formatted, want) {
+			t.Errorf("recent detail %q missing:\n%s", want, formatted)
+		}
+	}
+	if strings.Contains(formatted, "open result 40\n") || strings.Contains(formatted, "open the shop") {
+		t.Errorf("older actions are listed in detail:\n%s", formatted)
+	}
+
+	if all := (&OpenAIClient{}).formatHistorySummary(history(50)); !strings.Contains(all, "open the shop") {
+		t.Error("a zero window should list every action")
+	}
+}
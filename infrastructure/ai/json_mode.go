@@ -0,0 +1,70 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// callActionAPI - asks the model for the next action using tool calling, or JSON mode when it is
+// enabled. Switches to JSON mode for the rest of the session if the model rejects tools
+func (c *OpenAIClient) callActionAPI(ctx context.Context, prompt string, tools []Tool) (string, error) {
+	if c.jsonMode.Load() {
+		return c.callAPIJSON(ctx, prompt, tools)
+	}
+
+	response, err := c.callAPI(ctx, prompt, tools)
+	if err != nil && isToolsUnsupportedError(err) {
+		c.logger.Warnf("Model %s does not support tool calling, switching to JSON mode", c.model)
+		c.jsonMode.Store(true)
+		return c.callAPIJSON(ctx, prompt, tools)
+	}
+	return response, err
+}
+
+// callAPIJSON - requests a bare JSON action with response_format json_object.
+// The reply has the same {"name", "arguments"} shape as a tool call so parseActionResponse handles both
+func (c *OpenAIClient) callAPIJSON(ctx context.Context, prompt string, tools []Tool) (string, error) {
+	requestBody := c.buildRequestBody(ctx, prompt+"\n\n"+buildJSONModeInstructions(tools), nil)
+	requestBody["response_format"] = map[string]string{"type": "json_object"}
+
+	return c.callAPIWithBody(ctx, requestBody)
+}
+
+// buildJSONModeInstructions - describes available actions and the exact reply format
+func buildJSONModeInstructions(tools []Tool) string {
+	var sb strings.Builder
+	sb.WriteString("Reply with a single JSON object and nothing else (no markdown, no explanations) in the form:\n")
+	sb.WriteString(`{"name": "<action name>", "arguments": {<action arguments>}}`)
+	sb.WriteString("\n\nAvailable actions:\n")
+
+	for _, tool := range tools {
+		sb.WriteString(fmt.Sprintf("- %s: %s\n", tool.Function.Name, tool.Function.Description))
+
+		properties, _ := tool.Function.Parameters["properties"].(map[string]interface{})
+		if len(properties) == 0 {
+			continue
+		}
+		schema, err := json.Marshal(properties)
+		if err != nil {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("  arguments: %s\n", schema))
+		if required, ok := tool.Function.Parameters["required"].([]string); ok && len(required) > 0 {
+			sb.WriteString(fmt.Sprintf("  required: %s\n", strings.Join(required, ", ")))
+		}
+	}
+
+	return sb.String()
+}
+
+// isToolsUnsupportedError - detects API errors returned for models without tool calling
+func isToolsUnsupportedError(err error) bool {
+	message := strings.ToLower(err.Error())
+	if !strings.Contains(message, "tool") {
+		return false
+	}
+	return strings.Contains(message, "not support") || strings.Contains(message, "unsupported") ||
+		strings.Contains(message, "unrecognized request argument")
+}
@@ -0,0 +1,91 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"ai_automation/domain/entities"
+)
+
+// Batch workers share one client: the switch to JSON mode and model escalation must not race,
+// and a stuck task escalates only its own decisions
+func TestConcurrentWorkersWithJSONModeFallback(t *testing.T) {
+	var toolRequests atomic.Int32
+	var mu sync.Mutex
+	var mismatches []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Model    string        `json:"model"`
+			Messages []Message     `json:"messages"`
+			Tools    []interface{} `json:"tools"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if len(body.Tools) > 0 {
+			toolRequests.Add(1)
+			http.Error(w, `{"error": {"message": "this model does not support tools"}}`, http.StatusBadRequest)
+			return
+		}
+
+		want := "routine"
+		if strings.Contains(body.Messages[len(body.Messages)-1].Content, "stuck task") {
+			want = "strong"
+		}
+		if body.Model != want {
+			mu.Lock()
+			mismatches = append(mismatches, fmt.Sprintf("model %s, want %s", body.Model, want))
+			mu.Unlock()
+		}
+		io.WriteString(w, `{"choices": [{"message": {"content": "{\"name\": \"scroll\", \"arguments\": {\"direction\": \"down\"}}"}}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	c := testClient(server, retryPolicy{}, nil)
+	c.model = "routine"
+	c.tier = newModelTier("routine", "strong", 1)
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < 4; worker++ {
+		task := &entities.Task{ID: fmt.Sprintf("task-%d", worker), Description: "healthy task"}
+		if worker == 0 {
+			task.Description = "stuck task"
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for step := 0; step < 5; step++ {
+				// The stuck task fails every step, the others always make progress
+				c.ReportOutcome(task, task.Description != "stuck task")
+				action, err := c.DecideNextAction(context.Background(), task, &entities.PageInfo{URL: "https://example.com"}, nil)
+				if err != nil {
+					t.Errorf("%s: %v", task.ID, err)
+					return
+				}
+				if action.Type != entities.ActionScroll {
+					t.Errorf("%s: action = %s, want scroll", task.ID, action.Type)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if !c.jsonMode.Load() {
+		t.Error("client did not switch to JSON mode")
+	}
+	if n := toolRequests.Load(); n < 1 || n > 4 {
+		t.Errorf("tool requests = %d, want one per worker at most", n)
+	}
+	if len(mismatches) > 0 {
+		t.Errorf("escalation leaked between tasks: %v", mismatches)
+	}
+}
@@ -0,0 +1,82 @@
+package ai
+
+import (
+	"context"
+	"sync"
+
+	"ai_automation/domain/entities"
+)
+
+// modelTier - routine decisions use the cheap model, after repeated failures the strong one
+// takes over until a step succeeds again. Failures are counted per task, so one stuck task
+// of a batch does not escalate the others
+type modelTier struct {
+	mu            sync.Mutex
+	strong        string
+	escalateAfter int
+	// failures - unsuccessful steps in a row by task ID
+	failures map[string]int
+}
+
+// newModelTier - escalates from model to strong after escalateAfter unsuccessful steps,
+// a strong model equal to the routine one disables escalation
+func newModelTier(model string, strong string, escalateAfter int) modelTier {
+	if strong == model {
+		strong = ""
+	}
+	return modelTier{strong: strong, escalateAfter: escalateAfter, failures: make(map[string]int)}
+}
+
+type taskIDKey struct{}
+
+// withTask - marks calls made for a task, the model for them is picked by that task's failures
+func withTask(ctx context.Context, task *entities.Task) context.Context {
+	if task == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, taskIDKey{}, task.ID)
+}
+
+// taskIDFrom - ID of the task the call is made for, empty for calls outside a task
+func taskIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(taskIDKey{}).(string)
+	return id
+}
+
+// activeModel - returns the model for the next call of the task
+func (c *OpenAIClient) activeModel(taskID string) string {
+	c.tier.mu.Lock()
+	defer c.tier.mu.Unlock()
+	if c.tier.strong != "" && c.tier.failures[taskID] >= c.tier.escalateAfter {
+		return c.tier.strong
+	}
+	return c.model
+}
+
+// ReportOutcome - tells the client whether the last decided step of the task made progress,
+// consecutive failures escalate the task's decisions to the strong model
+func (c *OpenAIClient) ReportOutcome(task *entities.Task, success bool) {
+	taskID := ""
+	if task != nil {
+		taskID = task.ID
+	}
+
+	c.tier.mu.Lock()
+	defer c.tier.mu.Unlock()
+
+	if success {
+		if c.tier.strong != "" && c.tier.failures[taskID] >= c.tier.escalateAfter {
+			c.logger.Infof("Step succeeded, returning to model %s", c.model)
+		}
+		delete(c.tier.failures, taskID)
+		return
+	}
+
+	if c.tier.failures == nil {
+		c.tier.failures = make(map[string]int)
+	}
+	c.tier.failures[taskID]++
+	if c.tier.strong != "" && c.tier.failures[taskID] == c.tier.escalateAfter {
+		c.logger.Infof("%d unsuccessful steps in a row, escalating to model %s", c.tier.failures[taskID], c.tier.strong)
+	}
+}
@@ -4,48 +4,183 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"text/template"
+	"unicode/utf8"
 
+	"ai_automation/config"
 	"ai_automation/domain/entities"
+	"ai_automation/domain/i18n"
 	"ai_automation/domain/interfaces"
 
 	"github.com/sirupsen/logrus"
 )
 
+// defaultBaseURL - OpenAI API root, chat completions and model lookups are below it
+const defaultBaseURL = "https://api.openai.com/v1"
+
+const defaultSystemPrompt = "You are an autonomous AI agent that controls a web browser. You must make decisions based on the current page state and task requirements. Always respond with valid JSON when using tools."
+
 type OpenAIClient struct {
-	apiKey string
-	client *http.Client
-	logger *logrus.Logger
-	model  string
+	apiKey  string
+	client  *http.Client
+	baseURL string
+	// retry repeats transient failures, breaker stops calling the API during an outage
+	retry        retryPolicy
+	breaker      *circuitBreaker
+	logger       *logrus.Logger
+	model        string
+	systemPrompt string
+	// maxPageContext limits page elements section of the prompt (in characters)
+	maxPageContext int
+	// maxTextContent and maxTextPreview limit page text sent for extraction and with each step
+	maxTextContent int
+	maxTextPreview int
+	// historyWindow is how many recent actions the prompt lists in detail, zero lists all
+	historyWindow int
+	temperature   float64
+	topP          *float64
+	maxTokens     int
+	// stream enables token streaming for AnalyzePage, partial output goes to streamOutput (set by the caller)
+	stream       bool
+	streamOutput io.Writer
+	// jsonMode asks for a bare JSON action via response_format instead of tool calling,
+	// batch workers may switch it on concurrently
+	jsonMode atomic.Bool
+	cache    DecisionCache
+	// jsEnabled offers the execute_js tool
+	jsEnabled bool
+	// pdfEnabled offers the save_pdf tool, the browser prints to PDF only headless or remote
+	pdfEnabled bool
+	// allowedActions limits advertised tools (ALLOWED_ACTIONS), nil offers all
+	allowedActions entities.ActionSet
+	// secretNames are names of stored secrets the model may type as {{secret:NAME}}
+	secretNames []string
+	// tier escalates to a stronger model when the agent is stuck
+	tier modelTier
+	// promptTemplate renders decision prompts, nil uses the embedded default
+	promptTemplate *template.Template
+	// tokensUsed counts tokens of all calls
+	tokensUsed atomic.Int64
 }
 
 func NewOpenAIClient(logger *logrus.Logger) (*OpenAIClient, error) {
-	apiKey := os.Getenv("OPENAI_API_KEY")
-	if apiKey == "" {
+	cfg := config.FromEnv().AI
+	if cfg.APIKey == "" {
 		return nil, fmt.Errorf("OPENAI_API_KEY environment variable is not set")
 	}
 
-	model := os.Getenv("OPENAI_MODEL")
-	if model == "" {
-		model = "gpt-4o" // Use GPT-4o by default
+	systemPrompt, err := loadSystemPrompt(cfg.SystemPrompt, cfg.SystemPromptMode)
+	if err != nil {
+		return nil, err
+	}
+
+	promptTemplate, err := loadDecisionTemplate(cfg.PromptTemplatePath)
+	if err != nil {
+		return nil, err
+	}
+
+	cache, err := newDecisionCache(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &OpenAIClient{
+		apiKey:         cfg.APIKey,
+		client:         &http.Client{},
+		baseURL:        defaultBaseURL,
+		retry:          retryPolicy{retries: cfg.Retries, backoff: cfg.RetryBackoff, jitter: cfg.RetryJitter},
+		breaker:        newCircuitBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown),
+		logger:         logger,
+		model:          cfg.Model,
+		systemPrompt:   systemPrompt,
+		maxPageContext: cfg.MaxPageContext,
+		maxTextContent: cfg.MaxTextContent,
+		maxTextPreview: cfg.MaxTextPreview,
+		historyWindow:  cfg.HistoryWindow,
+		temperature:    cfg.Temperature,
+		topP:           cfg.TopP,
+		maxTokens:      cfg.MaxTokens,
+		stream:         cfg.Stream,
+		cache:          cache,
+		jsEnabled:      cfg.JSEnabled,
+		pdfEnabled:     cfg.PDFEnabled,
+		allowedActions: cfg.AllowedActions,
+		secretNames:    loadSecretNames(),
+		tier:           newModelTier(cfg.Model, cfg.StrongModel, cfg.EscalateAfter),
+		promptTemplate: promptTemplate,
+	}
+
+	client.jsonMode.Store(cfg.JSONMode)
+
+	if client.tier.strong != "" {
+		logger.Infof("Using model %s, escalating to %s after %d unsuccessful steps", client.model, client.tier.strong, client.tier.escalateAfter)
+	} else {
+		logger.Infof("Using model %s", client.model)
+	}
+
+	return client, nil
+}
+
+// loadSystemPrompt - builds system prompt from AGENT_SYSTEM_PROMPT (inline text or file path).
+// Custom instructions are appended to the default prompt unless AGENT_SYSTEM_PROMPT_MODE=replace
+func loadSystemPrompt(custom string, mode string) (string, error) {
+	if custom == "" {
+		return defaultSystemPrompt, nil
+	}
+
+	if info, err := os.Stat(custom); err == nil && !info.IsDir() {
+		data, err := os.ReadFile(custom)
+		if err != nil {
+			return "", fmt.Errorf("failed to read AGENT_SYSTEM_PROMPT file: %w", err)
+		}
+		custom = strings.TrimSpace(string(data))
+	}
+
+	if mode == "replace" {
+		return custom, nil
 	}
 
-	return &OpenAIClient{
-		apiKey: apiKey,
-		client: &http.Client{},
-		logger: logger,
-		model:  model,
-	}, nil
+	return defaultSystemPrompt + "\n\nAdditional instructions:\n" + custom, nil
+}
+
+// loadSecretNames - lists names of AGENT_SECRET_* variables; values are resolved by the browser
+// controller when typing, so they never reach the model
+func loadSecretNames() []string {
+	names := []string{}
+	for _, entry := range os.Environ() {
+		key, _, _ := strings.Cut(entry, "=")
+		if name, ok := strings.CutPrefix(key, "AGENT_SECRET_"); ok && name != "" {
+			names = append(names, strings.ToUpper(name))
+		}
+	}
+	sort.Strings(names)
+	return names
 }
 
 func (c *OpenAIClient) DecideNextAction(ctx context.Context, task *entities.Task, pageInfo *entities.PageInfo, history []entities.Action) (*entities.Action, error) {
+	ctx = withTask(ctx, task)
 	contextSummary := c.buildContextSummary(pageInfo, history)
 	historySummary := c.formatHistorySummary(history)
 
+	cacheKey := ""
+	if c.cache != nil {
+		cacheKey = c.decisionCacheKey(task, pageInfo, historySummary)
+		if action, ok := c.cache.Get(cacheKey); ok {
+			c.logger.Infof("Using cached decision: %s", action.Type)
+			return action, nil
+		}
+	}
+
 	// Check if extract was used recently - if so, don't allow it again
 	hasRecentExtract := false
 	for i := len(history) - 1; i >= 0 && i >= len(history)-3; i-- {
@@ -88,28 +223,86 @@ func (c *OpenAIClient) DecideNextAction(ctx context.Context, task *entities.Task
 		}
 		tools = filteredTools
 	}
+	if !c.jsEnabled {
+		filteredTools := []Tool{}
+		for _, tool := range tools {
+			if tool.Function.Name != "execute_js" {
+				filteredTools = append(filteredTools, tool)
+			}
+		}
+		tools = filteredTools
+	}
+	if !c.pdfEnabled {
+		filteredTools := []Tool{}
+		for _, tool := range tools {
+			if tool.Function.Name != "save_pdf" {
+				filteredTools = append(filteredTools, tool)
+			}
+		}
+		tools = filteredTools
+	}
+	if !pageInfo.HasMore {
+		filteredTools := []Tool{}
+		for _, tool := range tools {
+			if tool.Function.Name != "more_elements" {
+				filteredTools = append(filteredTools, tool)
+			}
+		}
+		tools = filteredTools
+	}
+	if task.CurrentSubtask() < 0 {
+		// Nothing to mark done without a plan or once every subtask is complete
+		filteredTools := []Tool{}
+		for _, tool := range tools {
+			if tool.Function.Name != "complete_subtask" {
+				filteredTools = append(filteredTools, tool)
+			}
+		}
+		tools = filteredTools
+	}
 
 	prompt := c.buildDecisionPrompt(task, contextSummary, pageInfo, historySummary, hasRecentExtract, hasRecentScrolls)
 
-	response, err := c.callAPI(ctx, prompt, tools)
+	response, err := c.callActionAPI(ctx, prompt, tools)
 	if err != nil {
 		return nil, err
 	}
 
-	// Check if response indicates task completion
-	if response == "" || response == "null" || strings.Contains(strings.ToLower(response), "task complete") ||
-		strings.Contains(strings.ToLower(response), "задача выполнена") {
+	// Empty response means the model has nothing more to do
+	if strings.TrimSpace(response) == "" || strings.TrimSpace(response) == "null" {
 		return nil, nil
 	}
 
 	action, err := c.parseActionResponse(response)
+	var invalidCall *invalidToolCallError
+	if errors.As(err, &invalidCall) {
+		// Give the model one chance to fix its tool call instead of wasting an iteration
+		c.logger.Warnf("Rejected tool call: %v", err)
+		retryPrompt := fmt.Sprintf("%s\n\nYour previous tool call was rejected: %s. Call the tool again with all required arguments of the correct type.", prompt, err)
+		response, err = c.callActionAPI(ctx, retryPrompt, tools)
+		if err != nil {
+			return nil, err
+		}
+		action, err = c.parseActionResponse(response)
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	if c.cache != nil {
+		if err := c.cache.Put(cacheKey, action); err != nil {
+			c.logger.Warnf("Failed to cache decision: %v", err)
+		}
+	}
+
 	return action, nil
 }
 
+// SetDecisionCache - replaces decision cache, nil disables caching
+func (c *OpenAIClient) SetDecisionCache(cache DecisionCache) {
+	c.cache = cache
+}
+
 func (c *OpenAIClient) AnalyzePage(ctx context.Context, pageInfo *entities.PageInfo, task *entities.Task) (string, error) {
 	prompt := fmt.Sprintf(`Analyze this web page and provide a brief summary relevant to the task: "%s"
 
@@ -122,7 +315,7 @@ Links: %d
 Forms: %d
 Buttons: %d
 
-Key visible text (first 500 chars): %s
+Key visible text (first %d chars): %s
 
 Provide a concise analysis focusing on elements that might help complete the task.`,
 		task.Description,
@@ -133,9 +326,14 @@ Provide a concise analysis focusing on elements that might help complete the tas
 		len(pageInfo.Links),
 		len(pageInfo.Forms),
 		len(pageInfo.Buttons),
-		c.truncateText(pageInfo.TextContent, 500),
+		c.maxTextPreview,
+		c.truncateText(pageInfo.TextContent, c.maxTextPreview),
 	)
 
+	if c.stream {
+		return c.callAPIStream(ctx, prompt)
+	}
+
 	response, err := c.callAPI(ctx, prompt, nil)
 	if err != nil {
 		return "", err
@@ -144,6 +342,48 @@ Provide a concise analysis focusing on elements that might help complete the tas
 	return response, nil
 }
 
+func (c *OpenAIClient) ExtractData(ctx context.Context, pageInfo *entities.PageInfo, schema string) (string, error) {
+	var elements strings.Builder
+	for _, elem := range pageInfo.Elements {
+		if elem.Text != "" {
+			elements.WriteString("- " + c.truncateText(elem.Text, 200) + "\n")
+		}
+	}
+
+	prompt := fmt.Sprintf(`Extract structured data from this web page.
+
+Requested data (fields / schema): %s
+
+Page URL: %s
+Page Title: %s
+
+Page text:
+%s
+
+Page elements:
+%s
+
+Return ONLY valid JSON matching the requested fields (use an array for lists of items). Do not wrap it in markdown and do not add explanations. Use null for values that are not present on the page.`,
+		schema,
+		pageInfo.URL,
+		pageInfo.Title,
+		c.truncateText(pageInfo.TextContent, c.maxTextContent),
+		c.truncateText(elements.String(), 4000),
+	)
+
+	response, err := c.callAPI(ctx, prompt, nil)
+	if err != nil {
+		return "", err
+	}
+
+	data := c.extractJSONValue(response)
+	if !json.Valid([]byte(data)) {
+		return "", fmt.Errorf("AI returned invalid JSON for extraction: %s", c.truncateText(response, 200))
+	}
+
+	return data, nil
+}
+
 // Helper methods
 
 func (c *OpenAIClient) buildDecisionPrompt(task *entities.Task, contextSummary string, pageInfo *entities.PageInfo, historySummary string, extractDisabled bool, scrollDisabled bool) string {
@@ -154,56 +394,62 @@ func (c *OpenAIClient) buildDecisionPrompt(task *entities.Task, contextSummary s
 
 	scrollWarning := ""
 	if scrollDisabled {
-		scrollWarning = "\nWARNING: Scroll action was used too many times recently and is now disabled. You MUST click on elements from the list above. The browser will automatically scroll to elements when you click them.\n"
+		scrollWarning = "\nWARNING: Scroll action was used too many times recently and is now disabled. You MUST click on elements from the list above. The browser will automatically scroll to elements when you click them. To load every item of an infinite list use load_all.\n"
 	}
 
-	warnings := extractWarning + scrollWarning
-
-	elementsInfo := c.formatPageElements(pageInfo)
-	if elementsInfo == "Интерактивные элементы не найдены" {
-		elementsInfo = "Попробуйте прокрутить страницу или использовать поиск по тексту элементов"
+	moreHint := ""
+	if pageInfo.HasMore {
+		moreHint = fmt.Sprintf("\nNOTE: The page has more elements than listed (showing page %d). If the element you need is not listed, call more_elements to see the next page.\n", pageInfo.Page+1)
 	}
 
-	return fmt.Sprintf(`You are an autonomous AI agent that controls a web browser to complete user tasks.
-
-Current Task: "%s"
-
-Current Page Context:
-- URL: %s
-- Title: %s
-- %s
-%sAvailable interactive elements on the page:
-%s
+	warnings := extractWarning + scrollWarning + moreHint
 
-History of actions: %s
+	userContext := ""
+	if task.Context != "" {
+		userContext = fmt.Sprintf("\nAdditional context from the user:\n%s\n", task.Context)
+	}
+	userContext += formatPlan(task)
+	userContext += formatVisits(task, pageInfo.URL)
+	if len(c.secretNames) > 0 {
+		userContext += fmt.Sprintf("\nStored secrets (type them with type_text as {{secret:NAME}}, never ask the user for their values): %s\n", strings.Join(c.secretNames, ", "))
+	}
 
-Based on the task, current page state, and action history, decide what action to take next.
+	elementsInfo := c.fitPageElements(pageInfo, task)
+	if elementsInfo == i18n.T(i18n.PromptNoElements) {
+		elementsInfo = i18n.T(i18n.PromptNoElementHint)
+	}
 
-CRITICAL INSTRUCTIONS:
-1. Look at the visible text above - it shows what's actually on the page
-2. The page ALWAYS has interactive elements. All elements are listed above, even if they're not currently visible - the browser will scroll to them automatically when you click.
-3. You MUST use click actions on elements from the list above. Use the selectors provided.
-4. Click on elements that contain text relevant to your task
-5. Look for buttons or icons that might perform actions you need
-6. Use XPath to find elements by text if selector doesn't work: //tr[contains(text(), 'текст')] or //li[contains(text(), 'текст')]
-7. DO NOT use extract - use click on the elements listed above
-8. DO NOT scroll repeatedly - scroll is only for initial page exploration. After scrolling once or twice, you MUST click on elements.
-9. All actions are equal - choose the one that best fits your current task state
-10. Return null only if task is complete
+	var sb strings.Builder
+	data := decisionPromptData{
+		Task:        task.Description,
+		Context:     userContext,
+		URL:         pageInfo.URL,
+		Title:       pageInfo.Title,
+		PageSummary: contextSummary,
+		Warnings:    warnings,
+		Elements:    elementsInfo,
+		History:     historySummary,
+	}
+	if err := c.decisionTemplate().ExecuteTemplate(&sb, decisionTemplateName, data); err != nil {
+		// A custom template is checked at startup, this only fails on data it did not expect
+		c.logger.Warnf("Prompt template failed, using the default one: %v", err)
+		sb.Reset()
+		defaultDecisionTemplate.ExecuteTemplate(&sb, decisionTemplateName, data)
+	}
+	return sb.String()
+}
 
-Respond with a JSON object containing the action to take, or return null/empty if task is complete.`,
-		task.Description,
-		pageInfo.URL,
-		pageInfo.Title,
-		contextSummary,
-		warnings,
-		elementsInfo,
-		historySummary,
-	)
+// decisionTemplate - returns the PROMPT_TEMPLATE_PATH template or the embedded default
+func (c *OpenAIClient) decisionTemplate() *template.Template {
+	if c.promptTemplate != nil {
+		return c.promptTemplate
+	}
+	return defaultDecisionTemplate
 }
 
+// buildTools - returns tools of the actions allowed by ALLOWED_ACTIONS
 func (c *OpenAIClient) buildTools() []Tool {
-	return []Tool{
+	tools := []Tool{
 		{
 			Type: "function",
 			Function: ToolFunction{
@@ -216,164 +462,802 @@ func (c *OpenAIClient) buildTools() []Tool {
 							"type":        "string",
 							"description": "The URL to navigate to",
 						},
+						"new_tab": map[string]interface{}{
+							"type":        "boolean",
+							"description": "Open the URL in a new tab and keep the current page open (e.g. to compare or look something up); a tab already showing the URL is reused. Return with previous_tab or close_tab",
+						},
 						"description": map[string]interface{}{
 							"type":        "string",
 							"description": "Why you are navigating to this URL",
 						},
 					},
-					"required": []string{"url", "description"},
+					"required": []string{"url", "description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "click",
+				Description: "Click on an element",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"selector": map[string]interface{}{
+							"type":        "string",
+							"description": "CSS selector, XPath, or text to identify the element (prefix with css=, xpath=, id= or text= to use only that lookup)",
+						},
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "What you are clicking and why",
+						},
+					},
+					"required": []string{"selector", "description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "right_click",
+				Description: "Right-click an element to open its context menu. Works only for menus rendered by the web page, not native browser menus",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"selector": map[string]interface{}{
+							"type":        "string",
+							"description": "CSS selector, XPath, or text to identify the element (prefix with css=, xpath=, id= or text= to use only that lookup)",
+						},
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "What you are right-clicking and why",
+						},
+					},
+					"required": []string{"selector", "description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "type_text",
+				Description: "Type text into an input field",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"selector": map[string]interface{}{
+							"type":        "string",
+							"description": "CSS selector or XPath to identify the input field (prefix with css=, xpath=, id= or text= to use only that lookup), or the visible label text of the field, e.g. Email",
+						},
+						"text": map[string]interface{}{
+							"type":        "string",
+							"description": "The text to type",
+						},
+						"append": map[string]interface{}{
+							"type":        "boolean",
+							"description": "Keep the current content and type after it (default false: the field is cleared first)",
+						},
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "What you are typing and why",
+						},
+					},
+					"required": []string{"selector", "text", "description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "set_date",
+				Description: "Set a date field or calendar picker, where typing the date does not work",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"selector": map[string]interface{}{
+							"type":        "string",
+							"description": "CSS selector or XPath of the date input, or the visible label text of the field",
+						},
+						"date": map[string]interface{}{
+							"type":        "string",
+							"description": "The date as YYYY-MM-DD, with THH:MM for date and time fields, YYYY-MM for month fields",
+						},
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "Which date you are setting and why",
+						},
+					},
+					"required": []string{"selector", "date", "description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "set_checked",
+				Description: "Check or uncheck a checkbox, radio button or switch. Safe to repeat: a control already in the requested state is not clicked",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"selector": map[string]interface{}{
+							"type":        "string",
+							"description": "CSS selector or XPath of the checkbox or its label, or the visible label text",
+						},
+						"checked": map[string]interface{}{
+							"type":        "boolean",
+							"description": "true to check or select (default), false to uncheck; radio buttons can only be selected",
+						},
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "Which option you are setting and why",
+						},
+					},
+					"required": []string{"selector", "description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "select_option",
+				Description: "Choose options of a select list (dropdown). For a multi-select list pass every option that should end up selected",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"selector": map[string]interface{}{
+							"type":        "string",
+							"description": "CSS selector or XPath of the select element, or the visible label text of the field",
+						},
+						"values": map[string]interface{}{
+							"type":        "array",
+							"items":       map[string]interface{}{"type": "string"},
+							"description": "Visible texts (or values) of the options to select, one for a single-choice list",
+						},
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "What you are choosing and why",
+						},
+					},
+					"required": []string{"selector", "values", "description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "get_select_options",
+				Description: "List all options of a select list, when the form info does not show them (lists outside forms or with many options)",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"selector": map[string]interface{}{
+							"type":        "string",
+							"description": "CSS selector or XPath of the select element, or the visible label text of the field",
+						},
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "Why you need the options",
+						},
+					},
+					"required": []string{"selector", "description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "scroll",
+				Description: "Scroll the page",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"direction": map[string]interface{}{
+							"type":        "string",
+							"description": "Direction: 'down' or 'up'",
+						},
+						"amount": map[string]interface{}{
+							"type":        "integer",
+							"description": "Pixels to scroll",
+						},
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "Why you are scrolling",
+						},
+					},
+					"required": []string{"direction", "description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "extract",
+				Description: "Extract more detailed information from the page. Use ONLY when you cannot find the element you need to click or type into. Prefer using click and type_text actions instead.",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "What specific information you need to extract",
+						},
+					},
+					"required": []string{"description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "load_all",
+				Description: "Load every item of an infinite-scroll list or a list with a 'load more' button by scrolling to the bottom until nothing new appears. Use it before collecting all items instead of scrolling repeatedly",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "Which list you are loading and why",
+						},
+					},
+					"required": []string{"description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "wait",
+				Description: "Wait for a condition or time",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"timeout": map[string]interface{}{
+							"type":        "integer",
+							"description": "Seconds to wait (at most 30)",
+						},
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "Why you are waiting",
+						},
+					},
+					"required": []string{"timeout", "description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "wait_for_navigation",
+				Description: "Wait until the current page finishes loading and its URL settles, e.g. after a redirect",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "Why you are waiting",
+						},
+					},
+					"required": []string{"description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "wait_for_text",
+				Description: "Wait until an element contains the given text. Use on single-page apps where content loads without navigation",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"selector": map[string]interface{}{
+							"type":        "string",
+							"description": "CSS selector or XPath of the element to watch",
+						},
+						"text": map[string]interface{}{
+							"type":        "string",
+							"description": "Text the element must contain",
+						},
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "What you are waiting for",
+						},
+					},
+					"required": []string{"selector", "text", "description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "wait_for_stable",
+				Description: "Wait until an element appears and stops moving or resizing (animations, lazy rendering)",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"selector": map[string]interface{}{
+							"type":        "string",
+							"description": "CSS selector or XPath of the element to watch",
+						},
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "What you are waiting for",
+						},
+					},
+					"required": []string{"selector", "description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "copy",
+				Description: "Copy text to the clipboard (e.g. a value read from a field on the page)",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"text": map[string]interface{}{
+							"type":        "string",
+							"description": "The text to put into the clipboard",
+						},
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "What you are copying and why",
+						},
+					},
+					"required": []string{"text", "description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "paste",
+				Description: "Paste the clipboard contents into an input field",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"selector": map[string]interface{}{
+							"type":        "string",
+							"description": "CSS selector or XPath to identify the input field (prefix with css=, xpath=, id= or text= to use only that lookup)",
+						},
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "Where you are pasting and why",
+						},
+					},
+					"required": []string{"selector", "description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "read_element",
+				Description: "Read the text or an attribute value of a specific element (e.g. a price or a field value)",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"selector": map[string]interface{}{
+							"type":        "string",
+							"description": "CSS selector or XPath to identify the element (prefix with css=, xpath=, id= or text= to use only that lookup)",
+						},
+						"attribute": map[string]interface{}{
+							"type":        "string",
+							"description": "Attribute to read (e.g. 'href', 'value'). Omit to read the element text",
+						},
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "What you are reading and why",
+						},
+					},
+					"required": []string{"selector", "description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "select_text",
+				Description: "Select the whole text of an element, as if dragging the mouse over it. Returns the selected text",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"selector": map[string]interface{}{
+							"type":        "string",
+							"description": "CSS selector or XPath to identify the element (prefix with css=, xpath=, id= or text= to use only that lookup)",
+						},
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "What you are selecting and why",
+						},
+					},
+					"required": []string{"selector", "description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "get_selection",
+				Description: "Read the text currently selected on the page",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "Why you need the selected text",
+						},
+					},
+					"required": []string{"description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "click_at",
+				Description: "Click at viewport coordinates in CSS pixels. Use only for canvas-based or obfuscated UIs where no selector can target the element",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"x": map[string]interface{}{
+							"type":        "integer",
+							"description": "Horizontal offset from the left edge of the viewport",
+						},
+						"y": map[string]interface{}{
+							"type":        "integer",
+							"description": "Vertical offset from the top edge of the viewport",
+						},
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "What you are clicking and why",
+						},
+					},
+					"required": []string{"x", "y", "description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "find_by_role",
+				Description: "Find elements by accessibility role and name (e.g. button named 'Submit'). Returns stable selectors; prefer it when CSS selectors are unreliable",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"role": map[string]interface{}{
+							"type":        "string",
+							"description": "ARIA role: button, link, textbox, checkbox, radio, combobox, heading, listitem, etc.",
+						},
+						"name": map[string]interface{}{
+							"type":        "string",
+							"description": "Accessible name or part of it (visible label, aria-label). Leave empty to list all elements with the role",
+						},
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "What you are looking for and why",
+						},
+					},
+					"required": []string{"role", "description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "find_near_text",
+				Description: "Find the element next to some text, when the target is only identifiable by it, e.g. the Buy button of the product 'iPhone 15' in a list of identical buttons. Returns its selector",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"text": map[string]interface{}{
+							"type":        "string",
+							"description": "Text the element is next to, e.g. a product name",
+						},
+						"role": map[string]interface{}{
+							"type":        "string",
+							"description": "ARIA role of the element: button, link, textbox, checkbox, combobox, etc.",
+						},
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "What you are looking for and why",
+						},
+					},
+					"required": []string{"text", "role", "description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "complete_subtask",
+				Description: "Mark the current subtask of the task plan as achieved and move on to the next one",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"summary": map[string]interface{}{
+							"type":        "string",
+							"description": "What was achieved",
+						},
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "Why the subtask is considered done",
+						},
+					},
+					"required": []string{"description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "more_elements",
+				Description: "Show the next page of interactive elements when the page has more elements than listed",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "What you are looking for",
+						},
+					},
+					"required": []string{"description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "focus_on",
+				Description: "Limit page analysis in the next steps to one container (e.g. the results list or a dialog) to see its elements without the rest of the page. Pass an empty selector to see the whole page again",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"selector": map[string]interface{}{
+							"type":        "string",
+							"description": "CSS selector or XPath of the container, empty or omitted to reset",
+						},
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "Why you focus on this area",
+						},
+					},
+					// selector is optional: an empty one resets the focus and must pass validation
+					"required": []string{"description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "close_tab",
+				Description: "Close the current tab and return to the previous one. Links that open a new tab are followed automatically; close it when you are done with it",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "Why the tab is closed",
+						},
+					},
+					"required": []string{"description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "previous_tab",
+				Description: "Switch back to the previously active tab without closing the current one",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "Why you switch tabs",
+						},
+					},
+					"required": []string{"description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "screenshot",
+				Description: "Save a screenshot of the visible page to a PNG file, e.g. as proof of a completed order",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"name": map[string]interface{}{
+							"type":        "string",
+							"description": "Optional file name without extension",
+						},
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "Why the screenshot is needed",
+						},
+					},
+					"required": []string{"description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "save_pdf",
+				Description: "Save the whole current page as a PDF file, e.g. to archive an invoice or a receipt",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"name": map[string]interface{}{
+							"type":        "string",
+							"description": "Optional file name without extension",
+						},
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "Why the page is saved",
+						},
+					},
+					"required": []string{"description"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        "download_file",
+				Description: "Click a download link or button and wait until the file is saved. Use instead of click when the element downloads a file",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"selector": map[string]interface{}{
+							"type":        "string",
+							"description": "CSS selector or XPath of the download link or button",
+						},
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "What file you are downloading and why",
+						},
+					},
+					"required": []string{"selector", "description"},
 				},
 			},
 		},
 		{
 			Type: "function",
 			Function: ToolFunction{
-				Name:        "click",
-				Description: "Click on an element",
+				Name:        "execute_js",
+				Description: "Run custom JavaScript on the page as a last resort (e.g. to dismiss an overlay no other action can close). Use return to get a value back. Requires user approval",
 				Parameters: map[string]interface{}{
 					"type": "object",
 					"properties": map[string]interface{}{
-						"selector": map[string]interface{}{
+						"script": map[string]interface{}{
 							"type":        "string",
-							"description": "CSS selector, XPath, or text to identify the element",
+							"description": "JavaScript function body, e.g. return document.title;",
 						},
 						"description": map[string]interface{}{
 							"type":        "string",
-							"description": "What you are clicking and why",
+							"description": "What the script does and why no other action works",
 						},
 					},
-					"required": []string{"selector", "description"},
+					"required": []string{"script", "description"},
 				},
 			},
 		},
 		{
 			Type: "function",
 			Function: ToolFunction{
-				Name:        "type_text",
-				Description: "Type text into an input field",
+				Name:        "count_elements",
+				Description: "Check whether an element exists and how many elements match a selector, without reading the whole page again",
 				Parameters: map[string]interface{}{
 					"type": "object",
 					"properties": map[string]interface{}{
 						"selector": map[string]interface{}{
 							"type":        "string",
-							"description": "CSS selector or XPath to identify the input field",
-						},
-						"text": map[string]interface{}{
-							"type":        "string",
-							"description": "The text to type",
+							"description": "CSS selector or XPath to count",
 						},
 						"description": map[string]interface{}{
 							"type":        "string",
-							"description": "What you are typing and why",
+							"description": "What you are checking and why",
 						},
 					},
-					"required": []string{"selector", "text", "description"},
+					"required": []string{"selector", "description"},
 				},
 			},
 		},
 		{
 			Type: "function",
 			Function: ToolFunction{
-				Name:        "scroll",
-				Description: "Scroll the page",
+				Name:        "extract_data",
+				Description: "Extract structured data (e.g. list of product names and prices) from the current page as JSON",
 				Parameters: map[string]interface{}{
 					"type": "object",
 					"properties": map[string]interface{}{
-						"direction": map[string]interface{}{
+						"fields": map[string]interface{}{
 							"type":        "string",
-							"description": "Direction: 'down' or 'up'",
-						},
-						"amount": map[string]interface{}{
-							"type":        "integer",
-							"description": "Pixels to scroll",
+							"description": "Description of the fields or JSON schema to extract, e.g. 'array of {name, price}'",
 						},
 						"description": map[string]interface{}{
 							"type":        "string",
-							"description": "Why you are scrolling",
+							"description": "What data you are extracting and why",
 						},
 					},
-					"required": []string{"direction", "description"},
+					"required": []string{"fields", "description"},
 				},
 			},
 		},
 		{
 			Type: "function",
 			Function: ToolFunction{
-				Name:        "extract",
-				Description: "Extract more detailed information from the page. Use ONLY when you cannot find the element you need to click or type into. Prefer using click and type_text actions instead.",
+				Name:        "ask_user",
+				Description: "Ask the user a question when you need information you cannot find on the page (account choice, 2FA code, preferences)",
 				Parameters: map[string]interface{}{
 					"type": "object",
 					"properties": map[string]interface{}{
+						"question": map[string]interface{}{
+							"type":        "string",
+							"description": "The question to ask the user",
+						},
 						"description": map[string]interface{}{
 							"type":        "string",
-							"description": "What specific information you need to extract",
+							"description": "Why you need this information",
 						},
 					},
-					"required": []string{"description"},
+					"required": []string{"question", "description"},
 				},
 			},
 		},
 		{
 			Type: "function",
 			Function: ToolFunction{
-				Name:        "wait",
-				Description: "Wait for a condition or time",
+				Name:        "complete",
+				Description: "Signal that the task is fully completed and report the final result to the user",
 				Parameters: map[string]interface{}{
 					"type": "object",
 					"properties": map[string]interface{}{
-						"timeout": map[string]interface{}{
-							"type":        "integer",
-							"description": "Seconds to wait",
+						"summary": map[string]interface{}{
+							"type":        "string",
+							"description": "Short summary of what was done and the final answer, if the task asked for one",
 						},
 						"description": map[string]interface{}{
 							"type":        "string",
-							"description": "Why you are waiting",
+							"description": "Why you consider the task complete",
 						},
 					},
-					"required": []string{"timeout", "description"},
+					"required": []string{"summary", "description"},
 				},
 			},
 		},
 	}
-}
-
-func (c *OpenAIClient) callAPI(ctx context.Context, prompt string, tools []Tool) (string, error) {
-	messages := []Message{
-		{
-			Role:    "system",
-			Content: "You are an autonomous AI agent that controls a web browser. You must make decisions based on the current page state and task requirements. Always respond with valid JSON when using tools.",
-		},
-		{
-			Role:    "user",
-			Content: prompt,
-		},
-	}
-
-	requestBody := map[string]interface{}{
-		"model":       c.model,
-		"messages":    messages,
-		"temperature": 0.7,
-	}
 
-	if len(tools) > 0 {
-		requestBody["tools"] = tools
-		requestBody["tool_choice"] = "auto"
+	if c.allowedActions == nil {
+		return tools
 	}
-
-	jsonData, err := json.Marshal(requestBody)
-	if err != nil {
-		return "", err
+	allowed := []Tool{}
+	for _, tool := range tools {
+		if c.allowedActions.Allows(toolActionType(tool.Function.Name)) {
+			allowed = append(allowed, tool)
+		}
 	}
+	return allowed
+}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/chat/completions", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", err
+// toolActionType - action type a tool call turns into, tool names match action types except type_text
+func toolActionType(name string) entities.ActionType {
+	if name == "type_text" {
+		return entities.ActionTypeText
 	}
+	return entities.ActionType(name)
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+func (c *OpenAIClient) callAPI(ctx context.Context, prompt string, tools []Tool) (string, error) {
+	return c.callAPIWithBody(ctx, c.buildRequestBody(ctx, prompt, tools))
+}
 
-	resp, err := c.client.Do(req)
+// callAPIWithBody - sends prepared request body, returns tool call as JSON or message content.
+// Several tool calls in one response come back as a JSON array in the order the model made them
+func (c *OpenAIClient) callAPIWithBody(ctx context.Context, requestBody map[string]interface{}) (string, error) {
+	resp, err := c.sendRequest(ctx, requestBody)
 	if err != nil {
 		return "", err
 	}
@@ -392,6 +1276,7 @@ func (c *OpenAIClient) callAPI(ctx context.Context, prompt string, tools []Tool)
 	if err := json.Unmarshal(body, &apiResponse); err != nil {
 		return "", err
 	}
+	c.tokensUsed.Add(int64(apiResponse.Usage.TotalTokens))
 
 	if len(apiResponse.Choices) == 0 {
 		return "", fmt.Errorf("no response from API")
@@ -401,18 +1286,25 @@ func (c *OpenAIClient) callAPI(ctx context.Context, prompt string, tools []Tool)
 
 	// Handle tool calls
 	if len(choice.Message.ToolCalls) > 0 {
-		toolCall := choice.Message.ToolCalls[0]
-		// Parse arguments JSON string
-		var args map[string]interface{}
-		if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &args); err != nil {
-			return "", fmt.Errorf("failed to parse tool call arguments: %w", err)
+		calls := make([]map[string]interface{}, 0, len(choice.Message.ToolCalls))
+		for _, toolCall := range choice.Message.ToolCalls {
+			// Parse arguments JSON string
+			var args map[string]interface{}
+			if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &args); err != nil {
+				return "", fmt.Errorf("failed to parse tool call arguments: %w", err)
+			}
+			calls = append(calls, map[string]interface{}{
+				"name":      toolCall.Function.Name,
+				"arguments": args,
+			})
 		}
 		// Return the tool call as JSON
-		toolCallJSON := map[string]interface{}{
-			"name":      toolCall.Function.Name,
-			"arguments": args,
+		var jsonData []byte
+		if len(calls) == 1 {
+			jsonData, err = json.Marshal(calls[0])
+		} else {
+			jsonData, err = json.Marshal(calls)
 		}
-		jsonData, err := json.Marshal(toolCallJSON)
 		if err != nil {
 			return "", err
 		}
@@ -422,7 +1314,94 @@ func (c *OpenAIClient) callAPI(ctx context.Context, prompt string, tools []Tool)
 	return choice.Message.Content, nil
 }
 
+// buildRequestBody - builds chat completion request body, the model is picked for the task in ctx
+func (c *OpenAIClient) buildRequestBody(ctx context.Context, prompt string, tools []Tool) map[string]interface{} {
+	messages := []Message{
+		{
+			Role:    "system",
+			Content: c.systemPrompt,
+		},
+		{
+			Role:    "user",
+			Content: prompt,
+		},
+	}
+
+	model := c.activeModel(taskIDFrom(ctx))
+	c.logger.WithField("model", model).Debug("Calling chat completions")
+
+	requestBody := map[string]interface{}{
+		"model":       model,
+		"messages":    messages,
+		"temperature": c.temperature,
+	}
+
+	if c.topP != nil {
+		requestBody["top_p"] = *c.topP
+	}
+	if c.maxTokens > 0 {
+		requestBody["max_tokens"] = c.maxTokens
+	}
+
+	if len(tools) > 0 {
+		requestBody["tools"] = tools
+		requestBody["tool_choice"] = "auto"
+	}
+
+	return requestBody
+}
+
+// sendRequest - sends chat completion request, repeating transient failures, caller must close response body.
+// While the circuit breaker is open it fails with ErrAIUnavailable without calling the API
+func (c *OpenAIClient) sendRequest(ctx context.Context, requestBody map[string]interface{}) (*http.Response, error) {
+	jsonData, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.retry.do(ctx, func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/chat/completions", bytes.NewReader(jsonData))
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+
+		return c.client.Do(req)
+	})
+	// A cancelled task says nothing about the API, it only gives the probe back. A deadline
+	// counts: an API that hangs until every call times out is down
+	if err != nil && errors.Is(ctx.Err(), context.Canceled) {
+		c.breaker.release()
+	} else {
+		c.breaker.record(isTransientFailure(resp, err))
+	}
+	return resp, err
+}
+
 func (c *OpenAIClient) parseActionResponse(response string) (*entities.Action, error) {
+	// Several tool calls: the first is the action, the rest run after it in order
+	if calls, ok := toolCallList(response); ok {
+		var first *entities.Action
+		for _, call := range calls {
+			action, err := c.parseActionResponse(string(call))
+			if err != nil {
+				return nil, err
+			}
+			if first == nil {
+				first = action
+			} else {
+				first.FollowUps = append(first.FollowUps, *action)
+			}
+		}
+		return first, nil
+	}
+
 	// Extract JSON from markdown code blocks if present
 	cleanedResponse := c.extractJSONFromMarkdown(response)
 
@@ -433,6 +1412,10 @@ func (c *OpenAIClient) parseActionResponse(response string) (*entities.Action, e
 	}
 
 	if err := json.Unmarshal([]byte(cleanedResponse), &toolCall); err == nil && toolCall.Name != "" {
+		if err := c.validateToolArguments(toolCall.Name, toolCall.Arguments); err != nil {
+			return nil, err
+		}
+
 		action := &entities.Action{}
 
 		switch toolCall.Name {
@@ -441,11 +1424,19 @@ func (c *OpenAIClient) parseActionResponse(response string) (*entities.Action, e
 			if url, ok := toolCall.Arguments["url"].(string); ok {
 				action.URL = url
 			}
+			if newTab, ok := toolCall.Arguments["new_tab"].(bool); ok {
+				action.NewTab = newTab
+			}
 		case "click":
 			action.Type = entities.ActionClick
 			if selector, ok := toolCall.Arguments["selector"].(string); ok {
 				action.Selector = selector
 			}
+		case "right_click":
+			action.Type = entities.ActionRightClick
+			if selector, ok := toolCall.Arguments["selector"].(string); ok {
+				action.Selector = selector
+			}
 		case "type_text":
 			action.Type = entities.ActionTypeText
 			if selector, ok := toolCall.Arguments["selector"].(string); ok {
@@ -454,13 +1445,192 @@ func (c *OpenAIClient) parseActionResponse(response string) (*entities.Action, e
 			if text, ok := toolCall.Arguments["text"].(string); ok {
 				action.Text = text
 			}
+			if appendText, ok := toolCall.Arguments["append"].(bool); ok {
+				action.Append = appendText
+			}
+		case "set_date":
+			action.Type = entities.ActionSetDate
+			if selector, ok := toolCall.Arguments["selector"].(string); ok {
+				action.Selector = selector
+			}
+			// The date is carried in Text
+			if date, ok := toolCall.Arguments["date"].(string); ok {
+				action.Text = date
+			}
+		case "set_checked":
+			action.Type = entities.ActionSetChecked
+			if selector, ok := toolCall.Arguments["selector"].(string); ok {
+				action.Selector = selector
+			}
+			// Checking is the default, only an explicit false unchecks
+			if checked, ok := toolCall.Arguments["checked"].(bool); ok {
+				action.Uncheck = !checked
+			}
+		case "select_option":
+			action.Type = entities.ActionSelect
+			if selector, ok := toolCall.Arguments["selector"].(string); ok {
+				action.Selector = selector
+			}
+			values, _ := toolCall.Arguments["values"].([]interface{})
+			for _, value := range values {
+				if text, ok := value.(string); ok && text != "" {
+					action.Values = append(action.Values, text)
+				}
+			}
+			// A single value is sometimes sent as a string
+			if value, ok := toolCall.Arguments["values"].(string); ok && value != "" {
+				action.Values = []string{value}
+			}
+		case "get_select_options":
+			action.Type = entities.ActionGetOptions
+			if selector, ok := toolCall.Arguments["selector"].(string); ok {
+				action.Selector = selector
+			}
 		case "scroll":
 			action.Type = entities.ActionScroll
-			// Scroll direction and amount can be handled in the action execution
+			if direction, ok := toolCall.Arguments["direction"].(string); ok {
+				action.Direction = direction
+			}
+			if amount, ok := toolCall.Arguments["amount"].(float64); ok {
+				action.Amount = int(amount)
+			}
 		case "extract":
 			action.Type = entities.ActionExtract
 		case "wait":
 			action.Type = entities.ActionWait
+			if timeout, ok := toolCall.Arguments["timeout"].(float64); ok {
+				action.Timeout = int(timeout)
+			}
+		case "wait_for_navigation":
+			action.Type = entities.ActionWaitLoad
+		case "wait_for_text":
+			action.Type = entities.ActionWaitText
+			if selector, ok := toolCall.Arguments["selector"].(string); ok {
+				action.Selector = selector
+			}
+			if text, ok := toolCall.Arguments["text"].(string); ok {
+				action.Text = text
+			}
+		case "wait_for_stable":
+			action.Type = entities.ActionWaitStable
+			if selector, ok := toolCall.Arguments["selector"].(string); ok {
+				action.Selector = selector
+			}
+		case "copy":
+			action.Type = entities.ActionCopy
+			if text, ok := toolCall.Arguments["text"].(string); ok {
+				action.Text = text
+			}
+		case "paste":
+			action.Type = entities.ActionPaste
+			if selector, ok := toolCall.Arguments["selector"].(string); ok {
+				action.Selector = selector
+			}
+		case "complete":
+			action.Type = entities.ActionComplete
+			// Summary is carried in Text so it can be shown to the user
+			if summary, ok := toolCall.Arguments["summary"].(string); ok {
+				action.Text = summary
+			}
+		case "ask_user":
+			action.Type = entities.ActionAskUser
+			// Question is carried in Text
+			if question, ok := toolCall.Arguments["question"].(string); ok {
+				action.Text = question
+			}
+		case "extract_data":
+			action.Type = entities.ActionExtractData
+			// Requested fields/schema are carried in Text
+			if fields, ok := toolCall.Arguments["fields"].(string); ok {
+				action.Text = fields
+			}
+		case "click_at":
+			action.Type = entities.ActionClickAt
+			if x, ok := toolCall.Arguments["x"].(float64); ok {
+				action.X = int(x)
+			}
+			if y, ok := toolCall.Arguments["y"].(float64); ok {
+				action.Y = int(y)
+			}
+		case "find_by_role":
+			action.Type = entities.ActionFindByRole
+			if role, ok := toolCall.Arguments["role"].(string); ok {
+				action.Role = role
+			}
+			// Accessible name is carried in Text
+			if name, ok := toolCall.Arguments["name"].(string); ok {
+				action.Text = name
+			}
+		case "find_near_text":
+			action.Type = entities.ActionFindNear
+			if role, ok := toolCall.Arguments["role"].(string); ok {
+				action.Role = role
+			}
+			// Anchor text is carried in Text
+			if text, ok := toolCall.Arguments["text"].(string); ok {
+				action.Text = text
+			}
+		case "complete_subtask":
+			action.Type = entities.ActionSubtaskDone
+			if summary, ok := toolCall.Arguments["summary"].(string); ok {
+				action.Text = summary
+			}
+		case "focus_on":
+			action.Type = entities.ActionFocus
+			if selector, ok := toolCall.Arguments["selector"].(string); ok {
+				action.Selector = selector
+			}
+		case "close_tab":
+			action.Type = entities.ActionCloseTab
+		case "previous_tab":
+			action.Type = entities.ActionPrevTab
+		case "screenshot":
+			action.Type = entities.ActionScreenshot
+			// File name is carried in Text
+			if name, ok := toolCall.Arguments["name"].(string); ok {
+				action.Text = name
+			}
+		case "save_pdf":
+			action.Type = entities.ActionSavePDF
+			// File name is carried in Text
+			if name, ok := toolCall.Arguments["name"].(string); ok {
+				action.Text = name
+			}
+		case "download_file":
+			action.Type = entities.ActionDownload
+			if selector, ok := toolCall.Arguments["selector"].(string); ok {
+				action.Selector = selector
+			}
+		case "execute_js":
+			action.Type = entities.ActionExecuteJS
+			// Script is carried in Text
+			if script, ok := toolCall.Arguments["script"].(string); ok {
+				action.Text = script
+			}
+		case "more_elements":
+			action.Type = entities.ActionMoreItems
+		case "count_elements":
+			action.Type = entities.ActionCount
+			if selector, ok := toolCall.Arguments["selector"].(string); ok {
+				action.Selector = selector
+			}
+		case "read_element":
+			action.Type = entities.ActionReadElement
+			if selector, ok := toolCall.Arguments["selector"].(string); ok {
+				action.Selector = selector
+			}
+			if attribute, ok := toolCall.Arguments["attribute"].(string); ok {
+				action.Attribute = attribute
+			}
+		case "select_text":
+			action.Type = entities.ActionSelectText
+			if selector, ok := toolCall.Arguments["selector"].(string); ok {
+				action.Selector = selector
+			}
+		case "get_selection":
+			action.Type = entities.ActionGetSelected
+		case "load_all":
+			action.Type = entities.ActionLoadAll
 		default:
 			return nil, fmt.Errorf("unknown action type: %s", toolCall.Name)
 		}
@@ -481,6 +1651,19 @@ func (c *OpenAIClient) parseActionResponse(response string) (*entities.Action, e
 	return nil, fmt.Errorf("failed to parse response: %s", response)
 }
 
+// toolCallList - splits a JSON array of tool calls, false for anything else (a single call or text)
+func toolCallList(response string) ([]json.RawMessage, bool) {
+	trimmed := strings.TrimSpace(response)
+	if !strings.HasPrefix(trimmed, "[") {
+		return nil, false
+	}
+	var calls []json.RawMessage
+	if err := json.Unmarshal([]byte(trimmed), &calls); err != nil || len(calls) == 0 {
+		return nil, false
+	}
+	return calls, true
+}
+
 // extractJSONFromMarkdown - extracts JSON from markdown code blocks
 func (c *OpenAIClient) extractJSONFromMarkdown(text string) string {
 	// Remove markdown code block markers
@@ -522,6 +1705,35 @@ func (c *OpenAIClient) extractJSONFromMarkdown(text string) string {
 	return text
 }
 
+// extractJSONValue - extracts JSON object or array from AI response text
+func (c *OpenAIClient) extractJSONValue(text string) string {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "```") {
+		text = strings.TrimSpace(strings.TrimPrefix(text, "```json"))
+		text = strings.TrimSpace(strings.TrimPrefix(text, "```"))
+		text = strings.TrimSpace(strings.TrimSuffix(text, "```"))
+	}
+
+	if json.Valid([]byte(text)) {
+		return text
+	}
+
+	start := strings.IndexAny(text, "[{")
+	if start == -1 {
+		return text
+	}
+	closing := "}"
+	if text[start] == '[' {
+		closing = "]"
+	}
+	end := strings.LastIndex(text, closing)
+	if end <= start {
+		return text
+	}
+
+	return text[start : end+1]
+}
+
 func (c *OpenAIClient) mapToAction(data map[string]interface{}) *entities.Action {
 	action := &entities.Action{}
 
@@ -537,6 +1749,30 @@ func (c *OpenAIClient) mapToAction(data map[string]interface{}) *entities.Action
 	if url, ok := data["url"].(string); ok {
 		action.URL = url
 	}
+	if attribute, ok := data["attribute"].(string); ok {
+		action.Attribute = attribute
+	}
+	if role, ok := data["role"].(string); ok {
+		action.Role = role
+	}
+	if x, ok := data["x"].(float64); ok {
+		action.X = int(x)
+	}
+	if y, ok := data["y"].(float64); ok {
+		action.Y = int(y)
+	}
+	if direction, ok := data["direction"].(string); ok {
+		action.Direction = direction
+	}
+	if amount, ok := data["amount"].(float64); ok {
+		action.Amount = int(amount)
+	}
+	if timeout, ok := data["timeout"].(float64); ok {
+		action.Timeout = int(timeout)
+	}
+	if summary, ok := data["summary"].(string); ok && action.Text == "" {
+		action.Text = summary
+	}
 	if desc, ok := data["description"].(string); ok {
 		action.Description = desc
 	}
@@ -560,14 +1796,18 @@ func (c *OpenAIClient) buildContextSummary(pageInfo *entities.PageInfo, history
 	return strings.Join(parts, ", ")
 }
 
-func (c *OpenAIClient) formatPageElements(pageInfo *entities.PageInfo) string {
+func (c *OpenAIClient) formatPageElements(pageInfo *entities.PageInfo, keywords []string) string {
 	var builder strings.Builder
 
+	if pageInfo.Scope != "" {
+		builder.WriteString(i18n.T(i18n.PromptScope, pageInfo.Scope) + "\n\n")
+	}
+
 	// Show visible text content first (helps AI understand page context)
 	if pageInfo.TextContent != "" {
-		textPreview := c.truncateText(pageInfo.TextContent, 500)
+		textPreview := c.truncateText(pageInfo.TextContent, c.maxTextPreview)
 		if len(textPreview) > 0 {
-			builder.WriteString("Видимый текст на странице (первые 500 символов):\n")
+			builder.WriteString(i18n.T(i18n.PromptVisibleText, c.maxTextPreview) + "\n")
 			builder.WriteString(textPreview)
 			builder.WriteString("\n\n")
 		}
@@ -575,13 +1815,18 @@ func (c *OpenAIClient) formatPageElements(pageInfo *entities.PageInfo) string {
 
 	// Format buttons
 	if len(pageInfo.Buttons) > 0 {
-		builder.WriteString("Кнопки:\n")
+		builder.WriteString(i18n.T(i18n.PromptButtons) + "\n")
 		for i, btn := range pageInfo.Buttons {
 			if i >= 50 {
 				break
 			}
-			if btn.Text != "" {
-				builder.WriteString(fmt.Sprintf("  - \"%s\" (селектор: %s)\n", c.truncateText(btn.Text, 100), btn.Selector))
+			text := btn.Text
+			if text == "" {
+				// Icon buttons often have only an aria-label
+				text = btn.AccessibleName
+			}
+			if text != "" {
+				builder.WriteString(i18n.T(i18n.PromptElementLine, c.truncateText(text, 100), btn.Selector) + c.formatAttributes(btn) + disabledMark(btn) + "\n")
 			}
 		}
 		builder.WriteString("\n")
@@ -589,7 +1834,7 @@ func (c *OpenAIClient) formatPageElements(pageInfo *entities.PageInfo) string {
 
 	// Format links
 	if len(pageInfo.Links) > 0 {
-		builder.WriteString("Ссылки:\n")
+		builder.WriteString(i18n.T(i18n.PromptLinks) + "\n")
 		for i, link := range pageInfo.Links {
 			if i >= 60 {
 				break
@@ -599,17 +1844,22 @@ func (c *OpenAIClient) formatPageElements(pageInfo *entities.PageInfo) string {
 				if selector == "" {
 					selector = fmt.Sprintf("a:contains('%s')", c.truncateText(link.Text, 50))
 				}
-				builder.WriteString(fmt.Sprintf("  - \"%s\" (селектор: %s)\n", c.truncateText(link.Text, 100), selector))
+				builder.WriteString(i18n.T(i18n.PromptElementLine, c.truncateText(link.Text, 100), selector) + "\n")
 			}
 		}
 		builder.WriteString("\n")
 	}
 
-	// Format interactive elements (list items, table rows, etc.)
+	// Format interactive elements (list items, table rows, etc.), most task-relevant first
 	if len(pageInfo.Elements) > 0 {
-		builder.WriteString("Интерактивные элементы:\n")
+		elements, marked := rankElements(pageInfo.Elements, keywords)
+		if marked > 0 {
+			builder.WriteString(i18n.T(i18n.PromptRankedHeader) + "\n")
+		} else {
+			builder.WriteString(i18n.T(i18n.PromptElements) + "\n")
+		}
 		count := 0
-		for _, elem := range pageInfo.Elements {
+		for i, elem := range elements {
 			if !elem.IsClickable {
 				continue
 			}
@@ -618,13 +1868,21 @@ func (c *OpenAIClient) formatPageElements(pageInfo *entities.PageInfo) string {
 			}
 			text := elem.Text
 			if text == "" {
-				text = "без текста"
+				text = i18n.T(i18n.PromptNoText)
 			}
 			maxTextLen := 120
 			if elem.TagName == "tr" || elem.TagName == "li" {
 				maxTextLen = 150
 			}
-			builder.WriteString(fmt.Sprintf("  - %s: \"%s\" (селектор: %s)\n", elem.TagName, c.truncateText(text, maxTextLen), elem.Selector))
+			tag := elem.TagName
+			if elem.Role != "" {
+				tag += " role=" + elem.Role
+			}
+			line := i18n.PromptTaggedLine
+			if i < marked {
+				line = i18n.PromptRelevantLine
+			}
+			builder.WriteString(i18n.T(line, tag, c.truncateText(text, maxTextLen), elem.Selector) + c.formatAttributes(elem) + disabledMark(elem) + "\n")
 			count++
 		}
 		builder.WriteString("\n")
@@ -632,12 +1890,12 @@ func (c *OpenAIClient) formatPageElements(pageInfo *entities.PageInfo) string {
 
 	// Format forms and inputs
 	if len(pageInfo.Forms) > 0 {
-		builder.WriteString("Формы и поля ввода:\n")
+		builder.WriteString(i18n.T(i18n.PromptForms) + "\n")
 		for i, form := range pageInfo.Forms {
 			if i >= 5 {
 				break
 			}
-			builder.WriteString(fmt.Sprintf("  Форма (метод: %s, действие: %s):\n", form.Method, form.Action))
+			builder.WriteString(i18n.T(i18n.PromptFormHeader, form.Method, form.Action) + "\n")
 			for _, input := range form.Inputs {
 				label := input.Label
 				if label == "" {
@@ -646,31 +1904,151 @@ func (c *OpenAIClient) formatPageElements(pageInfo *entities.PageInfo) string {
 				if label == "" {
 					label = input.Name
 				}
-				builder.WriteString(fmt.Sprintf("    - Поле \"%s\" (тип: %s, имя: %s)\n", label, input.Type, input.Name))
+				builder.WriteString(i18n.T(i18n.PromptFormField, label, input.Type, input.Name) + "\n")
+				if len(input.Options) > 0 {
+					builder.WriteString(i18n.T(i18n.PromptFormOptions, formatOptions(input.Options)) + "\n")
+				}
 			}
 		}
 		builder.WriteString("\n")
 	}
 
 	if builder.Len() == 0 {
-		return "Интерактивные элементы не найдены. Попробуйте прокрутить страницу."
+		return i18n.T(i18n.PromptNoElements)
 	}
 
 	return builder.String()
 }
 
+// maxPromptOptions - options of a select list shown to the AI, get_select_options lists the rest
+const maxPromptOptions = 20
+
+// formatOptions - quoted option texts of a select list, the ones past maxPromptOptions are counted
+func formatOptions(options []string) string {
+	shown := options
+	if len(shown) > maxPromptOptions {
+		shown = shown[:maxPromptOptions]
+	}
+	quoted := make([]string, len(shown))
+	for i, option := range shown {
+		quoted[i] = strconv.Quote(option)
+	}
+	text := strings.Join(quoted, ", ")
+	if rest := len(options) - len(shown); rest > 0 {
+		text += fmt.Sprintf(" (+%d)", rest)
+	}
+	return text
+}
+
+// maxAttributeChars - how much of an attribute value is shown next to an element
+const maxAttributeChars = 60
+
+// formatAttributes - lists extracted attributes of the element, sorted by name. Empty values,
+// values already in the selector and the role shown with the tag are left out
+func (c *OpenAIClient) formatAttributes(elem entities.PageElement) string {
+	shown := selectorParts(elem.Selector)
+	names := make([]string, 0, len(elem.Attributes))
+	for name, value := range elem.Attributes {
+		value = strings.TrimSpace(value)
+		if value == "" || inSelector(shown, name, value) || (name == "role" && value == elem.Role) {
+			continue
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		value := strings.Join(strings.Fields(elem.Attributes[name]), " ")
+		parts = append(parts, fmt.Sprintf("%s=%q", name, c.truncateText(value, maxAttributeChars)))
+	}
+	return " [" + strings.Join(parts, " ") + "]"
+}
+
+// selectorPattern - #id, .class and [name="value"] parts of a CSS selector
+var selectorPattern = regexp.MustCompile(`([#.])((?:\\.|[\w-])+)|\[([\w-]+)="([^"]*)"\]`)
+
+// selectorParts - what the selector matches on, as "#id", ".class" and "name=value" keys
+// with CSS escapes removed
+func selectorParts(selector string) map[string]bool {
+	parts := make(map[string]bool)
+	for _, match := range selectorPattern.FindAllStringSubmatch(selector, -1) {
+		if match[1] != "" {
+			parts[match[1]+strings.ReplaceAll(match[2], `\`, "")] = true
+		} else {
+			parts[match[3]+"="+match[4]] = true
+		}
+	}
+	return parts
+}
+
+// inSelector - reports an attribute the selector already shows: the element's id, all of its
+// classes or an attribute the selector matches on. A value that merely occurs in the selector
+// (e.g. a short title inside a longer id) is still listed
+func inSelector(parts map[string]bool, name, value string) bool {
+	switch name {
+	case "id":
+		return parts["#"+value]
+	case "class":
+		for _, class := range strings.Fields(value) {
+			if !parts["."+class] {
+				return false
+			}
+		}
+		return true
+	}
+	return parts[name+"="+value]
+}
+
+// disabledMark - suffix flagging a disabled control, so the AI does not try to click it
+func disabledMark(elem entities.PageElement) string {
+	if !elem.IsDisabled {
+		return ""
+	}
+	return i18n.T(i18n.PromptDisabled)
+}
+
+const (
+	// maxHistoryResultChars - how much of an older step's result is repeated in the prompt
+	maxHistoryResultChars = 300
+	// maxLastResultChars - how much of the last step's result is shown, e.g. extracted JSON
+	maxLastResultChars = 3000
+)
+
+// formatHistorySummary - lists the last historyWindow actions in detail, older ones are rolled up into one line
 func (c *OpenAIClient) formatHistorySummary(history []entities.Action) string {
 	if len(history) == 0 {
-		return "Нет выполненных действий"
+		return i18n.T(i18n.PromptNoHistory)
 	}
 
 	var parts []string
-	for i, action := range history {
+	first := 0
+	if c.historyWindow > 0 && len(history) > c.historyWindow {
+		first = len(history) - c.historyWindow
+		parts = append(parts, rollUpHistory(history[:first]))
+	}
+	for i := first; i < len(history); i++ {
+		action := history[i]
 		desc := getActionTypeDescription(action.Type)
 		if action.Description != "" {
 			desc += ": " + action.Description
 		}
-		parts = append(parts, fmt.Sprintf("%d. %s", i+1, desc))
+		line := fmt.Sprintf("%d. %s", i+1, desc)
+
+		// The latest result is what the model usually needs next, older ones are kept short
+		limit := maxHistoryResultChars
+		if i == len(history)-1 {
+			limit = maxLastResultChars
+		}
+		if action.Error != "" {
+			line += "\n" + i18n.T(i18n.PromptHistoryError, c.truncateText(action.Error, limit))
+		} else if action.Result != "" {
+			line += "\n" + i18n.T(i18n.PromptHistoryResult, c.truncateText(action.Result, limit))
+		}
+		parts = append(parts, line)
 	}
 
 	return strings.Join(parts, "\n")
@@ -679,27 +2057,84 @@ func (c *OpenAIClient) formatHistorySummary(history []entities.Action) string {
 func getActionTypeDescription(actionType entities.ActionType) string {
 	switch actionType {
 	case entities.ActionNavigate:
-		return "Переход на страницу"
+		return i18n.T(i18n.HistoryNavigate)
 	case entities.ActionClick:
-		return "Клик"
+		return i18n.T(i18n.HistoryClick)
 	case entities.ActionTypeText:
-		return "Ввод текста"
+		return i18n.T(i18n.HistoryType)
+	case entities.ActionSetDate:
+		return i18n.T(i18n.HistorySetDate)
+	case entities.ActionSetChecked:
+		return i18n.T(i18n.HistorySetChecked)
+	case entities.ActionSelect:
+		return i18n.T(i18n.HistorySelect)
+	case entities.ActionGetOptions:
+		return i18n.T(i18n.HistoryGetOptions)
 	case entities.ActionScroll:
-		return "Прокрутка"
+		return i18n.T(i18n.HistoryScroll)
 	case entities.ActionExtract:
-		return "Извлечение информации"
+		return i18n.T(i18n.HistoryExtract)
 	case entities.ActionWait:
-		return "Ожидание"
+		return i18n.T(i18n.HistoryWait)
+	case entities.ActionCopy:
+		return i18n.T(i18n.HistoryCopy)
+	case entities.ActionPaste:
+		return i18n.T(i18n.HistoryPaste)
+	case entities.ActionReadElement:
+		return i18n.T(i18n.HistoryReadElement)
+	case entities.ActionRightClick:
+		return i18n.T(i18n.HistoryRightClick)
+	case entities.ActionCount:
+		return i18n.T(i18n.HistoryCount)
+	case entities.ActionClickAt:
+		return i18n.T(i18n.HistoryClickAt)
+	case entities.ActionWaitLoad:
+		return i18n.T(i18n.HistoryWaitLoad)
+	case entities.ActionFindByRole:
+		return i18n.T(i18n.HistoryFindByRole)
+	case entities.ActionFindNear:
+		return i18n.T(i18n.HistoryFindNear)
+	case entities.ActionSubtaskDone:
+		return i18n.T(i18n.HistorySubtaskDone)
+	case entities.ActionMoreItems:
+		return i18n.T(i18n.HistoryMoreItems)
+	case entities.ActionExecuteJS:
+		return i18n.T(i18n.HistoryExecuteJS)
+	case entities.ActionDownload:
+		return i18n.T(i18n.HistoryDownload)
+	case entities.ActionScreenshot:
+		return i18n.T(i18n.HistoryScreenshot)
+	case entities.ActionSavePDF:
+		return i18n.T(i18n.HistorySavePDF)
+	case entities.ActionCloseTab:
+		return i18n.T(i18n.HistoryCloseTab)
+	case entities.ActionFocus:
+		return i18n.T(i18n.HistoryFocus)
+	case entities.ActionPrevTab:
+		return i18n.T(i18n.HistoryPrevTab)
+	case entities.ActionWaitText:
+		return i18n.T(i18n.HistoryWaitText)
+	case entities.ActionWaitStable:
+		return i18n.T(i18n.HistoryWaitStable)
+	case entities.ActionSelectText:
+		return i18n.T(i18n.HistorySelectText)
+	case entities.ActionGetSelected:
+		return i18n.T(i18n.HistorySelection)
+	case entities.ActionLoadAll:
+		return i18n.T(i18n.HistoryLoadAll)
+	case entities.ActionComplete:
+		return i18n.T(i18n.HistoryComplete)
 	default:
 		return string(actionType)
 	}
 }
 
+// truncateText - cuts text to maxLen characters (not bytes, so a cut never splits a letter) and marks the cut
 func (c *OpenAIClient) truncateText(text string, maxLen int) string {
-	if len(text) <= maxLen {
+	if utf8.RuneCountInString(text) <= maxLen {
 		return text
 	}
-	return text[:maxLen] + "..."
+	return string([]rune(text)[:maxLen]) + "..."
 }
 
 // API structures
@@ -738,6 +2173,14 @@ type APIResponse struct {
 			ToolCalls []ToolCall `json:"tool_calls,omitempty"`
 		} `json:"message"`
 	} `json:"choices"`
+	Usage struct {
+		TotalTokens int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// TokensUsed - returns total tokens spent by this client so far
+func (c *OpenAIClient) TokensUsed() int {
+	return int(c.tokensUsed.Load())
 }
 
 // Ensure OpenAIClient implements AIService interface
@@ -0,0 +1,326 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"ai_automation/domain/entities"
+	"ai_automation/domain/i18n"
+)
+
+func TestFormatPageElementsFlagsDisabledButton(t *testing.T) {
+	i18n.SetLanguage("en")
+	defer i18n.SetLanguage("ru")
+
+	// Buttons as the extraction script returns them
+	raw := `[
+		{"tag_name": "button", "text": "Pay", "selector": "#pay", "is_visible": true, "is_clickable": true, "is_disabled": true},
+		{"tag_name": "button", "text": "Cancel", "selector": "#cancel", "is_visible": true, "is_clickable": true}
+	]`
+	var buttons []entities.PageElement
+	if err := json.Unmarshal([]byte(raw), &buttons); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !buttons[0].IsDisabled || buttons[1].IsDisabled {
+		t.Fatal("disabled flag decoded wrong, an element without it must count as enabled")
+	}
+
+	c := &OpenAIClient{}
+	formatted := c.formatPageElements(&entities.PageInfo{Buttons: buttons}, nil)
+	mark := i18n.T(i18n.PromptDisabled)
+
+	lines := strings.Split(formatted, "\n")
+	var payLine, cancelLine string
+	for _, line := range lines {
+		switch {
+		case strings.Contains(line, "#pay"):
+			payLine = line
+		case strings.Contains(line, "#cancel"):
+			cancelLine = line
+		}
+	}
+	if !strings.HasSuffix(payLine, mark) {
+		t.Errorf("disabled button line %q is not flagged", payLine)
+	}
+	if cancelLine == "" || strings.Contains(cancelLine, mark) {
+		t.Errorf("enabled button line %q is missing or flagged", cancelLine)
+	}
+}
+
+func TestBuildToolsOffersOnlyAllowedActions(t *testing.T) {
+	c := &OpenAIClient{allowedActions: entities.ParseActionSet("navigate, extract")}
+
+	names := map[string]bool{}
+	for _, tool := range c.buildTools() {
+		names[tool.Function.Name] = true
+	}
+	for _, name := range []string{"navigate", "extract", "complete", "ask_user"} {
+		if !names[name] {
+			t.Errorf("allowed tool %s is missing", name)
+		}
+	}
+	for _, name := range []string{"click", "type_text", "click_at", "execute_js"} {
+		if names[name] {
+			t.Errorf("tool %s is offered although it is not allowed", name)
+		}
+	}
+}
+
+func TestParseTypeTextAppend(t *testing.T) {
+	c := &OpenAIClient{}
+	tests := []struct {
+		response   string
+		wantAppend bool
+	}{
+		{`{"name": "type_text", "arguments": {"selector": "#q", "text": " more", "append": true, "description": "add"}}`, true},
+		{`{"name": "type_text", "arguments": {"selector": "#q", "text": "new", "description": "replace"}}`, false},
+	}
+	for _, tt := range tests {
+		action, err := c.parseActionResponse(tt.response)
+		if err != nil {
+			t.Fatalf("parseActionResponse(%s): %v", tt.response, err)
+		}
+		if action.Type != entities.ActionTypeText || action.Append != tt.wantAppend {
+			t.Errorf("action = %+v, want type with append %v", action, tt.wantAppend)
+		}
+	}
+}
+
+func TestParseNavigateNewTab(t *testing.T) {
+	c := &OpenAIClient{}
+	action, err := c.parseActionResponse(`{"name": "navigate", "arguments": {"url": "https://example.com", "new_tab": true, "description": "look up"}}`)
+	if err != nil {
+		t.Fatalf("parseActionResponse: %v", err)
+	}
+	if action.Type != entities.ActionNavigate || !action.NewTab {
+		t.Errorf("action = %+v, want navigate in a new tab", action)
+	}
+}
+
+func TestParseScrollAndWaitParameters(t *testing.T) {
+	c := &OpenAIClient{}
+	tests := []struct {
+		response string
+		want     entities.Action
+	}{
+		{`{"name": "scroll", "arguments": {"direction": "up", "amount": 1200, "description": "back to the filters"}}`,
+			entities.Action{Type: entities.ActionScroll, Direction: "up", Amount: 1200}},
+		{`{"name": "wait", "arguments": {"timeout": 7, "description": "let the results load"}}`,
+			entities.Action{Type: entities.ActionWait, Timeout: 7}},
+		// Direct JSON actions carry the same parameters
+		{`{"type": "scroll", "direction": "down", "amount": 300}`,
+			entities.Action{Type: entities.ActionScroll, Direction: "down", Amount: 300}},
+	}
+	for _, tt := range tests {
+		action, err := c.parseActionResponse(tt.response)
+		if err != nil {
+			t.Fatalf("parseActionResponse(%s): %v", tt.response, err)
+		}
+		if action.Type != tt.want.Type || action.Direction != tt.want.Direction ||
+			action.Amount != tt.want.Amount || action.Timeout != tt.want.Timeout {
+			t.Errorf("parseActionResponse(%s) = %+v, want %+v", tt.response, action, tt.want)
+		}
+	}
+}
+
+func TestParseSetDate(t *testing.T) {
+	c := &OpenAIClient{}
+	action, err := c.parseActionResponse(`{"name": "set_date", "arguments": {"selector": "Check-in", "date": "2024-07-01", "description": "arrival day"}}`)
+	if err != nil {
+		t.Fatalf("parseActionResponse: %v", err)
+	}
+	if action.Type != entities.ActionSetDate || action.Selector != "Check-in" || action.Text != "2024-07-01" {
+		t.Errorf("action = %+v, want set_date of Check-in to 2024-07-01", action)
+	}
+}
+
+func TestParseSetChecked(t *testing.T) {
+	c := &OpenAIClient{}
+	tests := []struct {
+		response    string
+		wantUncheck bool
+	}{
+		{`{"name": "set_checked", "arguments": {"selector": "I agree to the terms", "description": "accept"}}`, false},
+		{`{"name": "set_checked", "arguments": {"selector": "#newsletter", "checked": false, "description": "no spam"}}`, true},
+	}
+	for _, tt := range tests {
+		action, err := c.parseActionResponse(tt.response)
+		if err != nil {
+			t.Fatalf("parseActionResponse(%s): %v", tt.response, err)
+		}
+		if action.Type != entities.ActionSetChecked || action.Selector == "" || action.Uncheck != tt.wantUncheck {
+			t.Errorf("action = %+v, want set_checked with uncheck %v", action, tt.wantUncheck)
+		}
+	}
+}
+
+func TestParseSelectOption(t *testing.T) {
+	c := &OpenAIClient{}
+	tests := []struct {
+		response string
+		want     []string
+	}{
+		{`{"name": "select_option", "arguments": {"selector": "#toppings", "values": ["Cheese", "Olives"], "description": "pick"}}`, []string{"Cheese", "Olives"}},
+		{`{"name": "select_option", "arguments": {"selector": "Country", "values": "Germany", "description": "pick"}}`, []string{"Germany"}},
+	}
+	for _, tt := range tests {
+		action, err := c.parseActionResponse(tt.response)
+		if err != nil {
+			t.Fatalf("parseActionResponse(%s): %v", tt.response, err)
+		}
+		if action.Type != entities.ActionSelect || action.Selector == "" || strings.Join(action.Values, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("action = %+v, want select_option of %v", action, tt.want)
+		}
+	}
+}
+
+func TestFormatPageElementsListsSelectOptions(t *testing.T) {
+	i18n.SetLanguage("en")
+	defer i18n.SetLanguage("ru")
+
+	var countries []string
+	for i := 0; i < maxPromptOptions+5; i++ {
+		countries = append(countries, fmt.Sprintf("Country %d", i))
+	}
+	form := entities.FormInfo{Inputs: []entities.InputInfo{
+		{Type: "select", Name: "size", Label: "Size", Options: []string{"Small", "Large"}},
+		{Type: "select", Name: "country", Label: "Country", Options: countries},
+	}}
+	formatted := (&OpenAIClient{}).formatPageElements(&entities.PageInfo{Forms: []entities.FormInfo{form}}, nil)
+
+	if !strings.Contains(formatted, `options: "Small", "Large"`+"\n") {
+		t.Errorf("options of the size list are missing:\n%s", formatted)
+	}
+	if !strings.Contains(formatted, `"Country 19" (+5)`) || strings.Contains(formatted, "Country 20") {
+		t.Errorf("long option list is not capped:\n%s", formatted)
+	}
+}
+
+func TestFormatPageElementsCapsVisibleText(t *testing.T) {
+	i18n.SetLanguage("en")
+	defer i18n.SetLanguage("ru")
+
+	c := &OpenAIClient{maxTextPreview: 40}
+	text := strings.Repeat("word ", 100)
+	formatted := c.formatPageElements(&entities.PageInfo{TextContent: text}, nil)
+
+	if !strings.Contains(formatted, i18n.T(i18n.PromptVisibleText, 40)) {
+		t.Errorf("header does not name the configured limit:\n%s", formatted)
+	}
+	if want := text[:40] + "..."; !strings.Contains(formatted, want) || strings.Contains(formatted, text[:41]) {
+		t.Errorf("visible text is not cut at 40 characters:\n%s", formatted)
+	}
+
+	// Cyrillic letters take two bytes each, the limit still counts letters
+	cyrillic := strings.Repeat("слово ", 100)
+	formatted = c.formatPageElements(&entities.PageInfo{TextContent: cyrillic}, nil)
+	runes := []rune(cyrillic)
+	if want := string(runes[:40]) + "..."; !strings.Contains(formatted, want) || strings.Contains(formatted, string(runes[:41])) {
+		t.Errorf("Cyrillic text is not cut at 40 characters:\n%s", formatted)
+	}
+	if !utf8.ValidString(formatted) {
+		t.Error("the cut produced invalid UTF-8")
+	}
+}
+
+func TestFormatPageElementsShowsAttributes(t *testing.T) {
+	i18n.SetLanguage("en")
+	defer i18n.SetLanguage("ru")
+
+	closeButton := entities.PageElement{
+		TagName: "button", Selector: "#close", AccessibleName: "Close dialog", IsVisible: true, IsClickable: true,
+		Attributes: map[string]string{"id": "close", "aria-label": "Close dialog", "title": "  Close\n this  dialog ", "data-track": ""},
+	}
+	formatted := (&OpenAIClient{}).formatPageElements(&entities.PageInfo{Buttons: []entities.PageElement{closeButton}}, nil)
+
+	if !strings.Contains(formatted, `(selector: #close) [aria-label="Close dialog" title="Close this dialog"]`) {
+		t.Errorf("attributes are missing or not normalized:\n%s", formatted)
+	}
+	if strings.Contains(formatted, "id=") || strings.Contains(formatted, "data-track") {
+		t.Errorf("id already in the selector or an empty attribute is listed:\n%s", formatted)
+	}
+}
+
+func TestFormatAttributesComparesSelectorParts(t *testing.T) {
+	c := &OpenAIClient{}
+	tests := []struct {
+		name     string
+		elem     entities.PageElement
+		want     string
+		excluded []string
+	}{
+		{
+			name: "value only occurring inside the id",
+			elem: entities.PageElement{Selector: "#checkout-button", Attributes: map[string]string{
+				"id": "checkout-button", "title": "checkout", "aria-label": "button",
+			}},
+			want:     `[aria-label="button" title="checkout"]`,
+			excluded: []string{"id="},
+		},
+		{
+			name: "classes partly in the selector",
+			elem: entities.PageElement{Selector: "button.btn:nth-of-type(2)", Attributes: map[string]string{
+				"class": "btn primary", "name": "btn",
+			}},
+			want: `[class="btn primary" name="btn"]`,
+		},
+		{
+			name: "all classes and the matched attribute in the selector",
+			elem: entities.PageElement{Selector: `form > .btn.primary[data-testid="pay"]`, Attributes: map[string]string{
+				"class": "primary btn", "data-testid": "pay", "data-qa": "pay",
+			}},
+			want:     `[data-qa="pay"]`,
+			excluded: []string{"class=", "data-testid="},
+		},
+		{
+			name: "escaped id",
+			elem: entities.PageElement{Selector: `#order\:submit`, Attributes: map[string]string{
+				"id": "order:submit", "type": "submit",
+			}},
+			want:     `[type="submit"]`,
+			excluded: []string{"id="},
+		},
+	}
+
+	for _, tt := range tests {
+		got := c.formatAttributes(tt.elem)
+		if !strings.Contains(got, tt.want) {
+			t.Errorf("%s: attributes = %q, want %s", tt.name, got, tt.want)
+		}
+		for _, name := range tt.excluded {
+			if strings.Contains(got, " "+name) || strings.Contains(got, "["+name) {
+				t.Errorf("%s: attributes = %q list %s already shown by the selector", tt.name, got, name)
+			}
+		}
+	}
+}
+
+func TestFormatPageElementsListsDuplicatesOnce(t *testing.T) {
+	i18n.SetLanguage("en")
+	defer i18n.SetLanguage("ru")
+
+	// A link styled as a button and a button that every script picked up
+	signup := entities.PageElement{TagName: "a", Text: "Sign up", Selector: "#signup", IsVisible: true, IsClickable: true}
+	search := entities.PageElement{TagName: "button", Text: "Search", Selector: "#search", IsVisible: true, IsClickable: true}
+	row := entities.PageElement{TagName: "li", Text: "Go developer, remote", Selector: "#job-1", IsVisible: true, IsClickable: true}
+	pageInfo := &entities.PageInfo{
+		Links:    []entities.LinkInfo{{Text: "Sign up", Href: "/signup", Selector: "#signup"}},
+		Buttons:  []entities.PageElement{signup, search},
+		Elements: []entities.PageElement{signup, search, row, {TagName: "li", Text: " Search ", Selector: "#search", IsClickable: true}},
+	}
+	pageInfo.RemoveDuplicates()
+
+	formatted := (&OpenAIClient{}).formatPageElements(pageInfo, nil)
+	for _, selector := range []string{"#signup", "#search", "#job-1"} {
+		if n := strings.Count(formatted, "selector: "+selector+")"); n != 1 {
+			t.Errorf("%s listed %d times, want once:\n%s", selector, n, formatted)
+		}
+	}
+	if len(pageInfo.Links) != 1 || len(pageInfo.Buttons) != 1 || len(pageInfo.Elements) != 1 {
+		t.Errorf("links/buttons/elements = %d/%d/%d, want the link, the search button and the row",
+			len(pageInfo.Links), len(pageInfo.Buttons), len(pageInfo.Elements))
+	}
+}
@@ -0,0 +1,81 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"ai_automation/domain/entities"
+)
+
+// maxPlanSteps - longer plans are cut, the agent still adapts step by step
+const maxPlanSteps = 10
+
+// PlanTask - asks the model to break the task into short ordered subtasks
+func (c *OpenAIClient) PlanTask(ctx context.Context, task *entities.Task) ([]string, error) {
+	userContext := ""
+	if task.Context != "" {
+		userContext = fmt.Sprintf("\nAdditional context from the user:\n%s\n", task.Context)
+	}
+
+	prompt := fmt.Sprintf(`You are planning work for an AI agent that controls a web browser.
+
+Task: "%s"
+%s
+Break the task into 2-%d short, ordered, verifiable subtasks (e.g. "Open the site", "Search for the product", "Add the cheapest item to the cart").
+Simple tasks may have a single subtask.
+
+Return ONLY a JSON array of strings. Do not wrap it in markdown and do not add explanations.`,
+		task.Description,
+		userContext,
+		maxPlanSteps,
+	)
+
+	response, err := c.callAPI(ctx, prompt, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var steps []string
+	if err := json.Unmarshal([]byte(c.extractJSONValue(response)), &steps); err != nil {
+		return nil, fmt.Errorf("AI returned invalid plan: %s", c.truncateText(response, 200))
+	}
+
+	plan := make([]string, 0, len(steps))
+	for _, step := range steps {
+		if step = strings.TrimSpace(step); step != "" && len(plan) < maxPlanSteps {
+			plan = append(plan, step)
+		}
+	}
+	return plan, nil
+}
+
+// formatPlan - renders task plan with progress for the decision prompt, empty when there is no plan
+func formatPlan(task *entities.Task) string {
+	if len(task.Subtasks) == 0 {
+		return ""
+	}
+
+	current := task.CurrentSubtask()
+	var sb strings.Builder
+	sb.WriteString("\nTask plan:\n")
+	for i, subtask := range task.Subtasks {
+		mark := "[ ]"
+		if subtask.Done {
+			mark = "[x]"
+		}
+		line := fmt.Sprintf("%d. %s %s", i+1, mark, subtask.Description)
+		if i == current {
+			line += "  <- CURRENT"
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	if current >= 0 {
+		sb.WriteString(fmt.Sprintf("Focus on subtask %d. When it is achieved, call complete_subtask before moving on.\n", current+1))
+	} else {
+		sb.WriteString("All subtasks are done - verify the result and call complete.\n")
+	}
+	return sb.String()
+}
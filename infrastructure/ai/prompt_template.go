@@ -0,0 +1,63 @@
+package ai
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"text/template"
+)
+
+//go:embed prompts/decision.tmpl
+var defaultDecisionTemplateText string
+
+// decisionTemplateName - template rendered for every decision, the other sections are called from it
+const decisionTemplateName = "decision"
+
+// defaultDecisionTemplate - embedded template, used when PROMPT_TEMPLATE_PATH is not set
+var defaultDecisionTemplate = template.Must(template.New("default").Parse(defaultDecisionTemplateText))
+
+// decisionPromptData - values available to the decision template
+type decisionPromptData struct {
+	Task string
+	// Context holds user answers, the plan and stored secret names, each block starting with a newline
+	Context     string
+	URL         string
+	Title       string
+	PageSummary string
+	// Warnings are disabled-action warnings and the more_elements hint, empty when there are none
+	Warnings string
+	Elements string
+	History  string
+}
+
+// loadDecisionTemplate - parses the PROMPT_TEMPLATE_PATH file over the embedded template, so it may
+// redefine single sections or the whole decision template. The result is rendered once with sample
+// data to catch unknown fields before the first task
+func loadDecisionTemplate(path string) (*template.Template, error) {
+	if path == "" {
+		return defaultDecisionTemplate, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PROMPT_TEMPLATE_PATH: %w", err)
+	}
+
+	tmpl, err := template.Must(defaultDecisionTemplate.Clone()).New("custom").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid prompt template %s: %w", path, err)
+	}
+	sample := decisionPromptData{
+		Task:        "sample task",
+		URL:         "https://example.com",
+		Title:       "Example",
+		PageSummary: "Visible text: sample",
+		Elements:    "- button: OK",
+		History:     "none",
+	}
+	if err := tmpl.ExecuteTemplate(io.Discard, decisionTemplateName, sample); err != nil {
+		return nil, fmt.Errorf("invalid prompt template %s: %w", path, err)
+	}
+	return tmpl, nil
+}
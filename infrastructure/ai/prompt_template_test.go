@@ -0,0 +1,109 @@
+package ai
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"ai_automation/domain/entities"
+)
+
+func TestDecisionPromptRendersSections(t *testing.T) {
+	c := &OpenAIClient{}
+	task := &entities.Task{Description: "find the cheapest flight", Context: "Q: From where?\nA: Berlin"}
+	pageInfo := &entities.PageInfo{
+		URL:     "https://flights.example/search",
+		Title:   "Flight search",
+		Buttons: []entities.PageElement{{TagName: "button", Text: "Search", Selector: "#search", IsVisible: true}},
+		HasMore: true,
+	}
+
+	prompt := c.buildDecisionPrompt(task, "Visible text: flights", pageInfo, "1. navigate", true, false)
+	for _, want := range []string{
+		`Current Task: "find the cheapest flight"`,
+		"A: Berlin",
+		"- URL: https://flights.example/search",
+		"- Title: Flight search",
+		"Visible text: flights",
+		"#search",
+		"History of actions: 1. navigate",
+		"WARNING: Extract action was recently used",
+		"call more_elements",
+		"CRITICAL INSTRUCTIONS:",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt is missing %q:\n%s", want, prompt)
+		}
+	}
+}
+
+func TestDecisionPromptWarnsAboutRevisitedPages(t *testing.T) {
+	c := &OpenAIClient{}
+	task := &entities.Task{Description: "find a cheap item"}
+	pageInfo := &entities.PageInfo{URL: "https://shop.example/list"}
+
+	task.RecordVisit("https://shop.example/list")
+	if prompt := c.buildDecisionPrompt(task, "", pageInfo, "none", false, false); strings.Contains(prompt, "Pages already visited") {
+		t.Errorf("the only page so far is listed as visited:\n%s", prompt)
+	}
+
+	task.RecordVisit("https://shop.example/item/1")
+	task.Visit("https://shop.example/item/1").Note = "Clicked Add to cart: failed"
+	task.RecordVisit("https://shop.example/list")
+	prompt := c.buildDecisionPrompt(task, "", pageInfo, "none", false, false)
+	for _, want := range []string{
+		"- https://shop.example/list (visits: 2) <- CURRENT",
+		"- https://shop.example/item/1 (visits: 1) - last action: Clicked Add to cart: failed",
+		"WARNING: you keep coming back to https://shop.example/list without finishing the task",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt is missing %q:\n%s", want, prompt)
+		}
+	}
+}
+
+func TestCustomTemplateOverridesSection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prompt.tmpl")
+	custom := `{{define "instructions"}}Pick the cheapest option on {{.URL}}.{{end}}`
+	if err := os.WriteFile(path, []byte(custom), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl, err := loadDecisionTemplate(path)
+	if err != nil {
+		t.Fatalf("loadDecisionTemplate: %v", err)
+	}
+	c := &OpenAIClient{promptTemplate: tmpl}
+	prompt := c.buildDecisionPrompt(&entities.Task{Description: "buy"}, "", &entities.PageInfo{URL: "https://shop.example"}, "none", false, false)
+
+	if !strings.Contains(prompt, "Pick the cheapest option on https://shop.example.") {
+		t.Errorf("custom section not used:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "CRITICAL INSTRUCTIONS") {
+		t.Error("default instructions were not replaced")
+	}
+	if !strings.Contains(prompt, `Current Task: "buy"`) {
+		t.Error("sections the file does not redefine were lost")
+	}
+}
+
+func TestInvalidTemplateRejectedAtLoad(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]string{
+		"syntax error":  `{{define "task"}}{{.Task}{{end}}`,
+		"unknown field": `{{define "history"}}{{.Steps}}{{end}}`,
+	}
+	for name, content := range tests {
+		path := filepath.Join(dir, strings.ReplaceAll(name, " ", "_")+".tmpl")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadDecisionTemplate(path); err == nil {
+			t.Errorf("%s: template was accepted", name)
+		}
+	}
+	if _, err := loadDecisionTemplate(filepath.Join(dir, "missing.tmpl")); err == nil {
+		t.Error("missing template file was accepted")
+	}
+}
@@ -0,0 +1,157 @@
+package ai
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"ai_automation/domain/entities"
+)
+
+// taskKeywords - splits task description into lowercase words useful for matching
+func taskKeywords(description string) []string {
+	words := strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	seen := map[string]bool{}
+	keywords := []string{}
+	for _, word := range words {
+		// Short words are mostly prepositions and conjunctions
+		if len([]rune(word)) < 3 || seen[word] {
+			continue
+		}
+		seen[word] = true
+		keywords = append(keywords, word)
+	}
+
+	return keywords
+}
+
+// relevanceScore - counts how many task keywords occur in the given texts
+func relevanceScore(keywords []string, texts ...string) int {
+	if len(keywords) == 0 {
+		return 0
+	}
+
+	combined := strings.ToLower(strings.Join(texts, " "))
+	score := 0
+	for _, keyword := range keywords {
+		if strings.Contains(combined, keyword) {
+			score++
+		}
+	}
+
+	return score
+}
+
+// fitPageElements - formats page elements, dropping the least task-relevant ones
+// until the result fits into the configured page context budget
+func (c *OpenAIClient) fitPageElements(pageInfo *entities.PageInfo, task *entities.Task) string {
+	keywords := taskKeywords(task.Description)
+	formatted := c.formatPageElements(pageInfo, keywords)
+	if c.maxPageContext <= 0 || len(formatted) <= c.maxPageContext {
+		return formatted
+	}
+
+	type candidate struct {
+		kind  int // 0 - button, 1 - link, 2 - element
+		index int
+		score int
+	}
+
+	candidates := []candidate{}
+	for i, btn := range pageInfo.Buttons {
+		candidates = append(candidates, candidate{0, i, relevanceScore(keywords, btn.Text, btn.Selector)})
+	}
+	for i, link := range pageInfo.Links {
+		candidates = append(candidates, candidate{1, i, relevanceScore(keywords, link.Text, link.Href)})
+	}
+	for i, elem := range pageInfo.Elements {
+		candidates = append(candidates, candidate{2, i, relevanceScore(keywords, elem.Text, elem.Placeholder, elem.Selector)})
+	}
+
+	// Least relevant first; among equals drop those further down the page first
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score < candidates[j].score
+		}
+		return candidates[i].index > candidates[j].index
+	})
+
+	dropped := [3]map[int]bool{{}, {}, {}}
+	trimmed := *pageInfo
+	step := len(candidates)/10 + 1
+
+	for removed := 0; removed < len(candidates); {
+		for i := 0; i < step && removed < len(candidates); i++ {
+			cand := candidates[removed]
+			dropped[cand.kind][cand.index] = true
+			removed++
+		}
+
+		trimmed.Buttons = filterElements(pageInfo.Buttons, dropped[0])
+		trimmed.Links = filterLinks(pageInfo.Links, dropped[1])
+		trimmed.Elements = filterElements(pageInfo.Elements, dropped[2])
+
+		formatted = c.formatPageElements(&trimmed, keywords)
+		if len(formatted) <= c.maxPageContext {
+			return formatted
+		}
+	}
+
+	return c.truncateText(formatted, c.maxPageContext)
+}
+
+// maxMarkedElements - how many best matching elements are highlighted for the AI
+const maxMarkedElements = 5
+
+// rankElements - orders elements by relevance to task keywords, keeping page order among equals.
+// Returns the ranked copy and how many leading elements are marked as the best matches
+func rankElements(elements []entities.PageElement, keywords []string) ([]entities.PageElement, int) {
+	if len(keywords) == 0 {
+		return elements, 0
+	}
+
+	scores := make([]int, len(elements))
+	order := make([]int, len(elements))
+	for i, elem := range elements {
+		order[i] = i
+		scores[i] = relevanceScore(keywords, elem.Text, elem.Placeholder, elem.AccessibleName, elem.Selector)
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+
+	ranked := make([]entities.PageElement, len(elements))
+	marked, markedClickable := 0, 0
+	for i, index := range order {
+		ranked[i] = elements[index]
+		// Only clickable elements are listed, so count marks among them
+		if scores[index] > 0 && markedClickable < maxMarkedElements && elements[index].IsClickable {
+			markedClickable++
+			marked = i + 1
+		}
+	}
+	return ranked, marked
+}
+
+func filterElements(elements []entities.PageElement, dropped map[int]bool) []entities.PageElement {
+	result := make([]entities.PageElement, 0, len(elements))
+	for i, elem := range elements {
+		if !dropped[i] {
+			result = append(result, elem)
+		}
+	}
+	return result
+}
+
+func filterLinks(links []entities.LinkInfo, dropped map[int]bool) []entities.LinkInfo {
+	result := make([]entities.LinkInfo, 0, len(links))
+	for i, link := range links {
+		if !dropped[i] {
+			result = append(result, link)
+		}
+	}
+	return result
+}
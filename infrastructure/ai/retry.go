@@ -0,0 +1,57 @@
+package ai
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// retryPolicy - how failed API calls are repeated. Delays double from backoff, and a random
+// jitter is added so parallel tasks hit by the same outage don't retry in lockstep
+type retryPolicy struct {
+	// retries is the number of repeats after the first attempt
+	retries int
+	backoff time.Duration
+	jitter  time.Duration
+}
+
+// delay - wait before repeat number attempt (0 for the first repeat)
+func (p retryPolicy) delay(attempt int) time.Duration {
+	delay := p.backoff << attempt
+	if p.jitter > 0 {
+		delay += rand.N(p.jitter)
+	}
+	return delay
+}
+
+// isTransientFailure - reports whether the call failed in a way worth repeating: no response,
+// rate limiting or a server error. Other statuses are answers, repeating them changes nothing
+func isTransientFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// do - calls send until it succeeds, fails permanently or retries run out. Bodies of repeated
+// responses are closed, the last response is returned for the caller to read
+func (p retryPolicy) do(ctx context.Context, send func() (*http.Response, error)) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := send()
+		if !isTransientFailure(resp, err) || attempt >= p.retries || ctx.Err() != nil {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(p.delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
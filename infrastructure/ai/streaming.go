@@ -0,0 +1,105 @@
+package ai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// streamChunk - single server-sent event payload of a streamed chat completion
+type streamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	// Usage comes in the last chunk when stream_options.include_usage is set
+	Usage *struct {
+		TotalTokens int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// SetStreamOutput - sets where streamed AnalyzePage tokens are written, nil turns live output off
+func (c *OpenAIClient) SetStreamOutput(w io.Writer) {
+	c.streamOutput = w
+}
+
+// Streams - reports whether AnalyzePage output is already printed while streaming
+func (c *OpenAIClient) Streams() bool {
+	return c.stream && c.streamOutput != nil
+}
+
+// callAPIStream - requests a streamed completion, writes partial tokens to c.streamOutput
+// as they arrive and returns the assembled content
+func (c *OpenAIClient) callAPIStream(ctx context.Context, prompt string) (string, error) {
+	requestBody := c.buildRequestBody(ctx, prompt, nil)
+	requestBody["stream"] = true
+	requestBody["stream_options"] = map[string]bool{"include_usage": true}
+
+	resp, err := c.sendRequest(ctx, requestBody)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("API error: %s - %s", resp.Status, string(body))
+	}
+
+	content, tokens, err := readStream(resp.Body, c.streamOutput)
+	c.tokensUsed.Add(int64(tokens))
+	if c.streamOutput != nil {
+		fmt.Fprintln(c.streamOutput)
+	}
+	return content, err
+}
+
+// readStream - parses SSE "data:" lines and concatenates delta contents, also returns tokens
+// reported in the usage chunk
+func readStream(body io.Reader, output io.Writer) (string, int, error) {
+	var content strings.Builder
+	tokens := 0
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk streamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return content.String(), tokens, fmt.Errorf("failed to parse stream chunk: %w", err)
+		}
+		if chunk.Usage != nil {
+			tokens = chunk.Usage.TotalTokens
+		}
+
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content == "" {
+				continue
+			}
+			content.WriteString(choice.Delta.Content)
+			if output != nil {
+				fmt.Fprint(output, choice.Delta.Content)
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return content.String(), tokens, err
+	}
+
+	return content.String(), tokens, nil
+}
@@ -0,0 +1,71 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ai_automation/domain/entities"
+)
+
+func TestDecideNextActionKeepsEveryToolCall(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"choices": [{"message": {"tool_calls": [
+			{"id": "call_1", "type": "function", "function": {"name": "type_text", "arguments": "{\"selector\": \"#q\", \"text\": \"go jobs\", \"description\": \"enter the query\"}"}},
+			{"id": "call_2", "type": "function", "function": {"name": "click", "arguments": "{\"selector\": \"#search\", \"description\": \"run the search\"}"}}
+		]}}]}`)
+	}))
+	t.Cleanup(server.Close)
+	c := testClient(server, retryPolicy{}, nil)
+
+	task := &entities.Task{ID: "task", Description: "search for Go jobs"}
+	action, err := c.DecideNextAction(context.Background(), task, &entities.PageInfo{URL: "https://jobs.example.com"}, nil)
+	if err != nil {
+		t.Fatalf("DecideNextAction: %v", err)
+	}
+	if action.Type != entities.ActionTypeText || action.Selector != "#q" || action.Text != "go jobs" {
+		t.Errorf("action = %+v, want the first tool call", action)
+	}
+	if len(action.FollowUps) != 1 || action.FollowUps[0].Type != entities.ActionClick || action.FollowUps[0].Selector != "#search" {
+		t.Errorf("follow-ups = %+v, want the click of the second tool call", action.FollowUps)
+	}
+}
+
+func TestSavePDFOfferedOnlyWhenBrowserCanPrint(t *testing.T) {
+	var offered map[string]bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Tools []Tool `json:"tools"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		offered = map[string]bool{}
+		for _, tool := range body.Tools {
+			offered[tool.Function.Name] = true
+		}
+		io.WriteString(w, `{"choices": [{"message": {"content": "{\"name\": \"complete\", \"arguments\": {\"summary\": \"done\"}}"}}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	task := &entities.Task{ID: "task", Description: "save the invoice"}
+	for _, pdfEnabled := range []bool{false, true} {
+		c := testClient(server, retryPolicy{}, nil)
+		c.pdfEnabled = pdfEnabled
+		if _, err := c.DecideNextAction(context.Background(), task, &entities.PageInfo{URL: "https://example.com"}, nil); err != nil {
+			t.Fatalf("DecideNextAction: %v", err)
+		}
+		if offered["save_pdf"] != pdfEnabled || !offered["screenshot"] {
+			t.Errorf("PDF enabled %v: save_pdf offered = %v", pdfEnabled, offered["save_pdf"])
+		}
+	}
+}
+
+func TestParseToolCallListRejectsInvalidCall(t *testing.T) {
+	c := &OpenAIClient{}
+	response := `[{"name": "click", "arguments": {"selector": "#ok", "description": "confirm"}}, {"name": "navigate", "arguments": {"description": "no url"}}]`
+	if _, err := c.parseActionResponse(response); err == nil {
+		t.Error("a list with an invalid tool call was accepted")
+	}
+}
@@ -0,0 +1,48 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Validate - checks the API key and models with a cheap model lookup, without spending tokens.
+// The strong model is checked too, so a typo surfaces at startup rather than on the first escalation
+func (c *OpenAIClient) Validate(ctx context.Context) error {
+	if err := c.validateModel(ctx, c.model, "OPENAI_MODEL"); err != nil {
+		return err
+	}
+	if c.tier.strong != "" {
+		return c.validateModel(ctx, c.tier.strong, "OPENAI_MODEL_STRONG")
+	}
+	return nil
+}
+
+// validateModel - looks up one model, envName tells the user which setting to fix
+func (c *OpenAIClient) validateModel(ctx context.Context, model, envName string) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/models/"+url.PathEscape(model), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach OpenAI API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized:
+		return fmt.Errorf("OPENAI_API_KEY was rejected by the API (401), check that the key is correct and active")
+	case http.StatusNotFound:
+		return fmt.Errorf("model %q is not available for this API key, check %s", model, envName)
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("API key validation failed with status %d: %s", resp.StatusCode, string(body))
+	}
+}
@@ -0,0 +1,89 @@
+package ai
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// invalidToolCallError - tool call arguments do not match the tool schema.
+// The message is written for the model so it can be sent back as retry feedback
+type invalidToolCallError struct {
+	tool     string
+	problems []string
+}
+
+func (e *invalidToolCallError) Error() string {
+	return fmt.Sprintf("invalid arguments for %s: %s", e.tool, strings.Join(e.problems, "; "))
+}
+
+// validateToolArguments - checks tool call arguments against the schema from buildTools:
+// required arguments must be present and non-empty, known arguments must have the declared type.
+// description is not enforced since it is only used for logging
+func (c *OpenAIClient) validateToolArguments(name string, args map[string]interface{}) error {
+	var parameters map[string]interface{}
+	for _, tool := range c.buildTools() {
+		if tool.Function.Name == name {
+			parameters = tool.Function.Parameters
+			break
+		}
+	}
+	if parameters == nil {
+		return nil
+	}
+
+	problems := []string{}
+	properties, _ := parameters["properties"].(map[string]interface{})
+
+	if required, ok := parameters["required"].([]string); ok {
+		for _, field := range required {
+			if field == "description" {
+				continue
+			}
+			value, present := args[field]
+			if !present || value == nil {
+				problems = append(problems, fmt.Sprintf("missing required argument %q", field))
+				continue
+			}
+			if text, ok := value.(string); ok && strings.TrimSpace(text) == "" {
+				problems = append(problems, fmt.Sprintf("argument %q must not be empty", field))
+			}
+		}
+	}
+
+	for field, value := range args {
+		property, ok := properties[field].(map[string]interface{})
+		if !ok || value == nil {
+			continue
+		}
+		expected, _ := property["type"].(string)
+		if !matchesSchemaType(value, expected) {
+			problems = append(problems, fmt.Sprintf("argument %q must be %s, got %T", field, expected, value))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &invalidToolCallError{tool: name, problems: problems}
+	}
+	return nil
+}
+
+// matchesSchemaType - checks decoded JSON value against JSON schema type name
+func matchesSchemaType(value interface{}, schemaType string) bool {
+	switch schemaType {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	default:
+		return true
+	}
+}
@@ -0,0 +1,51 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"ai_automation/domain/entities"
+)
+
+// VerifyCompletion - asks the model whether the page confirms that the task is really done
+func (c *OpenAIClient) VerifyCompletion(ctx context.Context, task *entities.Task, pageInfo *entities.PageInfo, summary string) (bool, string, error) {
+	prompt := fmt.Sprintf(`An AI agent controlling a web browser reports that it has completed a task. Check the claim against the current page.
+
+Task: "%s"
+Agent's summary of the result: %s
+
+Page URL: %s
+Page Title: %s
+
+Page text:
+%s
+
+Page elements:
+%s
+
+Given the original task, is it truly complete based on this page? Be strict: a form that was filled but not submitted, a search that was typed but not run or a result that is not visible means the task is not complete.
+
+Return ONLY a JSON object {"complete": true or false, "reason": "one sentence explaining why"}. Do not wrap it in markdown.`,
+		task.Description,
+		summary,
+		pageInfo.URL,
+		pageInfo.Title,
+		c.truncateText(pageInfo.TextContent, c.maxTextContent),
+		c.truncateText(c.formatPageElements(pageInfo, nil), 4000),
+	)
+
+	response, err := c.callAPI(ctx, prompt, nil)
+	if err != nil {
+		return false, "", err
+	}
+
+	var verdict struct {
+		Complete bool   `json:"complete"`
+		Reason   string `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(c.extractJSONValue(response)), &verdict); err != nil {
+		return false, "", fmt.Errorf("AI returned invalid verification: %s", c.truncateText(response, 200))
+	}
+	return verdict.Complete, verdict.Reason, nil
+}
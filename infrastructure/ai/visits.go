@@ -0,0 +1,46 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"ai_automation/domain/entities"
+)
+
+// maxPromptVisits - most recent visited pages listed in the prompt
+const maxPromptVisits = 10
+
+// formatVisits - pages the task already visited with what was done there, and a warning about the
+// ones it keeps returning to, so the AI does not loop between pages. Empty until there is more
+// than the current page to tell about
+func formatVisits(task *entities.Task, currentURL string) string {
+	visits := task.Visits
+	if len(visits) == 0 || (len(visits) == 1 && visits[0].Count < 2) {
+		return ""
+	}
+	if len(visits) > maxPromptVisits {
+		visits = visits[len(visits)-maxPromptVisits:]
+	}
+
+	current := task.Visit(currentURL)
+	var sb strings.Builder
+	var revisited []string
+	sb.WriteString("\nPages already visited in this task:\n")
+	for _, visit := range visits {
+		line := fmt.Sprintf("- %s (visits: %d)", visit.URL, visit.Count)
+		if current != nil && visit.URL == current.URL {
+			line += " <- CURRENT"
+		}
+		if visit.Note != "" {
+			line += " - last action: " + visit.Note
+		}
+		sb.WriteString(line + "\n")
+		if visit.Count >= 2 {
+			revisited = append(revisited, visit.URL)
+		}
+	}
+	if len(revisited) > 0 {
+		sb.WriteString(fmt.Sprintf("WARNING: you keep coming back to %s without finishing the task. Do not go there again unless the task needs something new from it, try a different page or approach.\n", strings.Join(revisited, ", ")))
+	}
+	return sb.String()
+}
@@ -0,0 +1,143 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"ai_automation/domain/entities"
+)
+
+// accessibilityHelpersScript - JS helpers computing ARIA role, accessible name and enabled state.
+// Injected into extraction scripts so every PageElement carries role, name and is_disabled
+const accessibilityHelpersScript = `
+	function ariaRole(el) {
+		const explicit = (el.getAttribute('role') || '').trim().split(/\s+/)[0];
+		if (explicit) return explicit.toLowerCase();
+		const tag = el.tagName.toLowerCase();
+		const type = (el.getAttribute('type') || 'text').toLowerCase();
+		switch (tag) {
+			case 'a': case 'area': return el.hasAttribute('href') ? 'link' : '';
+			case 'button': return 'button';
+			case 'select': return el.multiple || el.size > 1 ? 'listbox' : 'combobox';
+			case 'textarea': return 'textbox';
+			case 'img': return el.getAttribute('alt') === '' ? 'presentation' : 'img';
+			case 'h1': case 'h2': case 'h3': case 'h4': case 'h5': case 'h6': return 'heading';
+			case 'li': return 'listitem';
+			case 'ul': case 'ol': return 'list';
+			case 'tr': return 'row';
+			case 'table': return 'table';
+			case 'nav': return 'navigation';
+			case 'form': return 'form';
+			case 'dialog': return 'dialog';
+			case 'option': return 'option';
+			case 'input':
+				switch (type) {
+					case 'button': case 'submit': case 'reset': case 'image': return 'button';
+					case 'checkbox': return 'checkbox';
+					case 'radio': return 'radio';
+					case 'range': return 'slider';
+					case 'number': return 'spinbutton';
+					case 'search': return 'searchbox';
+					case 'hidden': return '';
+					default: return 'textbox';
+				}
+		}
+		return '';
+	}
+
+	function accessibleName(el) {
+		const labelledBy = el.getAttribute('aria-labelledby');
+		if (labelledBy) {
+			const text = labelledBy.split(/\s+/)
+				.map(id => document.getElementById(id))
+				.filter(Boolean)
+				.map(node => node.textContent.trim())
+				.join(' ');
+			if (text) return text.substring(0, 150);
+		}
+		const ariaLabel = el.getAttribute('aria-label');
+		if (ariaLabel && ariaLabel.trim()) return ariaLabel.trim().substring(0, 150);
+		if (el.id) {
+			const label = document.querySelector('label[for="' + CSS.escape(el.id) + '"]');
+			if (label && label.textContent.trim()) return label.textContent.trim().substring(0, 150);
+		}
+		const wrappingLabel = el.closest && el.closest('label');
+		if (wrappingLabel && wrappingLabel !== el && wrappingLabel.textContent.trim()) {
+			return wrappingLabel.textContent.trim().substring(0, 150);
+		}
+		const tag = el.tagName.toLowerCase();
+		if (tag === 'input' && ['button', 'submit', 'reset'].includes((el.type || '').toLowerCase()) && el.value) {
+			return el.value.substring(0, 150);
+		}
+		if (el.getAttribute('alt')) return el.getAttribute('alt').substring(0, 150);
+		if (tag !== 'input' && tag !== 'textarea' && tag !== 'select' && el.textContent && el.textContent.trim()) {
+			return el.textContent.trim().replace(/\s+/g, ' ').substring(0, 150);
+		}
+		if (el.getAttribute('title')) return el.getAttribute('title').substring(0, 150);
+		if (el.getAttribute('placeholder')) return el.getAttribute('placeholder').substring(0, 150);
+		return '';
+	}
+
+	function isDisabled(el) {
+		if (el.disabled || el.getAttribute('aria-disabled') === 'true') return true;
+		return !!(el.closest && el.closest('fieldset[disabled]'));
+	}
+`
+
+// FindElementsByRole - finds elements by ARIA role (explicit or implicit) and accessible name.
+// Name matching is case-insensitive substring, empty name matches any element with the role
+func (s *SeleniumController) FindElementsByRole(ctx context.Context, role string, name string) ([]entities.PageElement, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	role = strings.ToLower(strings.TrimSpace(role))
+	if role == "" {
+		return nil, fmt.Errorf("role is required")
+	}
+
+	script := accessibilityHelpersScript + selectorHelpersScript + `
+	const role = arguments[0];
+	const name = arguments[1].trim().toLowerCase();
+	const found = [];
+	document.querySelectorAll('*').forEach(el => {
+		if (found.length >= 50 || ariaRole(el) !== role) return;
+		const style = window.getComputedStyle(el);
+		if (style.visibility === 'hidden' || style.display === 'none' || el.getAttribute('aria-hidden') === 'true') return;
+		const accName = accessibleName(el);
+		if (name && !accName.toLowerCase().includes(name)) return;
+		const rect = el.getBoundingClientRect();
+		found.push({
+			tag_name: el.tagName.toLowerCase(),
+			text: el.textContent ? el.textContent.trim().substring(0, 200) : '',
+			placeholder: el.placeholder || '',
+			value: el.value || '',
+			attributes: {},
+			selector: cssPath(el),
+			role: role,
+			accessible_name: accName,
+			is_visible: rect.width > 0 && rect.height > 0,
+			is_clickable: !el.disabled,
+			is_disabled: isDisabled(el)
+		});
+	});
+	return JSON.stringify(found);
+	`
+
+	raw, err := s.wd.ExecuteScript(script, []interface{}{role, name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find elements by role: %w", err)
+	}
+	data, ok := raw.(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected find by role result: %v", raw)
+	}
+
+	var result []entities.PageElement
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse elements: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,34 @@
+package browser
+
+import (
+	"strings"
+
+	"ai_automation/domain/entities"
+)
+
+// keepAttributes - drops element attributes not named in patterns (EXTRACT_ATTRIBUTES), so inline
+// styles and framework internals do not reach the prompt. A pattern ending in * keeps a prefix
+func keepAttributes(elements []entities.PageElement, patterns []string) {
+	for i := range elements {
+		for name := range elements[i].Attributes {
+			if !attributeWanted(name, patterns) {
+				delete(elements[i].Attributes, name)
+			}
+		}
+	}
+}
+
+// attributeWanted - reports whether the attribute matches one of patterns
+func attributeWanted(name string, patterns []string) bool {
+	name = strings.ToLower(name)
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if name == pattern {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package browser
+
+import (
+	"testing"
+
+	"ai_automation/domain/entities"
+)
+
+func TestKeepAttributesKeepsRequestedOnes(t *testing.T) {
+	// Attributes as the extraction script collects them, before EXTRACT_ATTRIBUTES is applied
+	elements := []entities.PageElement{{
+		TagName:  "a",
+		Selector: "#buy",
+		Attributes: map[string]string{
+			"id":            "buy",
+			"href":          "/cart/add?sku=42",
+			"style":         "color: red",
+			"data-price":    "19.99",
+			"data-reactid":  ".0.1",
+			"ng-click":      "add()",
+			"ARIA-LABEL":    "Add to cart",
+			"data-currency": "EUR",
+		},
+	}}
+	keepAttributes(elements, []string{"href", "aria-label", "data-price", "data-curr*"})
+
+	want := map[string]string{"href": "/cart/add?sku=42", "data-price": "19.99", "ARIA-LABEL": "Add to cart", "data-currency": "EUR"}
+	got := elements[0].Attributes
+	if len(got) != len(want) {
+		t.Errorf("attributes = %v, want %v", got, want)
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("attribute %s = %q, want %q", name, got[name], value)
+		}
+	}
+}
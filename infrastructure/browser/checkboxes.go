@@ -0,0 +1,165 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"ai_automation/domain/interfaces"
+
+	"github.com/tebeka/selenium"
+)
+
+// resolveCheckControl - JavaScript resolving arguments[0] to the checkbox, radio button or switch it
+// stands for: the element itself, the control of a label, or the one control nested in a wrapper
+const resolveCheckControl = `
+	const isControl = el => {
+		const tag = el.tagName.toLowerCase();
+		const type = (el.getAttribute('type') || '').toLowerCase();
+		const role = (el.getAttribute('role') || '').toLowerCase();
+		return (tag === 'input' && (type === 'checkbox' || type === 'radio')) ||
+			['checkbox', 'radio', 'switch', 'menuitemcheckbox', 'menuitemradio'].includes(role);
+	};
+	let control = arguments[0];
+	if (control.tagName.toLowerCase() === 'label' && control.control) {
+		control = control.control;
+	}
+	if (!isControl(control)) {
+		control = control.querySelector('input[type=checkbox], input[type=radio], [role=checkbox], [role=radio], [role=switch]');
+	}
+`
+
+// checkStateScript - state of the resolved control as JSON, found is false when there is none
+const checkStateScript = resolveCheckControl + `
+	if (!control) return JSON.stringify({found: false});
+	const native = control.tagName.toLowerCase() === 'input';
+	const kind = native ? control.type.toLowerCase() : control.getAttribute('role').toLowerCase().replace('menuitem', '');
+	return JSON.stringify({
+		found: true,
+		kind: kind,
+		checked: native ? control.checked : control.getAttribute('aria-checked') === 'true',
+		disabled: native ? control.disabled : control.getAttribute('aria-disabled') === 'true'
+	});
+`
+
+// clickCheckControlScript - clicks the resolved control directly, for styled controls whose input
+// is hidden under another element. click() fires the same click, input and change events
+const clickCheckControlScript = resolveCheckControl + `
+	if (control) control.click();
+	return !!control;
+`
+
+const (
+	// checkSettleTimeout - how long a clicked control may take to show its new state, pages that
+	// re-render it asynchronously are not clicked a second time meanwhile
+	checkSettleTimeout = time.Second
+	checkPollInterval  = 100 * time.Millisecond
+)
+
+// checkState - what a checkbox, radio button or switch currently shows
+type checkState struct {
+	Found bool `json:"found"`
+	// Kind is checkbox, radio or switch
+	Kind     string `json:"kind"`
+	Checked  bool   `json:"checked"`
+	Disabled bool   `json:"disabled"`
+}
+
+// toggleNeeded - reports whether the control has to be clicked to become checked (or unchecked).
+// A control already in that state is left alone, so repeating the action never toggles it back
+func toggleNeeded(state checkState, checked bool, selector string) (bool, error) {
+	if !state.Found {
+		return false, fmt.Errorf("element %s is not a checkbox or radio button", selector)
+	}
+	if state.Checked == checked {
+		return false, nil
+	}
+	if state.Kind == "radio" && !checked {
+		return false, fmt.Errorf("radio button %s can't be unchecked, select another option of its group instead", selector)
+	}
+	if state.Disabled {
+		return false, newBrowserError(interfaces.ErrElementDisabled, nil, "element %s is disabled", selector)
+	}
+	return true, nil
+}
+
+// SetChecked - checks or unchecks a checkbox, radio button or switch found by selector or label text.
+// Nothing is clicked when it is already in the wanted state; otherwise it is clicked like a user
+// would, then directly when the click did not change it within checkSettleTimeout, and the new
+// state is verified
+func (s *SeleniumController) SetChecked(ctx context.Context, selector string, checked bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	element, err := s.findField(ctx, selector)
+	if err != nil {
+		return err
+	}
+	state, err := s.checkState(element)
+	if err != nil {
+		return err
+	}
+	toggle, err := toggleNeeded(state, checked, selector)
+	if err != nil || !toggle {
+		if err == nil {
+			s.logger.Infof("%s is already in the requested state (checked: %v)", selector, checked)
+		}
+		return err
+	}
+
+	s.logger.Infof("Setting %s %s checked: %v", state.Kind, selector, checked)
+	if err := s.clickElement(ctx, element, selector); err != nil {
+		s.logger.Debugf("Click on %s failed, clicking the control directly: %v", selector, err)
+	}
+
+	if element, state, err = s.waitCheckState(ctx, selector, checked); err != nil || state.Checked == checked {
+		return err
+	}
+
+	// The click hit a covering element or was not delivered
+	if _, err := s.wd.ExecuteScript(clickCheckControlScript, []interface{}{element}); err != nil {
+		return driverError(interfaces.ErrCommand, err, "failed to click %s: %v", selector, err)
+	}
+	if _, state, err = s.waitCheckState(ctx, selector, checked); err != nil {
+		return err
+	}
+	if state.Checked != checked {
+		return newBrowserError(interfaces.ErrCommand, nil, "%s %s did not change to checked: %v", state.Kind, selector, checked)
+	}
+	return nil
+}
+
+// waitCheckState - reads the control until it shows checked or checkSettleTimeout elapses. The page
+// may re-render the control on click, so it is looked up again on every read
+func (s *SeleniumController) waitCheckState(ctx context.Context, selector string, checked bool) (selenium.WebElement, checkState, error) {
+	deadline := time.Now().Add(checkSettleTimeout)
+	for {
+		element, err := s.findField(ctx, selector)
+		if err != nil {
+			return nil, checkState{}, err
+		}
+		state, err := s.checkState(element)
+		if err != nil || state.Checked == checked || !time.Now().Before(deadline) {
+			return element, state, err
+		}
+		if err := sleepWithContext(ctx, checkPollInterval); err != nil {
+			return nil, state, err
+		}
+	}
+}
+
+// checkState - reads the state of the control element stands for
+func (s *SeleniumController) checkState(element selenium.WebElement) (checkState, error) {
+	var state checkState
+	raw, err := s.wd.ExecuteScript(checkStateScript, []interface{}{element})
+	if err != nil {
+		return state, driverError(interfaces.ErrCommand, err, "failed to read checkbox state: %v", err)
+	}
+	encoded, _ := raw.(string)
+	if err := json.Unmarshal([]byte(encoded), &state); err != nil {
+		return state, newBrowserError(interfaces.ErrCommand, err, "failed to decode checkbox state: %v", err)
+	}
+	return state, nil
+}
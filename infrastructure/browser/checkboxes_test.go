@@ -0,0 +1,120 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"ai_automation/domain/interfaces"
+
+	"github.com/tebeka/selenium"
+)
+
+func TestToggleNeeded(t *testing.T) {
+	checkedBox := checkState{Found: true, Kind: "checkbox", Checked: true}
+	tests := []struct {
+		name       string
+		state      checkState
+		checked    bool
+		wantToggle bool
+		wantErr    bool
+	}{
+		{"checking a checked box is a no-op", checkedBox, true, false, false},
+		{"unchecking a checked box", checkedBox, false, true, false},
+		{"checking an unchecked switch", checkState{Found: true, Kind: "switch"}, true, true, false},
+		{"unchecking an unchecked box is a no-op", checkState{Found: true, Kind: "checkbox"}, false, false, false},
+		{"selecting a selected radio is a no-op", checkState{Found: true, Kind: "radio", Checked: true}, true, false, false},
+		{"radio buttons can't be unchecked", checkState{Found: true, Kind: "radio", Checked: true}, false, false, true},
+		{"disabled box", checkState{Found: true, Kind: "checkbox", Disabled: true}, true, false, true},
+		{"not a checkbox", checkState{}, true, false, true},
+	}
+	for _, tt := range tests {
+		toggle, err := toggleNeeded(tt.state, tt.checked, "#option")
+		if toggle != tt.wantToggle || (err != nil) != tt.wantErr {
+			t.Errorf("%s: toggleNeeded = %v, %v, want toggle %v, error %v", tt.name, toggle, err, tt.wantToggle, tt.wantErr)
+		}
+	}
+}
+
+// oneTabDriver - scriptDriver with a single tab, enough for clickElement
+type oneTabDriver struct {
+	scriptDriver
+}
+
+func (d *oneTabDriver) WindowHandles() ([]string, error) {
+	return []string{"main"}, nil
+}
+
+// clickableElement - enabled element counting clicks
+type clickableElement struct {
+	selenium.WebElement
+	clicks int
+}
+
+func (e *clickableElement) IsEnabled() (bool, error) {
+	return true, nil
+}
+
+func (e *clickableElement) Click() error {
+	e.clicks++
+	return nil
+}
+
+func TestSetCheckedWaitsForAsyncRerender(t *testing.T) {
+	tests := []struct {
+		name string
+		// settleReads - state reads after the click before the page shows it checked, -1 never
+		settleReads  int
+		wantFallback bool
+	}{
+		{"state changes a few reads after the click", 3, false},
+		{"click is lost", -1, true},
+	}
+	for _, tt := range tests {
+		element := &clickableElement{}
+		checked, reads, fallbacks := false, 0, 0
+		driver := &oneTabDriver{scriptDriver{
+			find: func(by, value string) (selenium.WebElement, error) { return element, nil },
+			reply: func(script string, args []interface{}) (interface{}, error) {
+				switch script {
+				case checkStateScript:
+					if element.clicks > 0 && !checked {
+						reads++
+						checked = tt.settleReads >= 0 && reads > tt.settleReads
+					}
+					return fmt.Sprintf(`{"found": true, "kind": "checkbox", "checked": %v}`, checked), nil
+				case clickCheckControlScript:
+					fallbacks++
+					checked = !checked
+					return true, nil
+				}
+				return true, nil
+			},
+		}}
+
+		err := newFakeController(driver).SetChecked(context.Background(), "#terms", true)
+		if err != nil {
+			t.Errorf("%s: SetChecked: %v", tt.name, err)
+		}
+		if !checked {
+			t.Errorf("%s: box ended unchecked", tt.name)
+		}
+		if got := fallbacks > 0; got != tt.wantFallback || fallbacks > 1 {
+			t.Errorf("%s: direct clicks = %d, want fallback %v", tt.name, fallbacks, tt.wantFallback)
+		}
+	}
+}
+
+func TestSetCheckedOnDisabledBox(t *testing.T) {
+	driver := &scriptDriver{
+		find: func(by, value string) (selenium.WebElement, error) { return &clickableElement{}, nil },
+		reply: func(script string, args []interface{}) (interface{}, error) {
+			return `{"found": true, "kind": "checkbox", "checked": false, "disabled": true}`, nil
+		},
+	}
+	err := newFakeController(driver).SetChecked(context.Background(), "#terms", true)
+	if !errors.Is(err, interfaces.ErrElementDisabled) {
+		t.Errorf("err = %v, want ErrElementDisabled", err)
+	}
+}
@@ -0,0 +1,76 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+)
+
+// consentScript - clicks the accept button of a cookie consent banner. Known consent platforms
+// (OneTrust, Cookiebot, Didomi, Usercentrics, Quantcast, Google Funding Choices) are matched by
+// selector, other banners by accept wording on buttons inside a cookie/consent container.
+// Returns the clicked button label, empty string when no banner was found
+const consentScript = `
+	function visible(el) {
+		const rect = el.getBoundingClientRect();
+		const style = window.getComputedStyle(el);
+		return rect.width > 0 && rect.height > 0 && style.visibility !== 'hidden' && style.display !== 'none';
+	}
+	function label(el) {
+		return (el.textContent || el.value || '').trim().replace(/\s+/g, ' ');
+	}
+
+	const knownButtons = [
+		'#onetrust-accept-btn-handler',
+		'#CybotCookiebotDialogBodyLevelButtonLevelOptinAllowAll',
+		'#CybotCookiebotDialogBodyButtonAccept',
+		'#didomi-notice-agree-button',
+		'[data-testid="uc-accept-all-button"]',
+		'.qc-cmp2-summary-buttons button[mode="primary"]',
+		'.fc-cta-consent'
+	];
+	for (const selector of knownButtons) {
+		const button = document.querySelector(selector);
+		if (button && visible(button)) {
+			button.click();
+			return label(button) || selector;
+		}
+	}
+
+	const phrases = [
+		'accept', 'accept all', 'accept all cookies', 'accept cookies', 'allow all', 'allow all cookies',
+		'agree', 'i agree', 'agree and close', 'got it',
+		'принять', 'принять все', 'принять всё', 'принять cookie', 'согласен', 'я согласен', 'понятно', 'хорошо'
+	];
+	const containers = document.querySelectorAll(
+		'[id*="cookie" i], [class*="cookie" i], [id*="consent" i], [class*="consent" i], ' +
+		'[aria-label*="cookie" i], [aria-label*="consent" i], [role="dialog"]'
+	);
+	for (const box of containers) {
+		if (!visible(box)) continue;
+		if (box.getAttribute('role') === 'dialog' && !/cookie|consent|куки/i.test(box.textContent)) continue;
+		const buttons = box.querySelectorAll('button, [role="button"], a, input[type="button"], input[type="submit"]');
+		for (const button of buttons) {
+			const text = label(button);
+			if (phrases.includes(text.toLowerCase()) && visible(button)) {
+				button.click();
+				return text;
+			}
+		}
+	}
+	return '';
+`
+
+// DismissConsentBanner - clicks the accept button of a cookie consent banner on the current page,
+// returns its label or empty string when there is no banner
+func (s *SeleniumController) DismissConsentBanner(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	result, err := s.wd.ExecuteScript(consentScript, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to look for consent banner: %w", err)
+	}
+	clicked, _ := result.(string)
+	return clicked, nil
+}
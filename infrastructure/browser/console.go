@@ -0,0 +1,88 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"ai_automation/domain/entities"
+
+	"github.com/tebeka/selenium/log"
+)
+
+// maxConsoleEntries - console entries kept per page, the oldest are dropped first
+const maxConsoleEntries = 200
+
+// consoleLocationPattern - Chrome prefixes browser log messages with their source: "url line:column text"
+// (console-api for console.* calls)
+var consoleLocationPattern = regexp.MustCompile(`^(\S+ \d+:\d+) (.*)$`)
+
+// consoleBuffer - console entries of the current page, Navigate starts a new page
+type consoleBuffer struct {
+	entries []entities.LogEntry
+	// since drops entries logged by the previous page that arrive after navigation
+	since time.Time
+}
+
+// reset - forgets entries of the previous page
+func (b *consoleBuffer) reset(now time.Time) {
+	b.entries = nil
+	b.since = now
+}
+
+// add - appends browser log messages of the current page, keeping at most maxConsoleEntries
+func (b *consoleBuffer) add(messages []log.Message) {
+	for _, message := range messages {
+		if message.Timestamp.Before(b.since) {
+			continue
+		}
+		b.entries = append(b.entries, consoleEntry(message))
+	}
+	if extra := len(b.entries) - maxConsoleEntries; extra > 0 {
+		b.entries = append([]entities.LogEntry(nil), b.entries[extra:]...)
+	}
+}
+
+// consoleEntry - converts a WebDriver browser log message. Uncaught exceptions are SEVERE like console.error
+func consoleEntry(message log.Message) entities.LogEntry {
+	entry := entities.LogEntry{Message: message.Message, Time: message.Timestamp}
+	switch message.Level {
+	case log.Severe:
+		entry.Level = "error"
+	case log.Warning:
+		entry.Level = "warning"
+	case log.Debug:
+		entry.Level = "debug"
+	default:
+		entry.Level = "info"
+	}
+
+	if match := consoleLocationPattern.FindStringSubmatch(message.Message); match != nil {
+		entry.Message = match[2]
+		if !strings.HasPrefix(match[1], "console-api ") {
+			entry.Source = match[1]
+		}
+		// console.* arguments arrive JSON-quoted
+		if unquoted, err := strconv.Unquote(entry.Message); err == nil {
+			entry.Message = unquoted
+		}
+	}
+	return entry
+}
+
+// GetConsoleLogs - returns console messages and uncaught errors of the current page, oldest first
+func (s *SeleniumController) GetConsoleLogs(ctx context.Context) ([]entities.LogEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	messages, err := s.wd.Log(log.Browser)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read browser console: %w", err)
+	}
+	s.console.add(messages)
+	return append([]entities.LogEntry(nil), s.console.entries...), nil
+}
@@ -0,0 +1,56 @@
+package browser
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/tebeka/selenium/log"
+)
+
+func TestConsoleBufferCapturesPageLogs(t *testing.T) {
+	navigated := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	var buffer consoleBuffer
+	buffer.reset(navigated)
+
+	// What ChromeDriver returns for a page that logs and then throws
+	buffer.add([]log.Message{
+		{Timestamp: navigated.Add(-time.Second), Level: log.Info, Message: `console-api 1:1 "from the previous page"`},
+		{Timestamp: navigated.Add(time.Second), Level: log.Info, Message: `console-api 12:9 "cart loaded"`},
+		{Timestamp: navigated.Add(2 * time.Second), Level: log.Warning, Message: `https://shop.example.com/app.js 40:3 "deprecated API"`},
+		{Timestamp: navigated.Add(3 * time.Second), Level: log.Severe, Message: `https://shop.example.com/app.js 57:11 Uncaught TypeError: Cannot read properties of null (reading 'click')`},
+	})
+
+	want := []struct{ level, source, message string }{
+		{"info", "", "cart loaded"},
+		{"warning", "https://shop.example.com/app.js 40:3", "deprecated API"},
+		{"error", "https://shop.example.com/app.js 57:11", "Uncaught TypeError: Cannot read properties of null (reading 'click')"},
+	}
+	if len(buffer.entries) != len(want) {
+		t.Fatalf("captured %d entries, want %d: %+v", len(buffer.entries), len(want), buffer.entries)
+	}
+	for i, w := range want {
+		got := buffer.entries[i]
+		if got.Level != w.level || got.Source != w.source || got.Message != w.message {
+			t.Errorf("entry %d = %+v, want %+v", i, got, w)
+		}
+	}
+
+	buffer.reset(navigated.Add(time.Minute))
+	if len(buffer.entries) != 0 {
+		t.Errorf("entries kept after navigation: %+v", buffer.entries)
+	}
+}
+
+func TestConsoleBufferIsCapped(t *testing.T) {
+	var buffer consoleBuffer
+	messages := make([]log.Message, maxConsoleEntries+10)
+	for i := range messages {
+		messages[i] = log.Message{Timestamp: time.Now(), Level: log.Info, Message: fmt.Sprintf(`console-api 1:1 "tick %d"`, i)}
+	}
+	buffer.add(messages)
+
+	if len(buffer.entries) != maxConsoleEntries || buffer.entries[0].Message != "tick 10" {
+		t.Errorf("kept %d entries starting with %q, want the last %d", len(buffer.entries), buffer.entries[0].Message, maxConsoleEntries)
+	}
+}
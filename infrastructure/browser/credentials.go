@@ -0,0 +1,76 @@
+package browser
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// httpCredentials - parsed BROWSER_HTTP_CREDENTIALS setting for HTTP Basic/Digest auth
+type httpCredentials struct {
+	Username string
+	Password string
+	// Hosts limits which servers receive the credentials, empty means any host
+	Hosts []string
+}
+
+// parseHTTPCredentials - parses "user:pass" and optional comma separated host list.
+// Returns nil when credentials are not configured
+func parseHTTPCredentials(raw string, hosts string) (*httpCredentials, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+
+	username, password, ok := strings.Cut(raw, ":")
+	if !ok || username == "" {
+		return nil, fmt.Errorf("invalid BROWSER_HTTP_CREDENTIALS value: expected user:pass")
+	}
+
+	creds := &httpCredentials{Username: username, Password: password, Hosts: []string{}}
+	for _, host := range strings.Split(hosts, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			creds.Hosts = append(creds.Hosts, host)
+		}
+	}
+	return creds, nil
+}
+
+// secretEnvPrefix - secrets are read from environment variables AGENT_SECRET_<NAME>
+const secretEnvPrefix = "AGENT_SECRET_"
+
+// secretPlaceholder - matches {{secret:NAME}} in typed text
+var secretPlaceholder = regexp.MustCompile(`\{\{\s*secret:([A-Za-z0-9_]+)\s*\}\}`)
+
+// loadSecrets - collects AGENT_SECRET_* variables, keyed by upper-case name
+func loadSecrets() map[string]string {
+	secrets := map[string]string{}
+	for _, entry := range os.Environ() {
+		key, value, _ := strings.Cut(entry, "=")
+		if name, ok := strings.CutPrefix(key, secretEnvPrefix); ok && name != "" {
+			secrets[strings.ToUpper(name)] = value
+		}
+	}
+	return secrets
+}
+
+// resolveSecrets - replaces {{secret:NAME}} placeholders with secret values, so the AI
+// only ever sees placeholder names. Unknown names are an error rather than typed literally
+func resolveSecrets(text string, secrets map[string]string) (string, error) {
+	var missing []string
+	resolved := secretPlaceholder.ReplaceAllStringFunc(text, func(match string) string {
+		name := strings.ToUpper(secretPlaceholder.FindStringSubmatch(match)[1])
+		value, ok := secrets[name]
+		if !ok {
+			missing = append(missing, name)
+			return match
+		}
+		return value
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("unknown secret(s): %s, set %s<NAME> environment variable", strings.Join(missing, ", "), secretEnvPrefix)
+	}
+	return resolved, nil
+}
@@ -0,0 +1,118 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dateLayouts - accepted set_date values: a day, a day with time, or a month
+var dateLayouts = []string{"2006-01-02", "2006-01-02T15:04", "2006-01-02 15:04", "2006-01"}
+
+// parseDate - reads a normalized date (YYYY-MM-DD, optionally with THH:MM, or YYYY-MM)
+func parseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range dateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q, use YYYY-MM-DD", value)
+}
+
+// nativeDateValue - value a native date input of inputType takes for date in the format the browser
+// requires, false for inputs that are not native date pickers
+func nativeDateValue(inputType string, date time.Time) (string, bool) {
+	switch inputType {
+	case "date":
+		return date.Format("2006-01-02"), true
+	case "datetime-local":
+		return date.Format("2006-01-02T15:04"), true
+	case "month":
+		return date.Format("2006-01"), true
+	case "week":
+		year, week := date.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week), true
+	}
+	return "", false
+}
+
+// dateReadBackScript - value of a date input after it was set and whether it breaks min, max or step.
+// Browsers keep an out-of-range date in the field and only flag it in validity
+const dateReadBackScript = `
+	var el = arguments[0];
+	return JSON.stringify({
+		value: el.value,
+		min: el.min || '',
+		max: el.max || '',
+		out_of_range: el.validity.rangeUnderflow || el.validity.rangeOverflow || el.validity.stepMismatch
+	});
+`
+
+// dateReadBack - what dateReadBackScript reports
+type dateReadBack struct {
+	Value      string `json:"value"`
+	Min        string `json:"min"`
+	Max        string `json:"max"`
+	OutOfRange bool   `json:"out_of_range"`
+}
+
+// SetDate - sets a date field. Native date inputs get the value in their ISO format with input and
+// change events, as typing into them depends on the browser locale; calendar widgets get the date typed
+func (s *SeleniumController) SetDate(ctx context.Context, selector string, value string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	date, err := parseDate(value)
+	if err != nil {
+		return err
+	}
+
+	element, err := s.findField(ctx, selector)
+	if err != nil {
+		return err
+	}
+
+	raw, err := s.wd.ExecuteScript(`
+		var el = arguments[0];
+		return el.tagName.toLowerCase() === 'input' ? (el.getAttribute('type') || 'text').toLowerCase() : '';
+	`, []interface{}{element})
+	if err != nil {
+		return fmt.Errorf("failed to inspect date field %s: %w", selector, err)
+	}
+	inputType, _ := raw.(string)
+
+	native, ok := nativeDateValue(inputType, date)
+	if !ok {
+		s.logger.Debugf("Field %s is not a native date input, typing the date", selector)
+		return s.TypeText(ctx, selector, strings.TrimSpace(value))
+	}
+
+	s.logger.Infof("Setting date %s into: %s", native, selector)
+	if err := s.setTextWithScript(element, native, true); err != nil {
+		return err
+	}
+
+	// A malformed value reads back empty, one outside min/max or step stays but is invalid
+	raw, err = s.wd.ExecuteScript(dateReadBackScript, []interface{}{element})
+	if err != nil {
+		s.logger.Debugf("Failed to read back date field %s: %v", selector, err)
+		return nil
+	}
+	var readBack dateReadBack
+	encoded, _ := raw.(string)
+	if err := json.Unmarshal([]byte(encoded), &readBack); err != nil {
+		s.logger.Debugf("Failed to decode date field %s: %v", selector, err)
+		return nil
+	}
+	if readBack.OutOfRange {
+		return fmt.Errorf("date field %s does not allow %s (min %q, max %q)", selector, native, readBack.Min, readBack.Max)
+	}
+	if readBack.Value != native {
+		return fmt.Errorf("date field %s did not accept %s", selector, native)
+	}
+	return nil
+}
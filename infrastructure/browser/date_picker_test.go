@@ -0,0 +1,118 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tebeka/selenium"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2024-03-15", time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{" 2024-03-15T09:30 ", time.Date(2024, 3, 15, 9, 30, 0, 0, time.UTC)},
+		{"2024-03-15 09:30", time.Date(2024, 3, 15, 9, 30, 0, 0, time.UTC)},
+		{"2024-03", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseDate(tt.value)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseDate(%q) = %v, %v, want %v", tt.value, got, err, tt.want)
+		}
+	}
+
+	for _, value := range []string{"15.03.2024", "03/15/2024", "2024-02-30", "tomorrow", ""} {
+		if _, err := parseDate(value); err == nil {
+			t.Errorf("parseDate(%q) accepted a non-normalized date", value)
+		}
+	}
+}
+
+func TestNativeDateValue(t *testing.T) {
+	date := time.Date(2024, 12, 30, 18, 5, 0, 0, time.UTC)
+	tests := []struct {
+		inputType string
+		want      string
+		native    bool
+	}{
+		{"date", "2024-12-30", true},
+		{"datetime-local", "2024-12-30T18:05", true},
+		{"month", "2024-12", true},
+		// 30 December 2024 falls in the first ISO week of 2025
+		{"week", "2025-W01", true},
+		{"text", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, native := nativeDateValue(tt.inputType, date)
+		if got != tt.want || native != tt.native {
+			t.Errorf("nativeDateValue(%q) = %q, %v, want %q, %v", tt.inputType, got, native, tt.want, tt.native)
+		}
+	}
+}
+
+// dateField - native <input type=date> as the scripts of SetDate see it
+type dateField struct {
+	min, max string
+	value    string
+	// events are the events dispatched on the field in order
+	events []string
+}
+
+// dateDriver - runs the SetDate scripts against field
+func dateDriver(field *dateField) *scriptDriver {
+	element := &fakeElement{}
+	return &scriptDriver{
+		find: func(by, value string) (selenium.WebElement, error) { return element, nil },
+		reply: func(script string, args []interface{}) (interface{}, error) {
+			switch {
+			case strings.Contains(script, "getAttribute('type')"):
+				return "date", nil
+			case strings.Contains(script, "getOwnPropertyDescriptor"):
+				field.value = args[1].(string)
+				if strings.Contains(script, "dispatchEvent(new InputEvent('input'") {
+					field.events = append(field.events, "input")
+				}
+				if strings.Contains(script, "dispatchEvent(new Event('change'") {
+					field.events = append(field.events, "change")
+				}
+				return true, nil
+			case script == dateReadBackScript:
+				// ISO dates compare as strings
+				outOfRange := (field.min != "" && field.value < field.min) || (field.max != "" && field.value > field.max)
+				data, _ := json.Marshal(dateReadBack{Value: field.value, Min: field.min, Max: field.max, OutOfRange: outOfRange})
+				return string(data), nil
+			}
+			return nil, nil
+		},
+	}
+}
+
+func TestSetDateFillsNativeInput(t *testing.T) {
+	field := &dateField{min: "2024-01-01", max: "2024-12-31"}
+	if err := newFakeController(dateDriver(field)).SetDate(context.Background(), "#arrival", "2024-03-15T09:30"); err != nil {
+		t.Fatalf("SetDate: %v", err)
+	}
+	if field.value != "2024-03-15" {
+		t.Errorf("value = %q, want the date in the input's format", field.value)
+	}
+	if strings.Join(field.events, ",") != "input,change" {
+		t.Errorf("events = %v, want input then change", field.events)
+	}
+}
+
+func TestSetDateOutsideMinMax(t *testing.T) {
+	for _, value := range []string{"2023-12-31", "2025-01-01"} {
+		field := &dateField{min: "2024-01-01", max: "2024-12-31"}
+		err := newFakeController(dateDriver(field)).SetDate(context.Background(), "#arrival", value)
+		if err == nil || !strings.Contains(err.Error(), `min "2024-01-01", max "2024-12-31"`) {
+			t.Errorf("SetDate(%s) = %v, want an error naming min and max", value, err)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package browser
+
+import (
+	"context"
+	"strings"
+)
+
+// GetOpenDialog - returns the message of an open alert/confirm/prompt/beforeunload dialog.
+// The driver is started with unhandledPromptBehavior=ignore, so dialogs stay open until handled
+func (s *SeleniumController) GetOpenDialog(ctx context.Context) (string, bool) {
+	if ctx.Err() != nil {
+		return "", false
+	}
+
+	text, err := s.wd.AlertText()
+	if err != nil {
+		// "no such alert" is the normal case
+		return "", false
+	}
+	return strings.TrimSpace(text), true
+}
+
+// HandleDialog - accepts or dismisses the open dialog
+func (s *SeleniumController) HandleDialog(ctx context.Context, accept bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if accept {
+		return s.wd.AcceptAlert()
+	}
+	return s.wd.DismissAlert()
+}
@@ -0,0 +1,112 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"ai_automation/domain/interfaces"
+)
+
+// downloadTimeout - max time to wait for a download to finish when ctx has no deadline
+const downloadTimeout = 2 * time.Minute
+
+// getOrCreateDownloadDir - returns dir (DOWNLOAD_DIR) or ~/.ai_automation/downloads, creating it if needed
+func getOrCreateDownloadDir(dir string) (string, error) {
+	if dir == "" {
+		homeDir := os.Getenv("HOME")
+		if homeDir == "" {
+			return "", fmt.Errorf("HOME environment variable is not set")
+		}
+		dir = filepath.Join(homeDir, ".ai_automation", "downloads")
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("invalid DOWNLOAD_DIR: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create download directory: %w", err)
+	}
+	return dir, nil
+}
+
+// downloadPrefs - Chrome preferences that save downloads to dir without asking
+func downloadPrefs(dir string) map[string]interface{} {
+	return map[string]interface{}{
+		"download.default_directory":   dir,
+		"download.prompt_for_download": false,
+		"download.directory_upgrade":   true,
+		"safebrowsing.enabled":         true,
+	}
+}
+
+// DownloadFile - clicks download trigger and waits until a new file is fully saved, returns its path
+func (s *SeleniumController) DownloadFile(ctx context.Context, selector string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	before, err := listFiles(s.downloadDir)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.Click(ctx, selector); err != nil {
+		return "", err
+	}
+
+	deadline := time.Now().Add(downloadTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	started := time.Now()
+	for {
+		files, err := listFiles(s.downloadDir)
+		if err != nil {
+			return "", err
+		}
+
+		inProgress := false
+		for name := range files {
+			if strings.HasSuffix(name, ".crdownload") || strings.HasSuffix(name, ".tmp") {
+				inProgress = true
+				continue
+			}
+			if !before[name] {
+				path := filepath.Join(s.downloadDir, name)
+				s.logger.Infof("Downloaded file: %s", path)
+				return path, nil
+			}
+		}
+
+		if time.Now().After(deadline) {
+			if inProgress {
+				return "", newBrowserError(interfaces.ErrTimeout, nil, "download did not finish within %s", time.Since(started).Round(time.Second))
+			}
+			return "", newBrowserError(interfaces.ErrTimeout, nil, "no download started after clicking %s", selector)
+		}
+		if err := sleepWithContext(ctx, 500*time.Millisecond); err != nil {
+			return "", err
+		}
+	}
+}
+
+// listFiles - returns names of regular files in dir
+func listFiles(dir string) (map[string]bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read download directory: %w", err)
+	}
+
+	files := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			files[entry.Name()] = true
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,177 @@
+package browser
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// driverProbeTimeout - how long to wait for an answer from a process already on the port
+const driverProbeTimeout = 2 * time.Second
+
+// portChecker - port probes, replaceable so port selection does not depend on real sockets
+type portChecker struct {
+	isFree       func(port int) bool
+	isDriver     func(port int) bool
+	isStale      func(port int) bool
+	shutdown     func(port int) error
+	freePort     func() (int, error)
+	waitInterval time.Duration
+}
+
+// systemPortChecker - probes real local ports
+var systemPortChecker = portChecker{
+	isFree:       isPortFree,
+	isDriver:     isChromeDriver,
+	isStale:      isStaleDriver,
+	shutdown:     shutdownChromeDriver,
+	freePort:     freePort,
+	waitInterval: 200 * time.Millisecond,
+}
+
+// selectDriverPort - returns a port ChromeDriver can bind to. A ChromeDriver this agent
+// started in a run that has since died is shut down and its port reused; a port held by
+// anything else, including a ChromeDriver of another running agent, is skipped in favour
+// of a free port chosen by the OS
+func selectDriverPort(preferred int, checker portChecker, logger *logrus.Logger) (int, error) {
+	if checker.isFree(preferred) {
+		return preferred, nil
+	}
+
+	if checker.isDriver(preferred) && checker.isStale(preferred) {
+		logger.Warnf("Stale ChromeDriver from a previous run found on port %d, shutting it down", preferred)
+		if err := checker.shutdown(preferred); err != nil {
+			logger.WithError(err).Warn("Failed to shut down stale ChromeDriver")
+		} else {
+			deadline := time.Now().Add(driverProbeTimeout)
+			for time.Now().Before(deadline) {
+				if checker.isFree(preferred) {
+					return preferred, nil
+				}
+				time.Sleep(checker.waitInterval)
+			}
+		}
+	}
+
+	port, err := checker.freePort()
+	if err != nil {
+		return 0, fmt.Errorf("port %d is busy and no free port is available: %w", preferred, err)
+	}
+	logger.Warnf("Port %d is busy, starting ChromeDriver on port %d", preferred, port)
+	return port, nil
+}
+
+// isPortFree - checks whether a local TCP port can be bound
+func isPortFree(port int) bool {
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return false
+	}
+	listener.Close()
+	return true
+}
+
+// freePort - asks the OS for an unused local TCP port
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// isChromeDriver - checks whether the process on the port answers /status as ChromeDriver
+func isChromeDriver(port int) bool {
+	client := http.Client{Timeout: driverProbeTimeout}
+	resp, err := client.Get(fmt.Sprintf("http://localhost:%d/status", port))
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	var status struct {
+		Value struct {
+			Message string `json:"message"`
+		} `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return false
+	}
+	return strings.Contains(status.Value.Message, "ChromeDriver")
+}
+
+// shutdownChromeDriver - asks ChromeDriver to quit along with the browsers it started
+func shutdownChromeDriver(port int) error {
+	client := http.Client{Timeout: driverProbeTimeout}
+	resp, err := client.Get(fmt.Sprintf("http://localhost:%d/shutdown", port))
+	if err != nil {
+		// ChromeDriver may drop the connection while exiting
+		if isPortFree(port) {
+			return nil
+		}
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// driverOwnerFile - file recording which agent process started ChromeDriver on the port
+func driverOwnerFile(port int) string {
+	homeDir := os.Getenv("HOME")
+	if homeDir == "" {
+		return ""
+	}
+	return filepath.Join(homeDir, ".ai_automation", fmt.Sprintf("chromedriver-%d.pid", port))
+}
+
+// recordDriverOwner - marks ChromeDriver on the port as started by this process
+func recordDriverOwner(port int) error {
+	path := driverOwnerFile(port)
+	if path == "" {
+		return fmt.Errorf("HOME environment variable is not set")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())), 0644)
+}
+
+// releaseDriverOwner - removes the owner record written by recordDriverOwner
+func releaseDriverOwner(port int) {
+	if path := driverOwnerFile(port); path != "" {
+		os.Remove(path)
+	}
+}
+
+// isStaleDriver - reports whether ChromeDriver on the port was started by an agent
+// process that is no longer running. Without an owner record the driver is not ours
+func isStaleDriver(port int) bool {
+	path := driverOwnerFile(port)
+	if path == "" {
+		return false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 || pid == os.Getpid() {
+		return false
+	}
+
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return true
+	}
+	return process.Signal(syscall.Signal(0)) != nil
+}
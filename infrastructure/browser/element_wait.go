@@ -0,0 +1,40 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"ai_automation/domain/interfaces"
+
+	"github.com/tebeka/selenium"
+)
+
+// findPollInterval - pause between lookups of an element that is not on the page yet
+const findPollInterval = 200 * time.Millisecond
+
+// pollElement - calls find until it returns an element or timeout elapses, like Playwright's
+// WaitFor. Only a missing element is retried, other errors (a lost session) are returned at once;
+// a zero timeout looks up once
+func pollElement(ctx context.Context, timeout time.Duration, find func() (selenium.WebElement, error)) (selenium.WebElement, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		element, err := find()
+		if err == nil || !errors.Is(err, interfaces.ErrElementNotFound) || !time.Now().Before(deadline) {
+			return element, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(findPollInterval):
+		}
+	}
+}
+
+// findElement - findElementOnce repeated for up to FIND_ELEMENT_TIMEOUT_MS, for elements that
+// render a moment after the page or the previous action
+func (s *SeleniumController) findElement(ctx context.Context, selector string) (selenium.WebElement, error) {
+	return pollElement(ctx, s.findTimeout, func() (selenium.WebElement, error) {
+		return s.findElementOnce(selector)
+	})
+}
@@ -0,0 +1,91 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"ai_automation/domain/interfaces"
+
+	"github.com/sirupsen/logrus"
+	"github.com/tebeka/selenium"
+)
+
+// lateDriver - WebDriver whose page renders #late after a number of lookups, other commands are not implemented
+type lateDriver struct {
+	selenium.WebDriver
+	element     selenium.WebElement
+	renderAfter int
+	lookups     int
+	err         error
+}
+
+func (d *lateDriver) FindElement(by, value string) (selenium.WebElement, error) {
+	d.lookups++
+	if d.err != nil {
+		return nil, d.err
+	}
+	if value != "#late" || d.lookups <= d.renderAfter {
+		return nil, errors.New("no such element: Unable to locate element")
+	}
+	return d.element, nil
+}
+
+func newWaitTestController(wd selenium.WebDriver, timeout time.Duration) *SeleniumController {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return &SeleniumController{wd: wd, logger: logger, findTimeout: timeout}
+}
+
+func TestFindElementWaitsForLateElement(t *testing.T) {
+	// Each attempt tries every selector strategy, the element renders during the third attempt
+	driver := &lateDriver{element: struct{ selenium.WebElement }{}, renderAfter: 10}
+	s := newWaitTestController(driver, 3*time.Second)
+
+	element, err := s.findElement(context.Background(), "#late")
+	if err != nil || element == nil {
+		t.Fatalf("findElement = %v, %v, want the element once it rendered", element, err)
+	}
+	if driver.lookups <= driver.renderAfter {
+		t.Errorf("lookups = %d, want more than %d", driver.lookups, driver.renderAfter)
+	}
+}
+
+func TestFindElementGivesUp(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   *lateDriver
+		timeout  time.Duration
+		wantKind error
+		minWait  time.Duration
+		maxWait  time.Duration
+	}{
+		{"missing element after the timeout", &lateDriver{}, 500 * time.Millisecond, interfaces.ErrElementNotFound, 500 * time.Millisecond, 2 * time.Second},
+		{"zero timeout looks once", &lateDriver{}, 0, interfaces.ErrElementNotFound, 0, findPollInterval},
+		{"lost session is not retried", &lateDriver{err: errors.New("invalid session id")}, 3 * time.Second, interfaces.ErrBrowserClosed, 0, findPollInterval},
+	}
+	for _, tt := range tests {
+		s := newWaitTestController(tt.driver, tt.timeout)
+		start := time.Now()
+		_, err := s.findElement(context.Background(), "#missing")
+		elapsed := time.Since(start)
+		if !errors.Is(err, tt.wantKind) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.wantKind)
+		}
+		if elapsed < tt.minWait || elapsed > tt.maxWait {
+			t.Errorf("%s: gave up after %s, want between %s and %s", tt.name, elapsed, tt.minWait, tt.maxWait)
+		}
+	}
+}
+
+func TestFindElementStopsWithContext(t *testing.T) {
+	s := newWaitTestController(&lateDriver{}, time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	if _, err := s.findElement(ctx, "#missing"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want the context deadline", err)
+	}
+}
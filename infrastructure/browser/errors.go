@@ -0,0 +1,46 @@
+package browser
+
+import (
+	"fmt"
+	"strings"
+
+	"ai_automation/domain/interfaces"
+)
+
+// browserClosedMarkers - WebDriver and transport errors returned once Chrome or its session is gone
+var browserClosedMarkers = []string{
+	"invalid session id",
+	"no such window",
+	"target window already closed",
+	"session deleted",
+	"chrome not reachable",
+	"disconnected: not connected to devtools",
+	"connect: connection refused",
+}
+
+// isBrowserClosedError - detects errors meaning no further command can succeed
+func isBrowserClosedError(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := strings.ToLower(err.Error())
+	for _, marker := range browserClosedMarkers {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// newBrowserError - error of the given kind with a formatted message, wrapping cause when set
+func newBrowserError(kind error, cause error, format string, args ...interface{}) error {
+	return &interfaces.BrowserError{Kind: kind, Message: fmt.Sprintf(format, args...), Err: cause}
+}
+
+// driverError - classifies a failed WebDriver command: a lost session is ErrBrowserClosed, anything else is kind
+func driverError(kind error, err error, format string, args ...interface{}) error {
+	if isBrowserClosedError(err) {
+		return newBrowserError(interfaces.ErrBrowserClosed, err, "browser closed: %v", err)
+	}
+	return newBrowserError(kind, err, format, args...)
+}
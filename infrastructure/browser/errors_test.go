@@ -0,0 +1,135 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"ai_automation/domain/interfaces"
+
+	"github.com/tebeka/selenium"
+)
+
+func TestDriverErrorKinds(t *testing.T) {
+	tests := []struct {
+		name  string
+		kind  error
+		cause error
+		want  error
+	}{
+		{"missing element", interfaces.ErrElementNotFound, errors.New("no such element: Unable to locate element"), interfaces.ErrElementNotFound},
+		{"failed load", interfaces.ErrNavigation, errors.New("unknown error: net::ERR_NAME_NOT_RESOLVED"), interfaces.ErrNavigation},
+		{"slow page", interfaces.ErrTimeout, nil, interfaces.ErrTimeout},
+		{"closed window", interfaces.ErrElementNotFound, errors.New("no such window: target window already closed"), interfaces.ErrBrowserClosed},
+		{"lost session", interfaces.ErrNavigation, errors.New("invalid session id"), interfaces.ErrBrowserClosed},
+		{"script error", interfaces.ErrCommand, errors.New("javascript error: x is not defined"), interfaces.ErrCommand},
+		{"driver gone", interfaces.ErrTimeout, errors.New("dial tcp 127.0.0.1:9515: connect: connection refused"), interfaces.ErrBrowserClosed},
+	}
+	kinds := []error{interfaces.ErrElementNotFound, interfaces.ErrTimeout, interfaces.ErrNavigation, interfaces.ErrCommand, interfaces.ErrBrowserClosed}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := driverError(tt.kind, tt.cause, "operation failed")
+			for _, kind := range kinds {
+				if got := errors.Is(err, kind); got != (kind == tt.want) {
+					t.Errorf("errors.Is(%v, %v) = %v", err, kind, got)
+				}
+			}
+			if tt.cause != nil && !errors.Is(err, tt.cause) {
+				t.Errorf("%v does not wrap its cause", err)
+			}
+		})
+	}
+}
+
+// tabDriver - WebDriver failing every window command with err
+type tabDriver struct {
+	scriptDriver
+	err error
+}
+
+func (d *tabDriver) WindowHandles() ([]string, error) {
+	return nil, d.err
+}
+
+func (d *tabDriver) CurrentWindowHandle() (string, error) {
+	return "", d.err
+}
+
+// disabledElement - element reporting itself disabled
+type disabledElement struct {
+	selenium.WebElement
+}
+
+func (e *disabledElement) IsEnabled() (bool, error) {
+	return false, nil
+}
+
+func TestControllerErrorsAreTyped(t *testing.T) {
+	ctx := context.Background()
+	scriptErr := errors.New("javascript error: x is not defined")
+	failing := &tabDriver{
+		scriptDriver: scriptDriver{reply: func(string, []interface{}) (interface{}, error) { return nil, scriptErr }},
+		err:          errors.New("unknown error: cannot determine loading status"),
+	}
+	nothingThere := &scriptDriver{reply: func(string, []interface{}) (interface{}, error) { return false, nil }}
+	disabledList := &scriptDriver{
+		find: func(by, value string) (selenium.WebElement, error) { return &fakeElement{}, nil },
+		reply: func(string, []interface{}) (interface{}, error) {
+			return `{"found": true, "disabled": true, "options": [{"text": "Red", "value": "red"}]}`, nil
+		},
+	}
+	brokenList := &scriptDriver{find: disabledList.find, reply: failing.reply}
+	disabledButton := &scriptDriver{find: func(by, value string) (selenium.WebElement, error) { return &disabledElement{}, nil }}
+	lost := &tabDriver{
+		scriptDriver: scriptDriver{reply: func(string, []interface{}) (interface{}, error) { return nil, errors.New("invalid session id") }},
+		err:          errors.New("invalid session id"),
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{"script", func() error { _, err := newFakeController(failing).ExecuteScript(ctx, "return x"); return err }, interfaces.ErrCommand},
+		{"click at", func() error { return newFakeController(failing).ClickAt(ctx, 10, 20) }, interfaces.ErrCommand},
+		{"click at empty point", func() error { return newFakeController(nothingThere).ClickAt(ctx, 10, 20) }, interfaces.ErrElementNotFound},
+		{"list tabs", func() error { _, err := newFakeController(failing).SwitchToTab(ctx, "https://example.com"); return err }, interfaces.ErrCommand},
+		{"previous tab", func() error { return newFakeController(failing).SwitchToPreviousTab(ctx) }, interfaces.ErrCommand},
+		{"close tab", func() error { return newFakeController(failing).CloseCurrentTab(ctx) }, interfaces.ErrCommand},
+		{"disabled button", func() error { return newFakeController(disabledButton).Click(ctx, "#pay") }, interfaces.ErrElementDisabled},
+		{"disabled select", func() error { return newFakeController(disabledList).SelectOption(ctx, "#color", []string{"red"}) }, interfaces.ErrElementDisabled},
+		{"select script", func() error { return newFakeController(brokenList).SelectOption(ctx, "#color", []string{"red"}) }, interfaces.ErrCommand},
+		{"text selection", func() error { _, err := newFakeController(failing).GetSelectedText(ctx); return err }, interfaces.ErrCommand},
+		{"load all", func() error { _, err := newFakeController(failing).LoadAll(ctx, 3); return err }, interfaces.ErrCommand},
+		{"script after session loss", func() error { _, err := newFakeController(lost).ExecuteScript(ctx, "return 1"); return err }, interfaces.ErrBrowserClosed},
+		{"tabs after session loss", func() error { return newFakeController(lost).OpenNewTab(ctx, "https://example.com") }, interfaces.ErrBrowserClosed},
+	}
+	for _, tt := range tests {
+		err := tt.call()
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+		var browserErr *interfaces.BrowserError
+		if !errors.As(err, &browserErr) {
+			t.Errorf("%s: %v is not a BrowserError", tt.name, err)
+		}
+	}
+}
+
+func TestNavigationProbeErrorKind(t *testing.T) {
+	probes := []navigationProbe{
+		{Status: 503, URL: "https://example.com"},
+		{URL: "chrome-error://chromewebdata/", NetError: "ERR_CONNECTION_REFUSED"},
+		{URL: "chrome-error://chromewebdata/"},
+	}
+	for _, probe := range probes {
+		err := probe.err()
+		if !errors.Is(err, interfaces.ErrNavigation) || errors.Is(err, interfaces.ErrBrowserClosed) {
+			t.Errorf("probe %+v: err = %v, want only ErrNavigation", probe, err)
+		}
+		if !strings.HasPrefix(err.Error(), "navigation failed") {
+			t.Errorf("probe %+v: message %q changed", probe, err)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package browser
+
+import (
+	"encoding/json"
+	"unicode/utf8"
+
+	"github.com/tebeka/selenium"
+)
+
+// extractionLimits - max number of items of each kind returned per page of extraction
+type extractionLimits struct {
+	Elements int
+	Links    int
+	Buttons  int
+	// Text is the max length of visible text in characters
+	Text int
+}
+
+// capText - cuts text to limit characters (not bytes), a non-positive limit keeps it whole.
+// The extraction script already stops at the limit, this guards against its UTF-16 counting
+func capText(text string, limit int) string {
+	if limit <= 0 || utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	return string([]rune(text)[:limit])
+}
+
+// runPagedScript - runs extraction script that receives (page, limit, root) and returns {items, total},
+// decodes items into out and reports whether more items exist after this page. A nil root means the whole document
+func (s *SeleniumController) runPagedScript(script string, page int, limit int, root selenium.WebElement, out interface{}) (bool, error) {
+	rawResult, err := s.wd.ExecuteScript(script, []interface{}{page, limit, root})
+	if err != nil {
+		return false, err
+	}
+
+	jsonData, err := json.Marshal(rawResult)
+	if err != nil {
+		return false, err
+	}
+
+	var result struct {
+		Items json.RawMessage `json:"items"`
+		Total int             `json:"total"`
+	}
+	if err := json.Unmarshal(jsonData, &result); err != nil {
+		return false, err
+	}
+	if len(result.Items) > 0 {
+		if err := json.Unmarshal(result.Items, out); err != nil {
+			return false, err
+		}
+	}
+
+	return result.Total > (page+1)*limit, nil
+}
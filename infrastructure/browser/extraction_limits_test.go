@@ -0,0 +1,27 @@
+package browser
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestCapTextRespectsLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  int
+	}{
+		{"short text kept", "Hello", 10, 5},
+		{"long text cut", strings.Repeat("a", 3000), 2000, 2000},
+		{"cyrillic counted in characters", strings.Repeat("я", 30), 20, 20},
+		{"no limit", strings.Repeat("a", 50), 0, 50},
+	}
+	for _, tt := range tests {
+		got := capText(tt.text, tt.limit)
+		if n := utf8.RuneCountInString(got); n != tt.want || !utf8.ValidString(got) {
+			t.Errorf("%s: got %d characters (valid %v), want %d", tt.name, n, utf8.ValidString(got), tt.want)
+		}
+	}
+}
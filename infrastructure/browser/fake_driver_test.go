@@ -0,0 +1,55 @@
+package browser
+
+import (
+	"errors"
+	"io"
+
+	"github.com/sirupsen/logrus"
+	"github.com/tebeka/selenium"
+)
+
+// scriptDriver - WebDriver answering ExecuteScript with reply and FindElement with find (nothing is
+// found when it is nil), other commands are not implemented
+type scriptDriver struct {
+	selenium.WebDriver
+	reply func(script string, args []interface{}) (interface{}, error)
+	find  func(by, value string) (selenium.WebElement, error)
+	// scripts are the executed scripts in order
+	scripts []string
+}
+
+func (d *scriptDriver) FindElement(by, value string) (selenium.WebElement, error) {
+	if d.find == nil {
+		return nil, errors.New("no such element: Unable to locate element")
+	}
+	return d.find(by, value)
+}
+
+func (d *scriptDriver) ExecuteScript(script string, args []interface{}) (interface{}, error) {
+	d.scripts = append(d.scripts, script)
+	return d.reply(script, args)
+}
+
+// newFakeController - controller over wd with a silent logger and no element wait
+func newFakeController(wd selenium.WebDriver) *SeleniumController {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return &SeleniumController{wd: wd, logger: logger}
+}
+
+// fakeElement - element keeping typed keys, it goes stale after staleAfter keys when that is set
+type fakeElement struct {
+	selenium.WebElement
+	value      string
+	staleAfter int
+	keys       int
+}
+
+func (e *fakeElement) SendKeys(keys string) error {
+	if e.staleAfter > 0 && e.keys >= e.staleAfter {
+		return errors.New("stale element reference: element is not attached to the page document")
+	}
+	e.keys++
+	e.value += keys
+	return nil
+}
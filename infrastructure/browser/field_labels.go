@@ -0,0 +1,104 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tebeka/selenium"
+)
+
+// labelTextsScript - text of every <label> in document order, empty for labels that name no control
+// (label.control resolves both the for attribute and a nested control)
+const labelTextsScript = `
+	return Array.from(document.querySelectorAll('label')).map(label =>
+		label.control ? (label.textContent || '').trim() : '');
+`
+
+// looksLikeLabelText - reports whether the selector reads like field text ("Email", "Номер телефона")
+// rather than CSS or XPath. Bare tag names pass too, label lookup just finds nothing for them
+func looksLikeLabelText(selector string) bool {
+	if _, ok := parseSelectorStrategy(selector); ok {
+		return false
+	}
+	selector = strings.TrimSpace(selector)
+	return selector != "" && !strings.ContainsAny(selector, "#.[]=>/()")
+}
+
+// normalizeLabel - lower-cased label text without extra spaces, a trailing colon or required-field asterisk
+func normalizeLabel(text string) string {
+	text = strings.ToLower(strings.Join(strings.Fields(text), " "))
+	return strings.TrimSpace(strings.TrimRight(text, ":* "))
+}
+
+// matchLabel - index of the label best matching text: the same text, then one starting with it,
+// then one containing it, earlier labels winning ties. -1 when none matches
+func matchLabel(labels []string, text string) int {
+	want := normalizeLabel(text)
+	if want == "" {
+		return -1
+	}
+	best, bestRank := -1, 0
+	for i, label := range labels {
+		label = normalizeLabel(label)
+		rank := 0
+		switch {
+		case label == "":
+		case label == want:
+			rank = 3
+		case strings.HasPrefix(label, want):
+			rank = 2
+		case strings.Contains(label, want):
+			rank = 1
+		}
+		if rank > bestRank {
+			best, bestRank = i, rank
+		}
+	}
+	return best
+}
+
+// findByLabel - finds the control named by the label with the given text, nil when no label matches
+func (s *SeleniumController) findByLabel(text string) (selenium.WebElement, error) {
+	result, err := s.wd.ExecuteScript(labelTextsScript, nil)
+	if err != nil {
+		return nil, err
+	}
+	items, _ := result.([]interface{})
+	labels := make([]string, len(items))
+	for i, item := range items {
+		labels[i], _ = item.(string)
+	}
+
+	index := matchLabel(labels, text)
+	if index < 0 {
+		return nil, nil
+	}
+	label, err := s.wd.FindElement(selenium.ByXPATH, fmt.Sprintf("(//label)[%d]", index+1))
+	if err != nil {
+		return nil, err
+	}
+	if id, err := label.GetAttribute("for"); err == nil && id != "" {
+		return s.wd.FindElement(selenium.ByID, id)
+	}
+	return label.FindElement(selenium.ByCSSSelector, "input, textarea, select")
+}
+
+// findField - finds a field by selector or, when the selector is plain text, by the label naming it.
+// Like findElement it waits for a field that is not on the page yet
+func (s *SeleniumController) findField(ctx context.Context, selector string) (selenium.WebElement, error) {
+	byLabel := looksLikeLabelText(selector)
+	return pollElement(ctx, s.findTimeout, func() (selenium.WebElement, error) {
+		if byLabel {
+			element, err := s.findByLabel(selector)
+			if err != nil {
+				s.logger.Debugf("Label lookup for %q failed: %v", selector, err)
+			}
+			if element != nil {
+				s.logger.Debugf("Resolved field %q by its label", selector)
+				return element, nil
+			}
+		}
+		return s.findElementOnce(selector)
+	})
+}
@@ -0,0 +1,138 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"ai_automation/domain/interfaces"
+
+	"github.com/tebeka/selenium"
+)
+
+func TestMatchLabelPicksLabelledField(t *testing.T) {
+	// Labels of a sign-up form in document order, the fourth names no control
+	labels := []string{"Confirm email", "Email address *", "Name:", "", "Пароль"}
+
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"Name", 2},
+		{"email", 1},
+		{"Email address", 1},
+		{"confirm EMAIL", 0},
+		{"пароль", 4},
+		{"Phone", -1},
+		{"", -1},
+	}
+	for _, tt := range tests {
+		if got := matchLabel(labels, tt.text); got != tt.want {
+			t.Errorf("matchLabel(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestLooksLikeLabelText(t *testing.T) {
+	tests := map[string]bool{
+		"Email":              true,
+		"Номер телефона":     true,
+		"#email":             false,
+		"input[name=q]":      false,
+		"//input[@id='q']":   false,
+		"css=form input":     false,
+		"text=Email":         false,
+		"form.login > input": false,
+	}
+	for selector, want := range tests {
+		if got := looksLikeLabelText(selector); got != want {
+			t.Errorf("looksLikeLabelText(%q) = %v, want %v", selector, got, want)
+		}
+	}
+}
+
+// labelElement - <label> pointing at its control with for, or wrapping it
+type labelElement struct {
+	selenium.WebElement
+	forID  string
+	nested selenium.WebElement
+}
+
+func (l *labelElement) GetAttribute(name string) (string, error) {
+	if name == "for" {
+		return l.forID, nil
+	}
+	return "", nil
+}
+
+func (l *labelElement) FindElement(by, value string) (selenium.WebElement, error) {
+	if l.nested == nil {
+		return nil, errors.New("no such element: Unable to locate element")
+	}
+	return l.nested, nil
+}
+
+func TestFindFieldResolvesLabels(t *testing.T) {
+	email := &fakeElement{value: "email input"}
+	subscribe := &fakeElement{value: "nested checkbox"}
+	phone := &fakeElement{value: "phone text"}
+	labels := []*labelElement{
+		{forID: "email"},
+		{nested: subscribe},
+		{},
+	}
+	driver := &scriptDriver{
+		reply: func(script string, args []interface{}) (interface{}, error) {
+			if script != labelTextsScript {
+				return nil, fmt.Errorf("unexpected script")
+			}
+			return []interface{}{"Email address *", "Subscribe to news", "Phone:"}, nil
+		},
+		find: func(by, value string) (selenium.WebElement, error) {
+			for i, label := range labels {
+				if by == selenium.ByXPATH && value == fmt.Sprintf("(//label)[%d]", i+1) {
+					return label, nil
+				}
+			}
+			switch {
+			case by == selenium.ByID && value == "email", by == selenium.ByCSSSelector && value == "#email":
+				return email, nil
+			case by == selenium.ByXPATH && strings.Contains(value, "Phone"):
+				return phone, nil
+			}
+			return nil, errors.New("no such element: Unable to locate element")
+		},
+	}
+	c := newFakeController(driver)
+
+	tests := []struct {
+		selector string
+		want     *fakeElement
+	}{
+		{"Email address", email},
+		{"subscribe", subscribe},
+		// The label names no control, the text itself is looked up
+		{"Phone", phone},
+		{"#email", email},
+	}
+	for _, tt := range tests {
+		driver.scripts = nil
+		element, err := c.findField(context.Background(), tt.selector)
+		if err != nil {
+			t.Errorf("findField(%q): %v", tt.selector, err)
+			continue
+		}
+		if element != tt.want {
+			t.Errorf("findField(%q) = %v, want %s", tt.selector, element, tt.want.value)
+		}
+		if strings.HasPrefix(tt.selector, "#") && len(driver.scripts) > 0 {
+			t.Errorf("findField(%q) looked for labels of a CSS selector", tt.selector)
+		}
+	}
+
+	if _, err := c.findField(context.Background(), "Fax"); !errors.Is(err, interfaces.ErrElementNotFound) {
+		t.Errorf("findField(Fax) = %v, want ErrElementNotFound", err)
+	}
+}
@@ -0,0 +1,68 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"ai_automation/domain/entities"
+)
+
+// listFramesScript - walks iframes and frames in document order. Same-origin frames report their
+// current location and are descended into; cross-origin ones only expose the src attribute
+const listFramesScript = `
+	const frames = [];
+	function walk(doc, depth) {
+		doc.querySelectorAll('iframe, frame').forEach(function(el) {
+			let childDoc = null;
+			try {
+				childDoc = el.contentDocument;
+			} catch (e) {}
+			let url = el.src || '';
+			if (childDoc) {
+				try {
+					url = el.contentWindow.location.href;
+				} catch (e) {}
+			}
+			frames.push({
+				name: el.getAttribute('name') || el.id || '',
+				url: url,
+				same_origin: childDoc !== null,
+				depth: depth
+			});
+			if (childDoc) {
+				walk(childDoc, depth + 1);
+			}
+		});
+	}
+	walk(document, 1);
+	return frames;
+`
+
+// ListFrames - returns the frame tree of the current page
+func (s *SeleniumController) ListFrames(ctx context.Context) ([]entities.FrameInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	raw, err := s.wd.ExecuteScript(listFramesScript, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list frames: %w", err)
+	}
+	return decodeFrames(raw)
+}
+
+// decodeFrames - converts the result of listFramesScript
+func decodeFrames(raw interface{}) ([]entities.FrameInfo, error) {
+	frames := []entities.FrameInfo{}
+	if raw == nil {
+		return frames, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &frames); err != nil {
+		return nil, fmt.Errorf("unexpected frame list: %w", err)
+	}
+	return frames, nil
+}
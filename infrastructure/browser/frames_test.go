@@ -0,0 +1,33 @@
+package browser
+
+import "testing"
+
+func TestDecodeFramesListsBothIframes(t *testing.T) {
+	// What listFramesScript returns through WebDriver for a page with a same-origin
+	// and a cross-origin iframe
+	raw := []interface{}{
+		map[string]interface{}{"name": "checkout", "url": "https://shop.example/pay", "same_origin": true, "depth": float64(1)},
+		map[string]interface{}{"name": "", "url": "https://ads.example/banner", "same_origin": false, "depth": float64(1)},
+	}
+
+	frames, err := decodeFrames(raw)
+	if err != nil {
+		t.Fatalf("decodeFrames: %v", err)
+	}
+	if len(frames) != 2 {
+		t.Fatalf("got %d frames, want 2: %+v", len(frames), frames)
+	}
+	if frames[0].URL != "https://shop.example/pay" || frames[0].Name != "checkout" || !frames[0].SameOrigin {
+		t.Errorf("first frame = %+v", frames[0])
+	}
+	if frames[1].URL != "https://ads.example/banner" || frames[1].SameOrigin || frames[1].Depth != 1 {
+		t.Errorf("second frame = %+v", frames[1])
+	}
+}
+
+func TestDecodeFramesEmptyPage(t *testing.T) {
+	frames, err := decodeFrames(nil)
+	if err != nil || len(frames) != 0 {
+		t.Errorf("decodeFrames(nil) = %v, %v, want no frames", frames, err)
+	}
+}
@@ -0,0 +1,149 @@
+package browser
+
+import (
+	"strings"
+	"unicode"
+
+	"ai_automation/domain/entities"
+)
+
+const (
+	// minFuzzyScore - minimal similarity for fallback element to be considered a match
+	minFuzzyScore = 0.8
+	// minFuzzyMargin - how much the best candidate must beat the runner-up, so that
+	// near-identical selectors (#delete-1 and #delete-2) never stand in for each other
+	minFuzzyMargin = 0.1
+)
+
+// findClosestSelector - searches extracted page elements for the one closest to the given selector.
+// Returns empty string when no element is similar enough or the best match is not unique
+func findClosestSelector(pageInfo *entities.PageInfo, selector string) (string, float64) {
+	if pageInfo == nil || strings.TrimSpace(selector) == "" {
+		return "", 0
+	}
+
+	// Best score of every candidate, an element may be listed as a button and an element
+	scores := map[string]float64{}
+	consider := func(candidateSelector string, texts ...string) {
+		if candidateSelector == "" || candidateSelector == selector {
+			return
+		}
+		for _, text := range texts {
+			if text == "" {
+				continue
+			}
+			if score := similarity(selector, text); score > scores[candidateSelector] {
+				scores[candidateSelector] = score
+			}
+		}
+	}
+
+	for _, elem := range pageInfo.Buttons {
+		consider(elem.Selector, append([]string{elem.Selector, elem.Text}, elem.AllSelectors...)...)
+	}
+	for _, elem := range pageInfo.Elements {
+		consider(elem.Selector, append([]string{elem.Selector, elem.Text, elem.Placeholder}, elem.AllSelectors...)...)
+	}
+	for _, link := range pageInfo.Links {
+		consider(link.Selector, link.Selector, link.Text)
+	}
+
+	bestSelector := ""
+	bestScore, secondScore := 0.0, 0.0
+	for candidate, score := range scores {
+		switch {
+		case score > bestScore:
+			secondScore = bestScore
+			bestScore = score
+			bestSelector = candidate
+		case score > secondScore:
+			secondScore = score
+		}
+	}
+
+	if bestScore < minFuzzyScore || bestScore-secondScore < minFuzzyMargin {
+		return "", bestScore
+	}
+	return bestSelector, bestScore
+}
+
+// similarity - combines normalized Levenshtein similarity and token overlap, returns value in [0, 1]
+func similarity(a, b string) float64 {
+	a = strings.ToLower(strings.TrimSpace(a))
+	b = strings.ToLower(strings.TrimSpace(b))
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	maxLen := len(ra)
+	if len(rb) > maxLen {
+		maxLen = len(rb)
+	}
+	editScore := 1 - float64(levenshtein(ra, rb))/float64(maxLen)
+
+	overlapScore := tokenOverlap(tokenize(a), tokenize(b))
+	if overlapScore > editScore {
+		return overlapScore
+	}
+	return editScore
+}
+
+// tokenize - splits selector or text into lowercase word tokens, dropping CSS/XPath punctuation
+func tokenize(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// tokenOverlap - Jaccard index of two token sets
+func tokenOverlap(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	setA := map[string]bool{}
+	for _, token := range a {
+		setA[token] = true
+	}
+	setB := map[string]bool{}
+	for _, token := range b {
+		setB[token] = true
+	}
+
+	intersection := 0
+	for token := range setA {
+		if setB[token] {
+			intersection++
+		}
+	}
+	union := len(setA) + len(setB) - intersection
+
+	return float64(intersection) / float64(union)
+}
+
+// levenshtein - edit distance between two rune slices
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
@@ -0,0 +1,174 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"ai_automation/domain/interfaces"
+)
+
+const (
+	// defaultLoadAllScrolls - scroll cap of LoadAll when the caller passes zero
+	defaultLoadAllScrolls = 20
+	// loadAllWait - how long new content may take to appear after a scroll or a "load more" click
+	loadAllWait = 3 * time.Second
+)
+
+// contentSizeScript - document height and element count, either one grows when a list loads more items
+const contentSizeScript = `
+	const body = document.body;
+	return JSON.stringify({
+		height: body ? body.scrollHeight : 0,
+		elements: document.getElementsByTagName('*').length
+	});
+`
+
+// loadMoreScript - clicks a visible "load more" style button, returns its label or empty string
+const loadMoreScript = `
+	const phrases = [
+		'load more', 'show more', 'see more', 'more results', 'view more',
+		'показать еще', 'показать ещё', 'загрузить еще', 'загрузить ещё', 'ещё', 'еще'
+	];
+	const candidates = document.querySelectorAll('button, [role="button"], a, input[type="button"]');
+	for (const el of candidates) {
+		const text = (el.textContent || el.value || '').trim().replace(/\s+/g, ' ').toLowerCase();
+		const rect = el.getBoundingClientRect();
+		if (!phrases.includes(text) || rect.width === 0 || rect.height === 0 || el.disabled) continue;
+		el.scrollIntoView({block: 'center'});
+		el.click();
+		return text;
+	}
+	return '';
+`
+
+// contentSize - how much content the page has, compared before and after loading more
+type contentSize struct {
+	Height   int `json:"height"`
+	Elements int `json:"elements"`
+}
+
+// grewFrom - reports whether the page got more content than before
+func (c contentSize) grewFrom(before contentSize) bool {
+	return c.Height > before.Height || c.Elements > before.Elements
+}
+
+// growingPage - page operations the infinite scroll loop needs
+type growingPage interface {
+	contentSize() (contentSize, error)
+	scrollToBottom() error
+	// clickLoadMore clicks a "load more" button, reports false when there is none
+	clickLoadMore() (bool, error)
+}
+
+// scrollLoader - scrolls to the bottom until the page stops growing or maxScrolls is reached
+type scrollLoader struct {
+	page       growingPage
+	maxScrolls int
+	// wait is how long new content may take to appear, poll how often it is checked
+	wait time.Duration
+	poll time.Duration
+}
+
+// run - returns how many times new content was loaded. When scrolling loads nothing the loader
+// tries a "load more" button once before giving up
+func (l scrollLoader) run(ctx context.Context) (int, error) {
+	before, err := l.page.contentSize()
+	if err != nil {
+		return 0, err
+	}
+
+	loads := 0
+	for scroll := 0; scroll < l.maxScrolls; scroll++ {
+		if err := l.page.scrollToBottom(); err != nil {
+			return loads, err
+		}
+		after, grew, err := l.waitForGrowth(ctx, before)
+		if err != nil {
+			return loads, err
+		}
+		if !grew {
+			clicked, err := l.page.clickLoadMore()
+			if err != nil || !clicked {
+				return loads, err
+			}
+			if after, grew, err = l.waitForGrowth(ctx, before); err != nil || !grew {
+				return loads, err
+			}
+		}
+		loads++
+		before = after
+	}
+	return loads, nil
+}
+
+// waitForGrowth - polls the content size until it exceeds before or wait runs out
+func (l scrollLoader) waitForGrowth(ctx context.Context, before contentSize) (contentSize, bool, error) {
+	deadline := time.Now().Add(l.wait)
+	for {
+		current, err := l.page.contentSize()
+		if err != nil {
+			return before, false, err
+		}
+		if current.grewFrom(before) {
+			return current, true, nil
+		}
+		if time.Now().After(deadline) {
+			return current, false, nil
+		}
+		if err := sleepWithContext(ctx, l.poll); err != nil {
+			return current, false, err
+		}
+	}
+}
+
+// LoadAll - scrolls an infinite list (or presses its "load more" button) until no new content
+// appears or maxScrolls is reached, returns how many times more content was loaded
+func (s *SeleniumController) LoadAll(ctx context.Context, maxScrolls int) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if maxScrolls <= 0 {
+		maxScrolls = defaultLoadAllScrolls
+	}
+
+	loader := scrollLoader{page: s, maxScrolls: maxScrolls, wait: loadAllWait, poll: urlPollInterval}
+	loads, err := loader.run(ctx)
+	if err != nil {
+		return loads, err
+	}
+	s.logger.Infof("Loaded more content %d times (cap %d)", loads, maxScrolls)
+	return loads, nil
+}
+
+func (s *SeleniumController) contentSize() (contentSize, error) {
+	var size contentSize
+	raw, err := s.wd.ExecuteScript(contentSizeScript, nil)
+	if err != nil {
+		return size, driverError(interfaces.ErrCommand, err, "failed to load more content: %v", err)
+	}
+	encoded, _ := raw.(string)
+	if err := json.Unmarshal([]byte(encoded), &size); err != nil {
+		return size, newBrowserError(interfaces.ErrCommand, err, "failed to load more content: bad content size: %v", err)
+	}
+	return size, nil
+}
+
+func (s *SeleniumController) scrollToBottom() error {
+	if _, err := s.wd.ExecuteScript("window.scrollTo(0, document.body.scrollHeight); return null;", nil); err != nil {
+		return driverError(interfaces.ErrCommand, err, "failed to load more content: %v", err)
+	}
+	return nil
+}
+
+func (s *SeleniumController) clickLoadMore() (bool, error) {
+	raw, err := s.wd.ExecuteScript(loadMoreScript, nil)
+	if err != nil {
+		return false, driverError(interfaces.ErrCommand, err, "failed to load more content: %v", err)
+	}
+	label, _ := raw.(string)
+	if label != "" {
+		s.logger.Infof("Clicked %q to load more content", label)
+	}
+	return label != "", nil
+}
@@ -0,0 +1,72 @@
+package browser
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeFeed - infinite list that appends a batch of items on every scroll to the bottom,
+// optionally only after its "load more" button is pressed
+type fakeFeed struct {
+	total, batch, loaded int
+	buttonOnly           bool
+	pendingScroll        bool
+	scrolls, clicks      int
+}
+
+func (f *fakeFeed) contentSize() (contentSize, error) {
+	if f.pendingScroll && !f.buttonOnly {
+		f.load()
+	}
+	f.pendingScroll = false
+	return contentSize{Height: 100 * f.loaded, Elements: f.loaded}, nil
+}
+
+func (f *fakeFeed) scrollToBottom() error {
+	f.scrolls++
+	f.pendingScroll = true
+	return nil
+}
+
+func (f *fakeFeed) clickLoadMore() (bool, error) {
+	if !f.buttonOnly || f.loaded >= f.total {
+		return false, nil
+	}
+	f.clicks++
+	f.load()
+	return true, nil
+}
+
+func (f *fakeFeed) load() {
+	f.loaded += f.batch
+	if f.loaded > f.total {
+		f.loaded = f.total
+	}
+}
+
+func TestScrollLoaderLoadsEveryItem(t *testing.T) {
+	tests := []struct {
+		name       string
+		feed       *fakeFeed
+		maxScrolls int
+		wantItems  int
+		wantLoads  int
+	}{
+		{"infinite scroll", &fakeFeed{total: 95, batch: 20, loaded: 20}, 20, 95, 4},
+		{"load more button", &fakeFeed{total: 50, batch: 10, loaded: 10, buttonOnly: true}, 20, 50, 4},
+		{"capped", &fakeFeed{total: 1000, batch: 10, loaded: 10}, 3, 40, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loader := scrollLoader{page: tt.feed, maxScrolls: tt.maxScrolls, wait: 5 * time.Millisecond, poll: time.Millisecond}
+			loads, err := loader.run(context.Background())
+			if err != nil {
+				t.Fatalf("run: %v", err)
+			}
+			if tt.feed.loaded != tt.wantItems || loads != tt.wantLoads {
+				t.Errorf("loaded %d items in %d loads, want %d in %d", tt.feed.loaded, loads, tt.wantItems, tt.wantLoads)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package browser
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"ai_automation/domain/interfaces"
+)
+
+// navigationProbeScript - reads the HTTP status of the loaded document and the network error
+// code Chrome shows on its own error page (chrome-error://). Other pages may have their own
+// .error-code elements, so it is only read on the error page
+const navigationProbeScript = `
+	const nav = performance.getEntriesByType('navigation')[0];
+	const code = location.protocol === 'chrome-error:' ? document.querySelector('.error-code') : null;
+	return JSON.stringify({
+		status: (nav && nav.responseStatus) || 0,
+		url: location.href,
+		net_error: code ? code.textContent.trim() : ''
+	});
+`
+
+// navigationProbe - outcome of a page load as seen from inside the page
+type navigationProbe struct {
+	Status   int    `json:"status"`
+	URL      string `json:"url"`
+	NetError string `json:"net_error"`
+}
+
+// netErrorDescriptions - Chrome network error codes worth naming for the AI
+var netErrorDescriptions = map[string]string{
+	"ERR_CONNECTION_REFUSED":       "connection refused",
+	"ERR_NAME_NOT_RESOLVED":        "host not found",
+	"ERR_CONNECTION_TIMED_OUT":     "connection timed out",
+	"ERR_CONNECTION_RESET":         "connection reset",
+	"ERR_INTERNET_DISCONNECTED":    "no internet connection",
+	"ERR_CERT_AUTHORITY_INVALID":   "invalid TLS certificate",
+	"ERR_CERT_COMMON_NAME_INVALID": "invalid TLS certificate",
+	"ERR_SSL_PROTOCOL_ERROR":       "TLS error",
+	"ERR_TOO_MANY_REDIRECTS":       "too many redirects",
+}
+
+// err - returns "navigation failed: ..." for an HTTP error status or a browser error page, nil otherwise.
+// A zero status means the browser did not report one, which is not treated as a failure. NetError
+// only counts on the browser error page, a site may show an error code of its own
+func (p navigationProbe) err() error {
+	if strings.HasPrefix(p.URL, "chrome-error://") {
+		code := strings.TrimPrefix(p.NetError, "net::")
+		if description, ok := netErrorDescriptions[code]; ok {
+			return newBrowserError(interfaces.ErrNavigation, nil, "navigation failed: %s (%s)", description, code)
+		}
+		if code != "" {
+			return newBrowserError(interfaces.ErrNavigation, nil, "navigation failed: %s", code)
+		}
+		return newBrowserError(interfaces.ErrNavigation, nil, "navigation failed: browser error page")
+	}
+	if p.Status >= 400 {
+		return newBrowserError(interfaces.ErrNavigation, nil, "navigation failed: %d %s", p.Status, http.StatusText(p.Status))
+	}
+	return nil
+}
+
+// checkNavigation - inspects the loaded page for an error status or a browser error page
+func (s *SeleniumController) checkNavigation() error {
+	raw, err := s.wd.ExecuteScript(navigationProbeScript, nil)
+	if err != nil {
+		s.logger.Debugf("Navigation probe failed: %v", err)
+		return nil
+	}
+	text, ok := raw.(string)
+	if !ok {
+		return nil
+	}
+
+	var probe navigationProbe
+	if err := json.Unmarshal([]byte(text), &probe); err != nil {
+		return nil
+	}
+	return probe.err()
+}
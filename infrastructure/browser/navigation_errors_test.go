@@ -0,0 +1,74 @@
+package browser
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNavigationProbeDetectsHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/missing")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	resp.Body.Close()
+
+	// What the probe script reports for the page the browser loaded
+	probe := navigationProbe{Status: resp.StatusCode, URL: server.URL + "/missing"}
+	err = probe.err()
+	if err == nil {
+		t.Fatal("404 page was not detected")
+	}
+	if err.Error() != "navigation failed: 404 Not Found" {
+		t.Errorf("error = %q, want %q", err, "navigation failed: 404 Not Found")
+	}
+}
+
+func TestNavigationProbe(t *testing.T) {
+	tests := []struct {
+		name  string
+		probe navigationProbe
+		want  string
+	}{
+		{"ok", navigationProbe{Status: 200, URL: "https://example.com"}, ""},
+		{"no status reported", navigationProbe{URL: "https://example.com"}, ""},
+		{"server error", navigationProbe{Status: 500, URL: "https://example.com"}, "navigation failed: 500 Internal Server Error"},
+		{"connection refused", navigationProbe{URL: "chrome-error://chromewebdata/", NetError: "ERR_CONNECTION_REFUSED"}, "connection refused"},
+		{"unknown net error", navigationProbe{URL: "chrome-error://chromewebdata/", NetError: "net::ERR_BLOCKED_BY_CLIENT"}, "ERR_BLOCKED_BY_CLIENT"},
+		{"error page without code", navigationProbe{URL: "chrome-error://chromewebdata/"}, "browser error page"},
+		{"site with its own error code", navigationProbe{Status: 200, URL: "https://status.example", NetError: "ERR_CONNECTION_REFUSED"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.probe.err()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("err = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckNavigationIgnoresErrorCodeOfSite(t *testing.T) {
+	// A loaded status page whose widget has class error-code, as the browser reports it
+	driver := &scriptDriver{reply: func(script string, args []interface{}) (interface{}, error) {
+		return `{"status": 200, "url": "https://status.example/", "net_error": "ERR_CONNECTION_REFUSED"}`, nil
+	}}
+	s := newFakeController(driver)
+
+	if err := s.checkNavigation(); err != nil {
+		t.Errorf("checkNavigation = %v, want the loaded page accepted", err)
+	}
+	if len(driver.scripts) != 1 || !strings.Contains(driver.scripts[0], "location.protocol === 'chrome-error:'") {
+		t.Errorf("probe script does not limit .error-code to the browser error page: %q", driver.scripts)
+	}
+}
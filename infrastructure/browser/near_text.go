@@ -0,0 +1,134 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"ai_automation/domain/entities"
+	"ai_automation/domain/interfaces"
+)
+
+// maxNearTextLevels - how many ancestors above the anchor text are searched for the element
+const maxNearTextLevels = 8
+
+// nearTextCandidate - element with the role found around an anchor: Levels is how many ancestors
+// up from the anchor the element shares a container with it, Distance is between their centers in pixels
+type nearTextCandidate struct {
+	Element  entities.PageElement `json:"element"`
+	Levels   int                  `json:"levels"`
+	Distance float64              `json:"distance"`
+}
+
+// nearTextScript - finds the innermost elements containing the anchor text, then climbs their
+// ancestors and collects visible elements with the role inside each, nearest container first
+const nearTextScript = accessibilityHelpersScript + selectorHelpersScript + `
+	const anchorText = arguments[0].trim().toLowerCase().replace(/\s+/g, ' ');
+	const role = arguments[1];
+	const maxLevels = arguments[2];
+	const normalize = text => (text || '').trim().toLowerCase().replace(/\s+/g, ' ');
+
+	const anchors = [];
+	document.querySelectorAll('body *').forEach(el => {
+		if (['script', 'style', 'noscript'].includes(el.tagName.toLowerCase())) return;
+		if (!normalize(el.textContent).includes(anchorText)) return;
+		// Only the innermost element holding the text is an anchor
+		for (const child of el.children) {
+			if (normalize(child.textContent).includes(anchorText)) return;
+		}
+		anchors.push(el);
+	});
+
+	const center = el => {
+		const rect = el.getBoundingClientRect();
+		return {x: rect.left + rect.width / 2, y: rect.top + rect.height / 2, visible: rect.width > 0 && rect.height > 0};
+	};
+
+	const found = new Map();
+	anchors.slice(0, 20).forEach(anchor => {
+		const anchorCenter = center(anchor);
+		let container = anchor;
+		for (let levels = 0; container && levels <= maxLevels; levels++, container = container.parentElement) {
+			const inside = [container, ...container.querySelectorAll('*')];
+			inside.forEach(el => {
+				if (ariaRole(el) !== role) return;
+				const style = window.getComputedStyle(el);
+				if (style.visibility === 'hidden' || style.display === 'none') return;
+				const elCenter = center(el);
+				if (!elCenter.visible) return;
+				const distance = Math.hypot(elCenter.x - anchorCenter.x, elCenter.y - anchorCenter.y);
+				const known = found.get(el);
+				if (known && (known.levels < levels || (known.levels === levels && known.distance <= distance))) return;
+				found.set(el, {
+					levels: levels,
+					distance: distance,
+					element: {
+						tag_name: el.tagName.toLowerCase(),
+						text: el.textContent ? el.textContent.trim().substring(0, 200) : '',
+						value: el.value || '',
+						attributes: {},
+						selector: cssPath(el),
+						role: role,
+						accessible_name: accessibleName(el),
+						is_visible: true,
+						is_clickable: !el.disabled,
+						is_disabled: isDisabled(el)
+					}
+				});
+			});
+			if (container === document.body) break;
+		}
+	});
+	return JSON.stringify(Array.from(found.values()));
+`
+
+// FindElementNearText - returns the element with the ARIA role (button, link, textbox...) closest
+// to the text in the DOM, e.g. the "Buy" button of the product named by the text.
+// The element sharing the smallest container with the text wins, ties go to the one nearer on screen
+func (s *SeleniumController) FindElementNearText(ctx context.Context, anchorText string, role string) (*entities.PageElement, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	anchorText = strings.TrimSpace(anchorText)
+	role = strings.ToLower(strings.TrimSpace(role))
+	if anchorText == "" || role == "" {
+		return nil, fmt.Errorf("anchor text and element role are required")
+	}
+
+	raw, err := s.wd.ExecuteScript(nearTextScript, []interface{}{anchorText, role, maxNearTextLevels})
+	if err != nil {
+		return nil, driverError(interfaces.ErrElementNotFound, err, "failed to find %s near %q: %v", role, anchorText, err)
+	}
+	data, ok := raw.(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected find near text result: %v", raw)
+	}
+
+	var candidates []nearTextCandidate
+	if err := json.Unmarshal([]byte(data), &candidates); err != nil {
+		return nil, fmt.Errorf("failed to parse elements: %w", err)
+	}
+	nearest, ok := nearestCandidate(candidates)
+	if !ok {
+		return nil, newBrowserError(interfaces.ErrElementNotFound, nil, "no %s found near %q", role, anchorText)
+	}
+	return &nearest.Element, nil
+}
+
+// nearestCandidate - picks the element sharing the smallest container with the anchor,
+// then the one nearest on screen
+func nearestCandidate(candidates []nearTextCandidate) (nearTextCandidate, bool) {
+	if len(candidates) == 0 {
+		return nearTextCandidate{}, false
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Levels != candidates[j].Levels {
+			return candidates[i].Levels < candidates[j].Levels
+		}
+		return candidates[i].Distance < candidates[j].Distance
+	})
+	return candidates[0], true
+}
@@ -0,0 +1,128 @@
+package browser
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ai_automation/domain/entities"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestNearestCandidatePicksButtonOfAnchorRow(t *testing.T) {
+	buy := func(selector string) entities.PageElement {
+		return entities.PageElement{TagName: "button", Text: "Buy", Selector: selector, Role: "button"}
+	}
+	// Buttons nearTextScript reports for "iPhone 15" in a product list: the row's own button shares
+	// the <li> with the name, the others only the <ul>, though the next row's button is nearer on screen
+	candidates := []nearTextCandidate{
+		{Element: buy("#products > li:nth-of-type(1) > button"), Levels: 2, Distance: 180},
+		{Element: buy("#products > li:nth-of-type(2) > button"), Levels: 1, Distance: 240},
+		{Element: buy("#products > li:nth-of-type(3) > button"), Levels: 2, Distance: 60},
+		{Element: entities.PageElement{TagName: "button", Text: "Checkout", Selector: "#checkout", Role: "button"}, Levels: 4, Distance: 20},
+	}
+
+	nearest, ok := nearestCandidate(candidates)
+	if !ok {
+		t.Fatal("no candidate chosen")
+	}
+	if want := "#products > li:nth-of-type(2) > button"; nearest.Element.Selector != want {
+		t.Errorf("chose %s, want the button in the anchor's row %s", nearest.Element.Selector, want)
+	}
+
+	if _, ok := nearestCandidate(nil); ok {
+		t.Error("a candidate was chosen from an empty list")
+	}
+}
+
+func TestNearestCandidateBreaksTiesByDistance(t *testing.T) {
+	candidates := []nearTextCandidate{
+		{Element: entities.PageElement{Selector: "#details"}, Levels: 1, Distance: 300},
+		{Element: entities.PageElement{Selector: "#add-to-cart"}, Levels: 1, Distance: 40},
+	}
+	nearest, _ := nearestCandidate(candidates)
+	if nearest.Element.Selector != "#add-to-cart" {
+		t.Errorf("chose %s, want the nearer #add-to-cart", nearest.Element.Selector)
+	}
+}
+
+// newHeadlessController - headless browser on a temporary profile for tests running page scripts.
+// Skipped in short mode and where ChromeDriver or Chrome is not installed
+func newHeadlessController(t *testing.T) *SeleniumController {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("browser tests are skipped in short mode")
+	}
+	if _, err := findChromeDriver(""); err != nil {
+		t.Skipf("no ChromeDriver: %v", err)
+	}
+	t.Setenv("BROWSER_HEADLESS", "true")
+	t.Setenv("DOWNLOAD_DIR", t.TempDir())
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	controller, err := NewIsolatedSeleniumController(logger)
+	if err != nil {
+		t.Skipf("browser did not start: %v", err)
+	}
+	t.Cleanup(func() { controller.Close() })
+	return controller
+}
+
+// productListPage - rows of a product list each with its own Buy button. The rows are tight, so the
+// Buy button of the row below a product is about as near on screen as the product's own
+const productListPage = `<!DOCTYPE html>
+<html><body>
+<ul id="products" style="list-style: none; margin: 0; padding: 0">
+	<li style="display: flex; justify-content: space-between; width: 600px; height: 24px">
+		<span>iPhone 14</span><button id="buy-14">Buy</button></li>
+	<li style="display: flex; justify-content: space-between; width: 600px; height: 24px">
+		<span>iPhone 15</span><button id="buy-15">Buy</button></li>
+	<li style="display: flex; justify-content: space-between; width: 600px; height: 24px">
+		<span>Pixel 9</span><button id="buy-pixel">Buy</button></li>
+	<li style="display: flex; justify-content: space-between; width: 600px; height: 24px">
+		<span>iPhone 16</span><button id="buy-16" style="display: none">Buy</button></li>
+</ul>
+<button id="checkout">Checkout</button>
+</body></html>`
+
+func TestFindElementNearTextInServedList(t *testing.T) {
+	controller := newHeadlessController(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, productListPage)
+	}))
+	t.Cleanup(server.Close)
+
+	ctx := context.Background()
+	if err := controller.Navigate(ctx, server.URL); err != nil {
+		t.Fatalf("Navigate: %v", err)
+	}
+
+	tests := []struct {
+		anchor string
+		want   string
+	}{
+		{"iPhone 14", "#buy-14"},
+		{"iPhone 15", "#buy-15"},
+		{"pixel 9", "#buy-pixel"},
+	}
+	for _, tt := range tests {
+		element, err := controller.FindElementNearText(ctx, tt.anchor, "button")
+		if err != nil {
+			t.Errorf("%s: %v", tt.anchor, err)
+			continue
+		}
+		if element.Selector != tt.want || element.Text != "Buy" {
+			t.Errorf("%s: found %s %q, want %s", tt.anchor, element.Selector, element.Text, tt.want)
+		}
+	}
+
+	// The row's own button is hidden, so the nearest visible one belongs to another row
+	if element, err := controller.FindElementNearText(ctx, "iPhone 16", "button"); err != nil || element.Selector == "#buy-16" {
+		t.Errorf("iPhone 16: found %+v, %v, want a visible button", element, err)
+	}
+}
@@ -0,0 +1,198 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// networkIdleTimeout - how long WAIT_STRATEGY=networkidle waits for the network before moving on
+	networkIdleTimeout = 5 * time.Second
+	// networkQuietPeriod - how long no request may finish for the network to count as idle
+	networkQuietPeriod = 500 * time.Millisecond
+	// longPollAge - requests open longer than that are long polls or streams that never finish
+	longPollAge = 2 * time.Second
+)
+
+// Wait strategies of WAIT_STRATEGY
+const (
+	waitDOMContentLoaded = "domcontentloaded"
+	waitNetworkIdle      = "networkidle"
+)
+
+// ignoredRequestPatterns - analytics beacons and push channels that keep firing on an otherwise idle page
+var ignoredRequestPatterns = []string{
+	"google-analytics.com", "googletagmanager.com", "doubleclick.net", "analytics.google.com",
+	"mc.yandex.ru", "hotjar.", "segment.io", "sentry.io", "clarity.ms", "facebook.com/tr",
+	"/socket.io/", "/sockjs", "/signalr", "/cable", "/collect?", "/beacon",
+}
+
+// networkTrackerScript - wraps fetch and XMLHttpRequest once per document to count open requests
+// and reports them together with resources that finished within arguments[0] milliseconds.
+// Requests started before the wrapper was installed are only seen once they finish
+const networkTrackerScript = `
+	const recentMs = arguments[0];
+	if (!window.__aiNetwork) {
+		const tracker = {next: 0, open: {}};
+		window.__aiNetwork = tracker;
+		const track = (url) => {
+			const id = tracker.next++;
+			tracker.open[id] = {url: String(url), started: performance.now()};
+			return () => { delete tracker.open[id]; };
+		};
+		if (window.fetch) {
+			const fetch = window.fetch;
+			window.fetch = function(input, init) {
+				const done = track(input && input.url ? input.url : input);
+				return fetch.apply(this, arguments).finally(done);
+			};
+		}
+		const xhrOpen = XMLHttpRequest.prototype.open;
+		const xhrSend = XMLHttpRequest.prototype.send;
+		XMLHttpRequest.prototype.open = function(method, url) {
+			this.__aiURL = url;
+			return xhrOpen.apply(this, arguments);
+		};
+		XMLHttpRequest.prototype.send = function() {
+			const done = track(this.__aiURL);
+			this.addEventListener('loadend', done);
+			return xhrSend.apply(this, arguments);
+		};
+	}
+	const now = performance.now();
+	const open = Object.values(window.__aiNetwork.open).map(r => ({url: r.url, age_ms: now - r.started}));
+	const finished = performance.getEntriesByType('resource')
+		.filter(e => now - e.responseEnd < recentMs)
+		.map(e => ({url: e.name, age_ms: now - e.responseEnd}));
+	return JSON.stringify({open: open, finished: finished});
+`
+
+// networkRequest - a request of the page, AgeMS is how long it is open or how long ago it finished
+type networkRequest struct {
+	URL   string  `json:"url"`
+	AgeMS float64 `json:"age_ms"`
+}
+
+func (r networkRequest) age() time.Duration {
+	return time.Duration(r.AgeMS * float64(time.Millisecond))
+}
+
+// networkActivity - open requests and recently finished resources of the page
+type networkActivity struct {
+	Open     []networkRequest `json:"open"`
+	Finished []networkRequest `json:"finished"`
+}
+
+// busy - reports whether a request that matters is open or finished within quiet.
+// Websockets, long polls and analytics beacons are left out, they never settle
+func (a networkActivity) busy(quiet time.Duration) bool {
+	for _, request := range a.Open {
+		if request.age() < longPollAge && !ignoredRequest(request.URL) {
+			return true
+		}
+	}
+	for _, request := range a.Finished {
+		if request.age() < quiet && !ignoredRequest(request.URL) {
+			return true
+		}
+	}
+	return false
+}
+
+// ignoredRequest - reports whether url is a push channel or a tracking request
+func ignoredRequest(url string) bool {
+	url = strings.ToLower(url)
+	if strings.HasPrefix(url, "ws://") || strings.HasPrefix(url, "wss://") {
+		return true
+	}
+	for _, pattern := range ignoredRequestPatterns {
+		if strings.Contains(url, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// idleWaiter - polls the network activity of the page until it is idle or timeout runs out
+type idleWaiter struct {
+	activity func() (networkActivity, error)
+	quiet    time.Duration
+	timeout  time.Duration
+	poll     time.Duration
+}
+
+// wait - reports whether the network became idle. Running out of time is not an error:
+// the page is usable, it just keeps talking to its server
+func (w idleWaiter) wait(ctx context.Context) (bool, error) {
+	deadline := time.Now().Add(w.timeout)
+	for {
+		activity, err := w.activity()
+		if err != nil {
+			return false, err
+		}
+		if !activity.busy(w.quiet) {
+			return true, nil
+		}
+		if time.Now().After(deadline) {
+			return false, nil
+		}
+		if err := sleepWithContext(ctx, w.poll); err != nil {
+			return false, err
+		}
+	}
+}
+
+// pageLoadStrategy - WebDriver pageLoadStrategy for a WAIT_STRATEGY: "eager" returns from
+// navigation at DOMContentLoaded, "normal" at the load event
+func pageLoadStrategy(strategy string) string {
+	if strategy == waitDOMContentLoaded {
+		return "eager"
+	}
+	return "normal"
+}
+
+// readyStateReached - reports whether document.readyState satisfies the wait strategy
+func readyStateReached(strategy, state string) bool {
+	if strategy == waitDOMContentLoaded {
+		return state == "interactive" || state == "complete"
+	}
+	return state == "complete"
+}
+
+// networkActivity - reads open and recently finished requests of the current page
+func (s *SeleniumController) networkActivity() (networkActivity, error) {
+	var activity networkActivity
+	raw, err := s.wd.ExecuteScript(networkTrackerScript, []interface{}{networkQuietPeriod.Milliseconds()})
+	if err != nil {
+		return activity, err
+	}
+	encoded, _ := raw.(string)
+	if err := json.Unmarshal([]byte(encoded), &activity); err != nil {
+		return activity, fmt.Errorf("failed to decode network activity: %w", err)
+	}
+	return activity, nil
+}
+
+// waitForNetworkIdle - with WAIT_STRATEGY=networkidle waits until the page stops loading data,
+// a page that never does is used after networkIdleTimeout
+func (s *SeleniumController) waitForNetworkIdle(ctx context.Context) error {
+	if s.waitStrategy != waitNetworkIdle {
+		return nil
+	}
+	waiter := idleWaiter{activity: s.networkActivity, quiet: networkQuietPeriod, timeout: networkIdleTimeout, poll: urlPollInterval}
+	idle, err := waiter.wait(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		s.logger.Debugf("Network idle check failed: %v", err)
+		return nil
+	}
+	if !idle {
+		s.logger.Debugf("Network still busy after %s, continuing", networkIdleTimeout)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package browser
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestIgnoredRequest(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"wss://chat.example.com/live", true},
+		{"https://www.google-analytics.com/g/collect?v=2", true},
+		{"https://example.com/socket.io/?EIO=4&transport=polling", true},
+		{"https://example.com/api/search?q=go", false},
+		{"https://cdn.example.com/app.js", false},
+	}
+	for _, tt := range tests {
+		if got := ignoredRequest(tt.url); got != tt.want {
+			t.Errorf("ignoredRequest(%s) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestIdleWaiterIgnoresLongLivedConnections(t *testing.T) {
+	// A chat page: a websocket, a long poll that is always open and a beacon sent every poll
+	neverIdle := func() (networkActivity, error) {
+		return networkActivity{
+			Open: []networkRequest{
+				{URL: "wss://chat.example.com/live", AgeMS: 100},
+				{URL: "https://example.com/api/updates?wait=30", AgeMS: 15000},
+			},
+			Finished: []networkRequest{{URL: "https://mc.yandex.ru/watch/123", AgeMS: 10}},
+		}, nil
+	}
+	waiter := idleWaiter{activity: neverIdle, quiet: networkQuietPeriod, timeout: 10 * time.Second, poll: 10 * time.Millisecond}
+
+	started := time.Now()
+	idle, err := waiter.wait(context.Background())
+	if err != nil || !idle {
+		t.Fatalf("wait = %v, %v, want idle", idle, err)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("wait took %s on a page with only long-lived connections", elapsed)
+	}
+}
+
+func TestIdleWaiterGivesUpOnBusyNetwork(t *testing.T) {
+	polls := 0
+	busy := func() (networkActivity, error) {
+		polls++
+		return networkActivity{Open: []networkRequest{{URL: "https://example.com/api/feed", AgeMS: 50}}}, nil
+	}
+	waiter := idleWaiter{activity: busy, quiet: networkQuietPeriod, timeout: 100 * time.Millisecond, poll: 10 * time.Millisecond}
+
+	started := time.Now()
+	idle, err := waiter.wait(context.Background())
+	if err != nil || idle {
+		t.Fatalf("wait = %v, %v, want not idle without an error", idle, err)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("wait took %s, want about the 100ms timeout", elapsed)
+	}
+	if polls < 2 {
+		t.Errorf("network polled %d times, want it polled until the timeout", polls)
+	}
+}
+
+func TestReadyStateReached(t *testing.T) {
+	if !readyStateReached(waitDOMContentLoaded, "interactive") || readyStateReached("load", "interactive") {
+		t.Error("interactive must satisfy domcontentloaded only")
+	}
+	if !readyStateReached(waitNetworkIdle, "complete") || readyStateReached(waitDOMContentLoaded, "loading") {
+		t.Error("complete must satisfy every strategy, loading none")
+	}
+	if pageLoadStrategy(waitDOMContentLoaded) != "eager" || pageLoadStrategy(waitNetworkIdle) != "normal" {
+		t.Error("unexpected WebDriver page load strategy")
+	}
+}
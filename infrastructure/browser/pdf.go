@@ -0,0 +1,65 @@
+package browser
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// errPDFNeedsHeadless - Chrome prints to PDF only in headless mode, a browser with a window refuses the command
+var errPDFNeedsHeadless = errors.New("saving as PDF needs headless Chrome or Edge, the browser is running with a window")
+
+// SavePDF - prints the whole current page to a PDF file at path, creating its directory.
+// Works through CDP Page.printToPDF, so only Chrome and Edge in headless mode support it
+func (s *SeleniumController) SavePDF(ctx context.Context, path string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if s.browserName == "firefox" {
+		return fmt.Errorf("saving as PDF is supported in Chrome and Edge only")
+	}
+
+	pdf, err := printToPDF(s.driverURL, s.wd.SessionID())
+	if err != nil {
+		return fmt.Errorf("failed to print page to PDF: %w", err)
+	}
+	return writePDF(path, pdf)
+}
+
+// printToPDF - asks the browser for the current page as PDF, backgrounds included
+func printToPDF(driverURL, sessionID string) ([]byte, error) {
+	var reply struct {
+		Data string `json:"data"`
+	}
+	params := map[string]interface{}{"printBackground": true, "preferCSSPageSize": true}
+	if err := callCDP(driverURL, sessionID, "Page.printToPDF", params, &reply); err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "printtopdf is not implemented") {
+			return nil, errPDFNeedsHeadless
+		}
+		return nil, err
+	}
+
+	pdf, err := base64.StdEncoding.DecodeString(reply.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode PDF data: %w", err)
+	}
+	if len(pdf) == 0 {
+		return nil, fmt.Errorf("browser returned an empty PDF")
+	}
+	return pdf, nil
+}
+
+// writePDF - saves pdf at path, creating missing directories
+func writePDF(path string, pdf []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create PDF directory: %w", err)
+	}
+	if err := os.WriteFile(path, pdf, 0644); err != nil {
+		return fmt.Errorf("failed to save PDF: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package browser
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeCDPDriver - ChromeDriver stand-in answering goog/cdp commands of session s1 with reply
+func fakeCDPDriver(t *testing.T, status int, reply interface{}) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var command struct {
+			Cmd string `json:"cmd"`
+		}
+		if r.URL.Path != "/session/s1/goog/cdp/execute" || json.NewDecoder(r.Body).Decode(&command) != nil || command.Cmd != "Page.printToPDF" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]interface{}{"value": reply})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPrintToPDFSavesFile(t *testing.T) {
+	document := []byte("%PDF-1.4\n1 0 obj << /Type /Catalog >> endobj\n%%EOF\n")
+	driver := fakeCDPDriver(t, http.StatusOK, map[string]string{"data": base64.StdEncoding.EncodeToString(document)})
+
+	pdf, err := printToPDF(driver.URL, "s1")
+	if err != nil {
+		t.Fatalf("printToPDF: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "invoices", "march.pdf")
+	if err := writePDF(path, pdf); err != nil {
+		t.Fatalf("writePDF: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("PDF was not saved: %v", err)
+	}
+	if info.Size() != int64(len(document)) {
+		t.Errorf("PDF size = %d, want %d", info.Size(), len(document))
+	}
+}
+
+func TestPrintToPDFInHeadedBrowser(t *testing.T) {
+	driver := fakeCDPDriver(t, http.StatusInternalServerError, map[string]string{
+		"error":   "unknown error",
+		"message": "unknown error: PrintToPDF is not implemented",
+	})
+
+	if _, err := printToPDF(driver.URL, "s1"); !errors.Is(err, errPDFNeedsHeadless) {
+		t.Errorf("err = %v, want errPDFNeedsHeadless", err)
+	}
+}
@@ -0,0 +1,43 @@
+package browser
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTempProfileRemovedOnClose(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	dir, err := createTempProfile()
+	if err != nil {
+		t.Fatalf("createTempProfile: %v", err)
+	}
+	if !strings.HasPrefix(dir, os.TempDir()) || !strings.HasPrefix(filepath.Base(dir), "ai_automation_profile_") {
+		t.Errorf("profile %s is not a temporary directory", dir)
+	}
+	// Chrome fills the profile while it runs
+	if err := os.WriteFile(filepath.Join(dir, "Local State"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	controller := &SeleniumController{userDataDir: dir, tempProfile: true}
+	if err := controller.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := os.Stat(dir); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("temporary profile still exists after Close: %v", err)
+	}
+}
+
+func TestIsProfileInUseError(t *testing.T) {
+	inUse := errors.New("session not created: probably user data directory is already in use, please specify a unique value for --user-data-dir")
+	if !isProfileInUseError(inUse) {
+		t.Errorf("%q not recognised as a locked profile", inUse)
+	}
+	if other := errors.New("cannot find Chrome binary"); isProfileInUseError(other) {
+		t.Errorf("%q recognised as a locked profile", other)
+	}
+}
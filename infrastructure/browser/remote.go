@@ -0,0 +1,82 @@
+package browser
+
+import (
+	"fmt"
+
+	"ai_automation/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/tebeka/selenium"
+)
+
+// driverEndpoint - WebDriver server sessions are created on: a ChromeDriver started by this
+// process, or a remote Selenium Grid / WebDriver that is left running on Close
+type driverEndpoint struct {
+	url string
+	// service and port are set only for the local ChromeDriver
+	service *selenium.Service
+	port    int
+}
+
+// stop - shuts down the local ChromeDriver, a remote endpoint is not ours to stop
+func (e *driverEndpoint) stop() {
+	if e.service == nil {
+		return
+	}
+	e.service.Stop()
+	releaseDriverOwner(e.port)
+}
+
+// localDriverStarter - starts ChromeDriver on this machine, replaceable so tests don't spawn processes
+type localDriverStarter func(cfg config.BrowserConfig, logger *logrus.Logger) (*driverEndpoint, error)
+
+// openDriver - connects to SELENIUM_REMOTE_URL when it is set, otherwise starts a local ChromeDriver
+func openDriver(cfg config.BrowserConfig, startLocal localDriverStarter, logger *logrus.Logger) (*driverEndpoint, error) {
+	if cfg.RemoteURL != "" {
+		logger.Infof("Using remote WebDriver at %s (%s)", cfg.RemoteURL, cfg.Name)
+		return &driverEndpoint{url: cfg.RemoteURL}, nil
+	}
+	if cfg.Name != "chrome" {
+		return nil, fmt.Errorf("BROWSER_NAME=%s needs SELENIUM_REMOTE_URL, only ChromeDriver is started locally", cfg.Name)
+	}
+	return startLocal(cfg, logger)
+}
+
+// startLocalChromeDriver - finds ChromeDriver and starts it on a free port
+func startLocalChromeDriver(cfg config.BrowserConfig, logger *logrus.Logger) (*driverEndpoint, error) {
+	driverPath, err := findChromeDriver(cfg.DriverPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find chromedriver: %w", err)
+	}
+
+	logger.Infof("Using ChromeDriver at: %s", driverPath)
+
+	port, err := selectDriverPort(cfg.DriverPort, systemPortChecker, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []selenium.ServiceOption{}
+	service, err := selenium.NewChromeDriverService(driverPath, port, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start chromedriver: %w", err)
+	}
+	// Lets a later run tell a ChromeDriver orphaned by a crash from one in use
+	if err := recordDriverOwner(port); err != nil {
+		logger.WithError(err).Warn("Failed to record ChromeDriver owner")
+	}
+
+	return &driverEndpoint{
+		url:     fmt.Sprintf("http://localhost:%d/wd/hub", port),
+		service: service,
+		port:    port,
+	}, nil
+}
+
+// capabilityBrowserName - W3C browserName for BROWSER_NAME
+func capabilityBrowserName(name string) string {
+	if name == "edge" {
+		return "MicrosoftEdge"
+	}
+	return name
+}
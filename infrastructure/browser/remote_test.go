@@ -0,0 +1,53 @@
+package browser
+
+import (
+	"io"
+	"testing"
+
+	"ai_automation/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestRemoteURLSkipsLocalDriver(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	started := 0
+	startLocal := func(cfg config.BrowserConfig, logger *logrus.Logger) (*driverEndpoint, error) {
+		started++
+		return &driverEndpoint{url: "http://localhost:9515/wd/hub", port: 9515}, nil
+	}
+
+	remote := config.BrowserConfig{Name: "firefox", RemoteURL: "http://grid.internal:4444/wd/hub"}
+	endpoint, err := openDriver(remote, startLocal, logger)
+	if err != nil {
+		t.Fatalf("openDriver: %v", err)
+	}
+	if started != 0 {
+		t.Error("local ChromeDriver started although SELENIUM_REMOTE_URL is set")
+	}
+	if endpoint.url != remote.RemoteURL || endpoint.service != nil {
+		t.Errorf("endpoint = %+v, want the remote hub without a service", endpoint)
+	}
+	// Nothing to stop, and Close of a controller on it must not touch a service either
+	endpoint.stop()
+	if err := (&SeleniumController{service: endpoint.service}).Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if _, err := openDriver(config.BrowserConfig{Name: "chrome"}, startLocal, logger); err != nil || started != 1 {
+		t.Errorf("local driver started %d times (err %v), want once without a remote URL", started, err)
+	}
+	if _, err := openDriver(config.BrowserConfig{Name: "edge"}, startLocal, logger); err == nil || started != 1 {
+		t.Error("edge was started locally, it needs a remote URL")
+	}
+}
+
+func TestCapabilityBrowserName(t *testing.T) {
+	for name, want := range map[string]string{"chrome": "chrome", "firefox": "firefox", "edge": "MicrosoftEdge"} {
+		if got := capabilityBrowserName(name); got != want {
+			t.Errorf("capabilityBrowserName(%s) = %s, want %s", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package browser
+
+import (
+	"fmt"
+
+	"github.com/tebeka/selenium"
+)
+
+// Kinds of text targets returned by textTargetKind
+const (
+	textTargetInput    = "input"
+	textTargetEditable = "contenteditable"
+	textTargetOther    = "other"
+)
+
+// textTargetKind - tells native form fields from contenteditable rich text editors
+func (s *SeleniumController) textTargetKind(element selenium.WebElement) (string, error) {
+	script := `
+		var el = arguments[0];
+		var tag = el.tagName.toLowerCase();
+		if (tag === 'input' || tag === 'textarea' || tag === 'select') return 'input';
+		if (el.isContentEditable) return 'contenteditable';
+		return 'other';
+	`
+	raw, err := s.wd.ExecuteScript(script, []interface{}{element})
+	if err != nil {
+		return "", err
+	}
+	kind, _ := raw.(string)
+	if kind == "" {
+		kind = textTargetOther
+	}
+	return kind, nil
+}
+
+// fieldText - current content of a form field or an editable element
+func (s *SeleniumController) fieldText(element selenium.WebElement) (string, error) {
+	script := `
+		var el = arguments[0];
+		var tag = el.tagName.toLowerCase();
+		return (tag === 'input' || tag === 'textarea') ? el.value : el.innerText;
+	`
+	raw, err := s.wd.ExecuteScript(script, []interface{}{element})
+	if err != nil {
+		return "", err
+	}
+	text, _ := raw.(string)
+	return text, nil
+}
+
+// setTextWithScript - sets text directly and fires input/change events, for editors that ignore SendKeys.
+// Form fields go through the native value setter so frameworks like React notice the change.
+// Without replace the text is added after the current content
+func (s *SeleniumController) setTextWithScript(element selenium.WebElement, text string, replace bool) error {
+	script := `
+		var el = arguments[0], text = arguments[1], replace = arguments[2];
+		el.focus();
+		var tag = el.tagName.toLowerCase();
+		if (tag === 'input' || tag === 'textarea') {
+			var proto = tag === 'input' ? HTMLInputElement.prototype : HTMLTextAreaElement.prototype;
+			var setter = Object.getOwnPropertyDescriptor(proto, 'value').set;
+			setter.call(el, replace ? text : el.value + text);
+		} else if (tag === 'select') {
+			el.value = text;
+		} else if (replace) {
+			el.innerText = text;
+		} else {
+			el.innerText = el.innerText + text;
+		}
+		el.dispatchEvent(new InputEvent('input', {bubbles: true, inputType: 'insertText', data: text}));
+		el.dispatchEvent(new Event('change', {bubbles: true}));
+		return true;
+	`
+	if _, err := s.wd.ExecuteScript(script, []interface{}{element, text, replace}); err != nil {
+		return fmt.Errorf("failed to set text with script: %w", err)
+	}
+	return nil
+}
+
+// moveCaretToEnd - focuses a form field with the caret after its value, so typed keys are appended
+func (s *SeleniumController) moveCaretToEnd(element selenium.WebElement) error {
+	script := `
+		var el = arguments[0];
+		el.focus();
+		if (typeof el.setSelectionRange === 'function') {
+			try {
+				el.setSelectionRange(el.value.length, el.value.length);
+			} catch (e) {}
+		}
+		return true;
+	`
+	_, err := s.wd.ExecuteScript(script, []interface{}{element})
+	return err
+}
@@ -0,0 +1,174 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"ai_automation/domain/interfaces"
+
+	"github.com/tebeka/selenium"
+)
+
+// selectStateScript - options of the <select> in arguments[0] as JSON, found is false for other elements
+const selectStateScript = `
+	const el = arguments[0];
+	if (el.tagName.toLowerCase() !== 'select') return JSON.stringify({found: false});
+	return JSON.stringify({
+		found: true,
+		multiple: el.multiple,
+		disabled: el.disabled,
+		options: Array.from(el.options).map(o => ({
+			text: (o.label || o.textContent || '').trim().replace(/\s+/g, ' '),
+			value: o.value,
+			selected: o.selected,
+			disabled: o.disabled
+		}))
+	});
+`
+
+// applySelectionScript - selects exactly the options at the indexes in arguments[1] with input and
+// change events, so frameworks bound to the list notice the change
+const applySelectionScript = `
+	const el = arguments[0], indexes = arguments[1];
+	el.focus();
+	Array.from(el.options).forEach((o, i) => { o.selected = indexes.includes(i); });
+	el.dispatchEvent(new Event('input', {bubbles: true}));
+	el.dispatchEvent(new Event('change', {bubbles: true}));
+	return true;
+`
+
+// selectOption - an <option> as the page shows it
+type selectOption struct {
+	Text     string `json:"text"`
+	Value    string `json:"value"`
+	Selected bool   `json:"selected"`
+	Disabled bool   `json:"disabled"`
+}
+
+// selectState - a <select> and its options
+type selectState struct {
+	Found    bool           `json:"found"`
+	Multiple bool           `json:"multiple"`
+	Disabled bool           `json:"disabled"`
+	Options  []selectOption `json:"options"`
+}
+
+// optionTexts - visible texts of the options, in list order
+func (s selectState) optionTexts() []string {
+	texts := make([]string, len(s.Options))
+	for i, option := range s.Options {
+		texts[i] = option.Text
+	}
+	return texts
+}
+
+// matchOptions - indexes of the options named by values, each matched by its exact value first and
+// then by its text like a label (same text, starting with it, containing it). Several values need
+// a multi-select; an unknown value fails with the available options so the AI can pick again
+func matchOptions(state selectState, values []string, selector string) ([]int, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("no option to select in %s", selector)
+	}
+	if len(values) > 1 && !state.Multiple {
+		return nil, fmt.Errorf("%s allows only one option, got %d", selector, len(values))
+	}
+
+	texts := state.optionTexts()
+	var indexes []int
+	for _, value := range values {
+		index := -1
+		for i, option := range state.Options {
+			if option.Value == value {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			index = matchLabel(texts, value)
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("option %q not found in %s, available: %s", value, selector, strings.Join(texts, ", "))
+		}
+		if state.Options[index].Disabled {
+			return nil, newBrowserError(interfaces.ErrElementDisabled, nil, "option %q of %s is disabled", state.Options[index].Text, selector)
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes, nil
+}
+
+// GetSelectOptions - visible texts of the options of a <select> found by selector or label text
+func (s *SeleniumController) GetSelectOptions(ctx context.Context, selector string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	element, err := s.findField(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+	state, err := s.selectState(element, selector)
+	if err != nil {
+		return nil, err
+	}
+	return state.optionTexts(), nil
+}
+
+// SelectOption - selects options of a <select> by value or visible text. A multi-select ends up
+// with exactly the given options selected, a single select takes one value
+func (s *SeleniumController) SelectOption(ctx context.Context, selector string, values []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	element, err := s.findField(ctx, selector)
+	if err != nil {
+		return err
+	}
+	state, err := s.selectState(element, selector)
+	if err != nil {
+		return err
+	}
+	if state.Disabled {
+		return newBrowserError(interfaces.ErrElementDisabled, nil, "element %s is disabled", selector)
+	}
+	indexes, err := matchOptions(state, values, selector)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Infof("Selecting %q in: %s", values, selector)
+	if _, err := s.wd.ExecuteScript(applySelectionScript, []interface{}{element, indexes}); err != nil {
+		return driverError(interfaces.ErrCommand, err, "failed to select options in %s: %v", selector, err)
+	}
+
+	// A script of the page may reset the list on change
+	if state, err = s.selectState(element, selector); err != nil {
+		return err
+	}
+	for _, index := range indexes {
+		if index >= len(state.Options) || !state.Options[index].Selected {
+			return newBrowserError(interfaces.ErrCommand, nil, "%s did not keep the selection %q", selector, values)
+		}
+	}
+	return nil
+}
+
+// selectState - reads the options of element, failing when it is not a <select>
+func (s *SeleniumController) selectState(element selenium.WebElement, selector string) (selectState, error) {
+	var state selectState
+	raw, err := s.wd.ExecuteScript(selectStateScript, []interface{}{element})
+	if err != nil {
+		return state, driverError(interfaces.ErrCommand, err, "failed to read options of %s: %v", selector, err)
+	}
+	encoded, _ := raw.(string)
+	if err := json.Unmarshal([]byte(encoded), &state); err != nil {
+		return state, newBrowserError(interfaces.ErrCommand, err, "failed to decode options of %s: %v", selector, err)
+	}
+	if !state.Found {
+		return state, fmt.Errorf("element %s is not a select list", selector)
+	}
+	return state, nil
+}
@@ -0,0 +1,45 @@
+package browser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatchOptions(t *testing.T) {
+	options := []selectOption{
+		{Text: "Red", Value: "r"},
+		{Text: "Green", Value: "g"},
+		{Text: "Blue (sold out)", Value: "b", Disabled: true},
+		{Text: "Dark green", Value: "dg"},
+	}
+	multi := selectState{Found: true, Multiple: true, Options: options}
+	single := selectState{Found: true, Options: options}
+
+	tests := []struct {
+		name    string
+		state   selectState
+		values  []string
+		want    []int
+		wantErr string
+	}{
+		{"multi-select by value and text", multi, []string{"r", "dark green"}, []int{0, 3}, ""},
+		{"exact text wins over a longer one", single, []string{"Green"}, []int{1}, ""},
+		{"single select takes one value", single, []string{"Red", "Green"}, nil, "allows only one option"},
+		{"unknown option lists the available ones", multi, []string{"Purple"}, nil, "available: Red, Green"},
+		{"disabled option", multi, []string{"Blue"}, nil, "disabled"},
+		{"nothing to select", multi, nil, nil, "no option"},
+	}
+	for _, tt := range tests {
+		got, err := matchOptions(tt.state, tt.values, "#colors")
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error = %v, want one containing %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: matchOptions = %v, %v, want %v", tt.name, got, err, tt.want)
+		}
+	}
+}
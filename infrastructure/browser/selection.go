@@ -0,0 +1,70 @@
+package browser
+
+import (
+	"context"
+
+	"ai_automation/domain/interfaces"
+)
+
+// selectTextScript - selects the text of the element: the value of a form field, the contents of any
+// other element. Returns the selected text
+const selectTextScript = `
+	const el = arguments[0];
+	el.scrollIntoView({block: 'center'});
+	if ((el.tagName === 'INPUT' || el.tagName === 'TEXTAREA') && typeof el.select === 'function') {
+		el.focus();
+		el.select();
+		return el.value;
+	}
+	const range = document.createRange();
+	range.selectNodeContents(el);
+	const selection = window.getSelection();
+	selection.removeAllRanges();
+	selection.addRange(range);
+	return selection.toString();
+`
+
+// selectedTextScript - reads the current selection. Text selected inside a form field is not part of
+// window.getSelection() in Chrome, so the focused field is checked first
+const selectedTextScript = `
+	const el = document.activeElement;
+	if (el && (el.tagName === 'INPUT' || el.tagName === 'TEXTAREA')) {
+		try {
+			if (el.selectionStart !== el.selectionEnd) {
+				return el.value.substring(el.selectionStart, el.selectionEnd);
+			}
+		} catch (e) {}
+	}
+	const selection = window.getSelection();
+	return selection ? selection.toString() : '';
+`
+
+// SelectText - selects the whole text of the element, as a user dragging over it would
+func (s *SeleniumController) SelectText(ctx context.Context, selector string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	element, err := s.findElement(ctx, selector)
+	if err != nil {
+		return err
+	}
+	if _, err := s.wd.ExecuteScript(selectTextScript, []interface{}{element}); err != nil {
+		return driverError(interfaces.ErrCommand, err, "failed to select text of %s: %v", selector, err)
+	}
+	return nil
+}
+
+// GetSelectedText - returns the text currently selected on the page, empty string when nothing is selected
+func (s *SeleniumController) GetSelectedText(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	result, err := s.wd.ExecuteScript(selectedTextScript, nil)
+	if err != nil {
+		return "", driverError(interfaces.ErrCommand, err, "failed to read selection: %v", err)
+	}
+	text, _ := result.(string)
+	return text, nil
+}
@@ -0,0 +1,56 @@
+package browser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tebeka/selenium"
+)
+
+// selectorStrategy - a single lookup strategy requested with a selector prefix
+type selectorStrategy struct {
+	by    string
+	value string
+}
+
+// selectorPrefixes - supported prefixes, e.g. "css=#login", "xpath=//form", "id=email", "text=Sign in"
+var selectorPrefixes = []string{"css=", "xpath=", "id=", "text="}
+
+// parseSelectorStrategy - returns the strategy named by the selector prefix.
+// ok is false for selectors without a prefix, those go through every strategy
+func parseSelectorStrategy(selector string) (selectorStrategy, bool) {
+	for _, prefix := range selectorPrefixes {
+		if !strings.HasPrefix(selector, prefix) {
+			continue
+		}
+		value := strings.TrimSpace(strings.TrimPrefix(selector, prefix))
+		switch prefix {
+		case "css=":
+			return selectorStrategy{by: selenium.ByCSSSelector, value: value}, true
+		case "xpath=":
+			return selectorStrategy{by: selenium.ByXPATH, value: value}, true
+		case "id=":
+			return selectorStrategy{by: selenium.ByID, value: value}, true
+		case "text=":
+			// Innermost elements whose own text contains the value
+			return selectorStrategy{
+				by:    selenium.ByXPATH,
+				value: fmt.Sprintf("//*[text()[contains(normalize-space(.), %s)]]", xpathLiteral(value)),
+			}, true
+		}
+	}
+	return selectorStrategy{}, false
+}
+
+// xpathLiteral - quotes s as an XPath string literal, using concat() when it has both quote kinds.
+// Always use it instead of wrapping text in quotes by hand: XPath has no escape sequences
+func xpathLiteral(s string) string {
+	if !strings.Contains(s, "'") {
+		return "'" + s + "'"
+	}
+	if !strings.Contains(s, `"`) {
+		return `"` + s + `"`
+	}
+	parts := strings.Split(s, "'")
+	return "concat('" + strings.Join(parts, `', "'", '`) + "')"
+}
@@ -0,0 +1,76 @@
+package browser
+
+// selectorHelpersScript - JS helpers building selectors that match exactly one element.
+// Class and tag selectors like div.item often match many nodes, so candidates are checked
+// for uniqueness and disambiguated with :nth-of-type and a short ancestor path
+const selectorHelpersScript = `
+	function isUniqueSelector(selector, el) {
+		try {
+			const matches = document.querySelectorAll(selector);
+			return matches.length === 1 && matches[0] === el;
+		} catch (e) {
+			return false;
+		}
+	}
+
+	function nthOfType(el) {
+		const parent = el.parentElement;
+		let part = el.tagName.toLowerCase();
+		if (!parent) return part;
+		const sameTag = Array.from(parent.children).filter(child => child.tagName === el.tagName);
+		if (sameTag.length > 1) {
+			part += ':nth-of-type(' + (sameTag.indexOf(el) + 1) + ')';
+		}
+		return part;
+	}
+
+	function uniqueSelector(el, candidates) {
+		candidates = (candidates || []).filter(Boolean);
+		for (const candidate of candidates) {
+			if (isUniqueSelector(candidate, el)) return candidate;
+		}
+
+		// Narrow the best candidate down by position and up to 4 ancestors
+		let tail = candidates.length > 0 ? candidates[0] : el.tagName.toLowerCase();
+		if (!tail.startsWith('#') && !tail.startsWith('[')) {
+			const position = nthOfType(el);
+			const nth = position.indexOf(':nth-of-type');
+			if (nth >= 0) tail += position.substring(nth);
+		}
+		if (isUniqueSelector(tail, el)) return tail;
+
+		let node = el.parentElement;
+		for (let depth = 0; node && node !== document.documentElement && depth < 4; depth++) {
+			const prefix = node.id ? '#' + CSS.escape(node.id) : nthOfType(node);
+			tail = prefix + ' > ' + tail;
+			if (isUniqueSelector(tail, el)) return tail;
+			if (node.id) break;
+			node = node.parentElement;
+		}
+
+		return cssPath(el);
+	}
+
+	function cssPath(el) {
+		if (el.id) return '#' + CSS.escape(el.id);
+		const parts = [];
+		let node = el;
+		while (node && node.nodeType === 1 && node !== document.documentElement) {
+			if (node.id) {
+				parts.unshift('#' + CSS.escape(node.id));
+				break;
+			}
+			let part = node.tagName.toLowerCase();
+			const parent = node.parentElement;
+			if (parent) {
+				const sameTag = Array.from(parent.children).filter(child => child.tagName === node.tagName);
+				if (sameTag.length > 1) {
+					part += ':nth-of-type(' + (sameTag.indexOf(node) + 1) + ')';
+				}
+			}
+			parts.unshift(part);
+			node = parent;
+		}
+		return parts.join(' > ');
+	}
+`
@@ -8,26 +8,54 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
+	"ai_automation/config"
 	"ai_automation/domain/entities"
 	"ai_automation/domain/interfaces"
 
 	"github.com/sirupsen/logrus"
 	"github.com/tebeka/selenium"
 	"github.com/tebeka/selenium/chrome"
+	"github.com/tebeka/selenium/log"
 )
 
+// SeleniumController - BrowserController over ChromeDriver. Every public method checks ctx before
+// talking to the driver, so an action abandoned by its step deadline stops at the next browser call
 type SeleniumController struct {
-	wd          selenium.WebDriver
-	service     *selenium.Service
-	logger      *logrus.Logger
-	userDataDir string
+	wd           selenium.WebDriver
+	service      *selenium.Service
+	logger       *logrus.Logger
+	userDataDir  string
+	lastPageInfo *entities.PageInfo
+	limits       extractionLimits
+	downloadDir  string
+	secrets      map[string]string
+	closeOnce    sync.Once
+	// tabHistory holds handles of previously active tabs, most recent last
+	tabHistory []string
+	// driverURL is the ChromeDriver endpoint, used for commands the client library lacks
+	driverURL string
+	// driverPort is the port ChromeDriver listens on
+	driverPort int
+	// browserName is chrome, firefox or edge
+	browserName string
+	// attributes are the element attributes kept in extracted elements (EXTRACT_ATTRIBUTES)
+	attributes []string
+	// waitStrategy is domcontentloaded, load or networkidle (WAIT_STRATEGY)
+	waitStrategy string
+	// findTimeout is how long element lookups wait for the element to appear (FIND_ELEMENT_TIMEOUT_MS)
+	findTimeout time.Duration
+	// tempProfile marks userDataDir as a throwaway profile removed on Close
+	tempProfile bool
+	// console buffers console messages of the current page
+	console consoleBuffer
 }
 
-// findChromeDriver - finds ChromeDriver executable path
-func findChromeDriver() (string, error) {
-	if path := os.Getenv("BROWSER_DRIVER_PATH"); path != "" {
+// findChromeDriver - finds ChromeDriver executable path, configured is BROWSER_DRIVER_PATH
+func findChromeDriver(configured string) (string, error) {
+	if path := configured; path != "" {
 		if _, err := os.Stat(path); err == nil {
 			return path, nil
 		}
@@ -53,9 +81,9 @@ func findChromeDriver() (string, error) {
 	return "", fmt.Errorf("chromedriver not found. Please install it or set BROWSER_DRIVER_PATH environment variable")
 }
 
-// findChromeBinary - finds Chrome/Chromium browser executable path
-func findChromeBinary() string {
-	if path := os.Getenv("CHROME_BINARY_PATH"); path != "" {
+// findChromeBinary - finds Chrome/Chromium browser executable path, configured is CHROME_BINARY_PATH
+func findChromeBinary(configured string) string {
+	if path := configured; path != "" {
 		if _, err := os.Stat(path); err == nil {
 			return path
 		}
@@ -105,18 +133,72 @@ func getOrCreateUserDataDir() (string, error) {
 	return userDataDir, nil
 }
 
-// NewSeleniumController - creates new Selenium browser controller instance
-func NewSeleniumController(logger *logrus.Logger) (*SeleniumController, error) {
-	driverPath, err := findChromeDriver()
+// createTempProfile - creates an empty profile directory for a browser that keeps nothing between runs
+func createTempProfile() (string, error) {
+	userDataDir, err := os.MkdirTemp("", "ai_automation_profile_")
 	if err != nil {
-		return nil, fmt.Errorf("failed to find chromedriver: %w", err)
+		return "", fmt.Errorf("failed to create temporary profile: %w", err)
 	}
+	return userDataDir, nil
+}
 
-	logger.Infof("Using ChromeDriver at: %s", driverPath)
+// profileInUseMarkers - Chrome startup errors caused by another browser holding the profile lock
+var profileInUseMarkers = []string{
+	"user data directory is already in use",
+	"profile appears to be in use",
+	"singletonlock",
+	"devtoolsactiveport file doesn't exist",
+}
 
-	chromeBinary := findChromeBinary()
-	if chromeBinary != "" {
-		logger.Infof("Using Chrome binary at: %s", chromeBinary)
+// isProfileInUseError - detects a failed start because the profile is locked by another Chrome
+func isProfileInUseError(err error) bool {
+	message := strings.ToLower(err.Error())
+	for _, marker := range profileInUseMarkers {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildChromeArgs - builds Chrome command line arguments
+func buildChromeArgs(userDataDir string, proxy *proxyConfig, userAgent string, headless bool) []string {
+	args := []string{
+		"--disable-dev-shm-usage",
+		"--no-sandbox",
+	}
+
+	if headless {
+		args = append(args, "--headless=new", "--window-size=1920,1080")
+	}
+
+	// A remote browser runs on its own profile
+	if userDataDir != "" {
+		args = append(args, fmt.Sprintf("--user-data-dir=%s", userDataDir))
+	}
+
+	if proxy != nil {
+		args = append(args, proxy.ServerArg())
+	}
+
+	if userAgent != "" {
+		args = append(args, fmt.Sprintf("--user-agent=%s", userAgent))
+	}
+
+	return args
+}
+
+// NewSeleniumController - creates new Selenium browser controller instance on the persistent profile.
+// With BROWSER_PERSISTENT=false, or when another browser holds the profile, a temporary one is used.
+// A browser on SELENIUM_REMOTE_URL keeps its profile on the remote machine
+func NewSeleniumController(logger *logrus.Logger) (*SeleniumController, error) {
+	cfg := config.FromEnv().Browser
+	if cfg.RemoteURL != "" {
+		return newSeleniumController(logger, "")
+	}
+	if !cfg.Persistent {
+		logger.Info("BROWSER_PERSISTENT=false: using a temporary profile removed on exit")
+		return NewIsolatedSeleniumController(logger)
 	}
 
 	userDataDir, err := getOrCreateUserDataDir()
@@ -125,61 +207,234 @@ func NewSeleniumController(logger *logrus.Logger) (*SeleniumController, error) {
 	}
 	logger.Infof("Using user data directory: %s (sessions will be preserved)", userDataDir)
 
-	opts := []selenium.ServiceOption{}
-	service, err := selenium.NewChromeDriverService(driverPath, 9515, opts...)
+	controller, err := newSeleniumController(logger, userDataDir)
+	if err != nil && isProfileInUseError(err) {
+		logger.Warnf("Profile %s is used by another browser, falling back to a temporary profile: %v", userDataDir, err)
+		return NewIsolatedSeleniumController(logger)
+	}
+	return controller, err
+}
+
+// NewIsolatedSeleniumController - creates a browser on a fresh temporary profile that is removed on Close.
+// Chrome locks its profile, so browsers running side by side (batch tasks) each need their own
+func NewIsolatedSeleniumController(logger *logrus.Logger) (*SeleniumController, error) {
+	if config.FromEnv().Browser.RemoteURL != "" {
+		return newSeleniumController(logger, "")
+	}
+
+	userDataDir, err := createTempProfile()
+	if err != nil {
+		return nil, err
+	}
+
+	controller, err := newSeleniumController(logger, userDataDir)
+	if err != nil {
+		os.RemoveAll(userDataDir)
+		return nil, err
+	}
+	controller.tempProfile = true
+	return controller, nil
+}
+
+// newSeleniumController - starts ChromeDriver (or connects to SELENIUM_REMOTE_URL) and the browser
+// on the given profile directory, an empty one leaves the profile to the browser
+func newSeleniumController(logger *logrus.Logger, userDataDir string) (*SeleniumController, error) {
+	cfg := config.FromEnv().Browser
+
+	// The Chrome binary is only looked up on this machine
+	chromeBinary := ""
+	if cfg.RemoteURL == "" {
+		chromeBinary = findChromeBinary(cfg.ChromeBinary)
+	}
+	if chromeBinary != "" {
+		logger.Infof("Using Chrome binary at: %s", chromeBinary)
+	}
+
+	proxy, err := parseProxy(cfg.Proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	downloadDir, err := getOrCreateDownloadDir(cfg.DownloadDir)
+	if err != nil {
+		return nil, err
+	}
+
+	userAgent := cfg.UserAgent
+	stealth := cfg.Stealth
+
+	httpAuth, err := parseHTTPCredentials(cfg.HTTPCredentials, cfg.HTTPCredentialsHosts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to start chromedriver: %w", err)
+		return nil, err
+	}
+
+	endpoint, err := openDriver(cfg, startLocalChromeDriver, logger)
+	if err != nil {
+		return nil, err
 	}
 
 	caps := selenium.Capabilities{
-		"browserName": "chrome",
+		"browserName": capabilityBrowserName(cfg.Name),
+		// Dialogs are left open so the agent can answer them by policy
+		"unhandledPromptBehavior": "ignore",
+		// Console messages and uncaught errors are read back with GetConsoleLogs
+		log.CapabilitiesKey: log.Capabilities{log.Browser: log.All},
+		// Navigation returns at DOMContentLoaded or the load event, network idle is waited for separately
+		"pageLoadStrategy": pageLoadStrategy(cfg.WaitStrategy),
 	}
 
 	chromeCaps := chrome.Capabilities{
-		Args: []string{
-			"--disable-blink-features=AutomationControlled",
-			"--disable-dev-shm-usage",
-			"--no-sandbox",
-			fmt.Sprintf("--user-data-dir=%s", userDataDir),
-		},
+		Args:  buildChromeArgs(userDataDir, proxy, userAgent, cfg.Headless),
+		Prefs: downloadPrefs(downloadDir),
 	}
 
 	if chromeBinary != "" {
 		chromeCaps.Path = chromeBinary
 	}
 
-	caps.AddChrome(chromeCaps)
+	if stealth {
+		// Hides the "controlled by automated software" bar and the switch sites look for
+		chromeCaps.ExcludeSwitches = []string{"enable-automation"}
+		// Keeps navigator.webdriver from being set by Blink
+		chromeCaps.Args = append(chromeCaps.Args, "--disable-blink-features=AutomationControlled")
+	}
+	if userAgent != "" {
+		logger.Infof("Using user agent: %s", userAgent)
+	}
+
+	if proxy != nil {
+		logger.Infof("Using proxy: %s://%s", proxy.Scheme, proxy.Host)
+	}
+	if httpAuth != nil {
+		logger.Infof("Using HTTP credentials for user %s", httpAuth.Username)
+	}
+	if (proxy != nil && proxy.HasAuth()) || httpAuth != nil {
+		extensionPath, err := writeAuthExtension(proxy, httpAuth)
+		if err != nil {
+			endpoint.stop()
+			return nil, err
+		}
+		defer os.Remove(extensionPath)
+		if err := chromeCaps.AddExtension(extensionPath); err != nil {
+			endpoint.stop()
+			return nil, fmt.Errorf("failed to add auth extension: %w", err)
+		}
+	}
+
+	// Edge takes the same options as Chrome under its own key, Firefox gets none of them
+	if cfg.Name == "firefox" && (proxy != nil || httpAuth != nil || userAgent != "" || stealth) {
+		logger.Warn("Proxy, HTTP credentials, user agent and stealth settings apply to Chrome and Edge only")
+	}
+	switch cfg.Name {
+	case "chrome":
+		caps.AddChrome(chromeCaps)
+	case "edge":
+		caps["ms:edgeOptions"] = chromeCaps
+	}
 
-	wd, err := selenium.NewRemote(caps, fmt.Sprintf("http://localhost:%d/wd/hub", 9515))
+	wd, err := selenium.NewRemote(caps, endpoint.url)
 	if err != nil {
-		service.Stop()
+		endpoint.stop()
 		if strings.Contains(err.Error(), "cannot find Chrome binary") {
 			return nil, fmt.Errorf("failed to create webdriver: Chrome browser not found. Please install Google Chrome or set CHROME_BINARY_PATH environment variable. Error: %w", err)
 		}
 		return nil, fmt.Errorf("failed to create webdriver: %w", err)
 	}
 
-	return &SeleniumController{
-		wd:          wd,
-		service:     service,
-		logger:      logger,
-		userDataDir: userDataDir,
-	}, nil
+	controller := &SeleniumController{
+		wd:           wd,
+		service:      endpoint.service,
+		logger:       logger,
+		userDataDir:  userDataDir,
+		limits:       extractionLimits{Elements: cfg.MaxElements, Links: cfg.MaxLinks, Buttons: cfg.MaxButtons, Text: cfg.MaxTextContent},
+		downloadDir:  downloadDir,
+		secrets:      loadSecrets(),
+		driverURL:    endpoint.url,
+		driverPort:   endpoint.port,
+		browserName:  cfg.Name,
+		attributes:   cfg.ExtractAttributes,
+		waitStrategy: cfg.WaitStrategy,
+		findTimeout:  cfg.FindElementTimeout,
+	}
+
+	// Nothing is injected into pages unless stealth mode is on
+	if stealth {
+		if err := controller.installStealth(); err != nil {
+			logger.Warnf("Stealth mode is only partially applied: %v", err)
+		}
+	}
+
+	return controller, nil
 }
 
-// Navigate - navigates browser to specified URL
+// Navigate - navigates browser to specified URL, an HTTP error status or a browser error page
+// (connection refused, unknown host) is returned as "navigation failed: ..." error
 func (s *SeleniumController) Navigate(ctx context.Context, url string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	s.logger.Infof("Navigating to: %s", url)
-	return s.wd.Get(url)
+	s.console.reset(time.Now())
+	if err := s.wd.Get(url); err != nil {
+		return driverError(interfaces.ErrNavigation, err, "navigation failed: %v", err)
+	}
+	// The page loaded, but it may be an error page the AI should know about
+	if err := s.checkNavigation(); err != nil {
+		s.logger.Warnf("%v: %s", err, url)
+		return err
+	}
+	return s.waitForNetworkIdle(ctx)
 }
 
 // Click - clicks on element identified by selector
 func (s *SeleniumController) Click(ctx context.Context, selector string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	s.logger.Infof("Clicking on: %s", selector)
 
-	element, err := s.findElement(selector)
+	element, err := s.findElement(ctx, selector)
 	if err != nil {
-		return fmt.Errorf("element not found: %w", err)
+		return err
+	}
+	return s.clickElement(ctx, element, selector)
+}
+
+// ClickClosest - clicks on element identified by selector. A selector from the AI may be slightly off,
+// so when it matches nothing the most similar extracted element is clicked instead.
+// Returns the selector that was actually clicked
+func (s *SeleniumController) ClickClosest(ctx context.Context, selector string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	s.logger.Infof("Clicking on: %s", selector)
+
+	element, err := s.findElement(ctx, selector)
+	if err != nil {
+		fallbackSelector, score := findClosestSelector(s.lastPageInfo, selector)
+		if fallbackSelector == "" {
+			return "", err
+		}
+		fallbackElement, fallbackErr := s.findElement(ctx, fallbackSelector)
+		if fallbackErr != nil {
+			return "", err
+		}
+		s.logger.Warnf("Selector %q not found, clicking closest match %q (score %.2f)", selector, fallbackSelector, score)
+		element = fallbackElement
+		selector = fallbackSelector
+	}
+
+	return selector, s.clickElement(ctx, element, selector)
+}
+
+// clickElement - scrolls element into view and clicks it, re-resolving selector when the element goes stale
+func (s *SeleniumController) clickElement(ctx context.Context, element selenium.WebElement, selector string) error {
+	// A disabled control ignores the click, say so instead of reporting success
+	if enabled, err := element.IsEnabled(); err == nil && !enabled {
+		return newBrowserError(interfaces.ErrElementDisabled, nil, "element %s is disabled", selector)
 	}
 
 	// Scroll element into view using JavaScript for better reliability
@@ -190,7 +445,7 @@ func (s *SeleniumController) Click(ctx context.Context, selector string) error {
 		return true;
 	})();
 	`
-	_, err = s.wd.ExecuteScript(script, []interface{}{element})
+	_, err := s.wd.ExecuteScript(script, []interface{}{element})
 	if err != nil {
 		s.logger.Warnf("Failed to scroll to element: %v", err)
 		// Try alternative method
@@ -199,35 +454,319 @@ func (s *SeleniumController) Click(ctx context.Context, selector string) error {
 		}
 	}
 
-	time.Sleep(300 * time.Millisecond)
-	return element.Click()
+	if err := sleepWithContext(ctx, 300*time.Millisecond); err != nil {
+		return err
+	}
+
+	handlesBefore, err := s.wd.WindowHandles()
+	if err != nil {
+		handlesBefore = nil
+	}
+
+	err = element.Click()
+	for attempt := 1; err != nil && isStaleElementError(err) && attempt <= maxStaleRetries; attempt++ {
+		s.logger.Warnf("Element %s went stale, re-resolving (attempt %d/%d)", selector, attempt, maxStaleRetries)
+		element, err = s.findElement(ctx, selector)
+		if err != nil {
+			return err
+		}
+		err = element.Click()
+	}
+	if err != nil {
+		return driverError(interfaces.ErrCommand, err, "failed to click %s: %v", selector, err)
+	}
+
+	s.followNewTab(ctx, handlesBefore)
+	return nil
+}
+
+// RightClick - right-clicks element identified by selector.
+// Native OS context menus are not accessible to WebDriver, so this is only useful
+// for pages that render their own menu on the contextmenu event
+func (s *SeleniumController) RightClick(ctx context.Context, selector string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.logger.Infof("Right-clicking on: %s", selector)
+
+	element, err := s.findElement(ctx, selector)
+	if err != nil {
+		return err
+	}
+
+	if err := element.MoveTo(0, 0); err == nil {
+		if err := s.wd.Click(selenium.RightButton); err == nil {
+			return nil
+		}
+	}
+
+	// W3C drivers may not support legacy mouse endpoints - dispatch the event directly
+	script := `
+	(function(element) {
+		element.scrollIntoView({ block: 'center' });
+		var rect = element.getBoundingClientRect();
+		var event = new MouseEvent('contextmenu', {
+			bubbles: true,
+			cancelable: true,
+			view: window,
+			button: 2,
+			buttons: 2,
+			clientX: rect.left + rect.width / 2,
+			clientY: rect.top + rect.height / 2
+		});
+		element.dispatchEvent(event);
+		return true;
+	})(arguments[0]);
+	`
+	if _, err := s.wd.ExecuteScript(script, []interface{}{element}); err != nil {
+		return driverError(interfaces.ErrCommand, err, "failed to right-click element: %v", err)
+	}
+
+	return nil
+}
+
+// ExecuteScript - runs JavaScript on the page, script must use return to pass a value back
+func (s *SeleniumController) ExecuteScript(ctx context.Context, script string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	s.logger.Infof("Executing custom script (%d chars)", len(script))
+
+	result, err := s.wd.ExecuteScript(script, nil)
+	if err != nil {
+		return "", driverError(interfaces.ErrCommand, err, "script failed: %v", err)
+	}
+
+	switch value := result.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return value, nil
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprint(value), nil
+		}
+		return string(data), nil
+	}
+}
+
+// ClickAt - clicks at viewport coordinates
+func (s *SeleniumController) ClickAt(ctx context.Context, x, y int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.logger.Infof("Clicking at: (%d, %d)", x, y)
+
+	// Legacy mouse offsets are relative to the element, so move relative to <html> corrected by scroll
+	if root, err := s.wd.FindElement(selenium.ByTagName, "html"); err == nil {
+		scroll, err := s.wd.ExecuteScript("return [window.scrollX, window.scrollY];", nil)
+		if offsets, ok := scroll.([]interface{}); err == nil && ok && len(offsets) == 2 {
+			scrollX, _ := offsets[0].(float64)
+			scrollY, _ := offsets[1].(float64)
+			if err := root.MoveTo(x+int(scrollX), y+int(scrollY)); err == nil {
+				if err := s.wd.Click(selenium.LeftButton); err == nil {
+					return nil
+				}
+			}
+		}
+	}
+
+	// W3C drivers may not support legacy mouse endpoints - dispatch the events directly
+	script := `
+	(function(x, y) {
+		var target = document.elementFromPoint(x, y);
+		if (!target) {
+			return false;
+		}
+		['mousedown', 'mouseup', 'click'].forEach(function(type) {
+			target.dispatchEvent(new MouseEvent(type, {
+				bubbles: true,
+				cancelable: true,
+				view: window,
+				button: 0,
+				clientX: x,
+				clientY: y
+			}));
+		});
+		return true;
+	})(arguments[0], arguments[1]);
+	`
+	clicked, err := s.wd.ExecuteScript(script, []interface{}{x, y})
+	if err != nil {
+		return driverError(interfaces.ErrCommand, err, "failed to click at (%d, %d): %v", x, y, err)
+	}
+	if found, ok := clicked.(bool); !ok || !found {
+		return newBrowserError(interfaces.ErrElementNotFound, nil, "no element at (%d, %d)", x, y)
+	}
+
+	return nil
 }
 
-// TypeText - types text into input field identified by selector
+// GetBoundingBox - returns element position and size in the viewport
+func (s *SeleniumController) GetBoundingBox(ctx context.Context, selector string) (*entities.BoundingBox, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	element, err := s.findElement(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	script := `
+	var rect = arguments[0].getBoundingClientRect();
+	return JSON.stringify({ x: rect.left, y: rect.top, width: rect.width, height: rect.height });
+	`
+	raw, err := s.wd.ExecuteScript(script, []interface{}{element})
+	if err != nil {
+		return nil, driverError(interfaces.ErrCommand, err, "failed to get bounding box: %v", err)
+	}
+	data, ok := raw.(string)
+	if !ok {
+		return nil, newBrowserError(interfaces.ErrCommand, nil, "unexpected bounding box result: %v", raw)
+	}
+
+	var box entities.BoundingBox
+	if err := json.Unmarshal([]byte(data), &box); err != nil {
+		return nil, newBrowserError(interfaces.ErrCommand, err, "failed to parse bounding box: %v", err)
+	}
+	return &box, nil
+}
+
+// TypeText - types text into input field identified by selector, replacing its content
 func (s *SeleniumController) TypeText(ctx context.Context, selector string, text string) error {
+	return s.typeText(ctx, selector, text, true)
+}
+
+// AppendText - types text into input field identified by selector after its current content
+func (s *SeleniumController) AppendText(ctx context.Context, selector string, text string) error {
+	return s.typeText(ctx, selector, text, false)
+}
+
+// typeText - types text into the field, clearing it first when clear is set
+func (s *SeleniumController) typeText(ctx context.Context, selector string, text string, clear bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	s.logger.Infof("Typing text into: %s", selector)
 
-	element, err := s.findElement(selector)
+	text, err := resolveSecrets(text, s.secrets)
 	if err != nil {
-		return fmt.Errorf("element not found: %w", err)
+		return err
+	}
+
+	// The AI may name the field by its label ("Email") instead of a selector
+	element, err := s.findField(ctx, selector)
+	if err != nil {
+		return err
+	}
+
+	// A retry can't tell how much of an append was typed before the field went stale,
+	// so it sets the original content plus the text instead of typing again
+	original, appendOver := "", false
+	if !clear {
+		var readErr error
+		original, readErr = s.fieldText(element)
+		appendOver = readErr == nil
 	}
 
-	if err := element.Clear(); err != nil {
-		s.logger.Warnf("Failed to clear element: %v", err)
+	err = s.typeIntoElement(ctx, element, text, clear)
+	for attempt := 1; err != nil && isStaleElementError(err) && attempt <= maxStaleRetries; attempt++ {
+		// DOM re-rendered while typing - find the field again and retype from scratch
+		s.logger.Warnf("Element %s went stale, re-resolving (attempt %d/%d)", selector, attempt, maxStaleRetries)
+		element, err = s.findField(ctx, selector)
+		if err != nil {
+			return err
+		}
+		if appendOver {
+			err = s.setTextWithScript(element, original+text, true)
+			continue
+		}
+		err = s.typeIntoElement(ctx, element, text, clear)
+	}
+
+	return err
+}
+
+// typeIntoElement - clears element (when clear is set) and types text character by character.
+// Rich text editors get the text set by script, as do fields that reject key events
+func (s *SeleniumController) typeIntoElement(ctx context.Context, element selenium.WebElement, text string, clear bool) error {
+	kind, err := s.textTargetKind(element)
+	if err != nil {
+		if isStaleElementError(err) {
+			return err
+		}
+		s.logger.Warnf("Failed to detect element type, typing as into an input: %v", err)
+		kind = textTargetInput
+	}
+	if kind != textTargetInput {
+		s.logger.Debugf("Element is %s, setting text with script", kind)
+		return s.setTextWithScript(element, text, clear)
 	}
 
-	for _, char := range text {
+	if clear {
+		if err := element.Clear(); err != nil {
+			if isStaleElementError(err) {
+				return err
+			}
+			s.logger.Warnf("Failed to clear element: %v", err)
+		}
+	} else if err := s.moveCaretToEnd(element); err != nil {
+		if isStaleElementError(err) {
+			return err
+		}
+		s.logger.Warnf("Failed to move caret to the end: %v", err)
+	}
+
+	for i, char := range text {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if err := element.SendKeys(string(char)); err != nil {
-			return fmt.Errorf("failed to type character: %w", err)
+			if isStaleElementError(err) {
+				return err
+			}
+			s.logger.Warnf("Typing failed, setting text with script: %v", err)
+			if clear {
+				return s.setTextWithScript(element, text, true)
+			}
+			// Characters typed so far stay in the field
+			return s.setTextWithScript(element, text[i:], false)
+		}
+		if err := sleepWithContext(ctx, 50*time.Millisecond); err != nil {
+			return err
 		}
-		time.Sleep(50 * time.Millisecond)
 	}
 
 	return nil
 }
 
-// ExtractPageInfo - extracts structured information from current page
-func (s *SeleniumController) ExtractPageInfo(ctx context.Context) (*entities.PageInfo, error) {
+// maxStaleRetries - how many times an action is retried after a stale element reference
+const maxStaleRetries = 2
+
+// isStaleElementError - detects stale element reference errors returned by WebDriver
+func isStaleElementError(err error) bool {
+	return err != nil && strings.Contains(strings.ToLower(err.Error()), "stale element")
+}
+
+// ExtractPageInfo - extracts page info; page selects which slice of elements, links and buttons
+// is returned when the page has more of them than the configured limits (0 - first page)
+func (s *SeleniumController) ExtractPageInfo(ctx context.Context, page int) (*entities.PageInfo, error) {
+	return s.ExtractPageInfoWithin(ctx, "", page)
+}
+
+// ExtractPageInfoWithin - extracts page info limited to the container matched by rootSelector.
+// An empty or no longer matching root extracts the whole page; PageInfo.Scope reports the scope used
+func (s *SeleniumController) ExtractPageInfoWithin(ctx context.Context, rootSelector string, page int) (*entities.PageInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	s.logger.Debug("Extracting page info")
 
 	url, err := s.GetCurrentURL(ctx)
@@ -240,59 +779,232 @@ func (s *SeleniumController) ExtractPageInfo(ctx context.Context) (*entities.Pag
 		return nil, err
 	}
 
-	elements, err := s.extractElements(ctx)
+	if page < 0 {
+		page = 0
+	}
+
+	var root selenium.WebElement
+	if rootSelector != "" {
+		root, err = s.findElement(ctx, rootSelector)
+		if err != nil {
+			s.logger.Warnf("Extraction scope %s not found, extracting the whole page", rootSelector)
+			root = nil
+			rootSelector = ""
+		}
+	}
+
+	elements, moreElements, err := s.extractElements(ctx, page, root)
 	if err != nil {
 		s.logger.Warnf("Failed to extract elements: %v", err)
 		elements = []entities.PageElement{}
 	}
 
-	links, err := s.extractLinks(ctx)
+	links, moreLinks, err := s.extractLinks(ctx, page, root)
 	if err != nil {
 		s.logger.Warnf("Failed to extract links: %v", err)
 		links = []entities.LinkInfo{}
 	}
 
-	forms, err := s.extractForms(ctx)
+	forms, err := s.extractForms(ctx, root)
 	if err != nil {
 		s.logger.Warnf("Failed to extract forms: %v", err)
 		forms = []entities.FormInfo{}
 	}
 
-	buttons, err := s.extractButtons(ctx)
+	buttons, moreButtons, err := s.extractButtons(ctx, page, root)
 	if err != nil {
 		s.logger.Warnf("Failed to extract buttons: %v", err)
 		buttons = []entities.PageElement{}
 	}
 
-	textContent, err := s.getVisibleText(ctx)
+	textContent, err := s.getVisibleText(ctx, root)
 	if err != nil {
 		textContent = ""
 	}
 
-	return &entities.PageInfo{
+	frames, err := s.extractFrames(ctx)
+	if err != nil {
+		s.logger.Warnf("Failed to extract frames: %v", err)
+	}
+
+	pageInfo := &entities.PageInfo{
 		URL:         url,
 		Title:       title,
-		Description: s.generateDescription(elements, links, forms),
 		Elements:    elements,
 		TextContent: textContent,
 		Links:       links,
 		Forms:       forms,
 		Buttons:     buttons,
-	}, nil
+		Page:        page,
+		HasMore:     moreElements || moreLinks || moreButtons,
+		Frames:      frames,
+		Scope:       rootSelector,
+	}
+	// A link styled as a button is found by all three scripts, the prompt should list it once
+	pageInfo.RemoveDuplicates()
+	pageInfo.Description = s.generateDescription(pageInfo.Elements, pageInfo.Links, pageInfo.Forms)
+	s.lastPageInfo = pageInfo
+
+	return pageInfo, nil
 }
 
 // Wait - waits for specified timeout
 func (s *SeleniumController) Wait(ctx context.Context, condition string, timeout int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	if timeout == 0 {
 		timeout = 5
 	}
 
-	time.Sleep(time.Duration(timeout) * time.Second)
-	return nil
+	return sleepWithContext(ctx, time.Duration(timeout)*time.Second)
+}
+
+// urlPollInterval - how often URL and load state are checked while waiting for navigation
+const urlPollInterval = 200 * time.Millisecond
+
+// WaitForURLChange - polls current URL until it differs from previousURL and stays the same
+// for two consecutive polls, so redirect chains are followed to the end
+func (s *SeleniumController) WaitForURLChange(ctx context.Context, previousURL string, timeout time.Duration) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	deadline := time.Now().Add(timeout)
+	lastURL := ""
+
+	for {
+		current, err := s.wd.CurrentURL()
+		if err == nil && current != previousURL {
+			if current == lastURL {
+				return current, nil
+			}
+			lastURL = current
+		}
+
+		if time.Now().After(deadline) {
+			if lastURL != "" {
+				return lastURL, nil
+			}
+			return "", newBrowserError(interfaces.ErrTimeout, nil, "URL did not change from %s within %s", previousURL, timeout)
+		}
+		if err := sleepWithContext(ctx, urlPollInterval); err != nil {
+			return "", err
+		}
+	}
+}
+
+// WaitForNavigation - waits until document.readyState reaches the WAIT_STRATEGY state and the URL
+// has settled, with networkidle also until the page stops loading data
+func (s *SeleniumController) WaitForNavigation(ctx context.Context, timeout time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+	lastURL := ""
+
+	for {
+		state, err := s.wd.ExecuteScript("return document.readyState;", nil)
+		current, urlErr := s.wd.CurrentURL()
+		if readyState, _ := state.(string); err == nil && urlErr == nil && readyStateReached(s.waitStrategy, readyState) && current == lastURL {
+			return s.waitForNetworkIdle(ctx)
+		}
+		lastURL = current
+
+		if time.Now().After(deadline) {
+			return driverError(interfaces.ErrTimeout, err, "page did not finish loading within %s", timeout)
+		}
+		if err := sleepWithContext(ctx, urlPollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// WaitForTextContains - waits until element text (or value for inputs) contains text.
+// Matching is case-insensitive; a missing element is treated as not yet rendered
+func (s *SeleniumController) WaitForTextContains(ctx context.Context, selector string, text string, timeout time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+	want := strings.ToLower(text)
+
+	for {
+		if current, err := s.GetElementText(ctx, selector); err == nil && strings.Contains(strings.ToLower(current), want) {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return newBrowserError(interfaces.ErrTimeout, nil, "text %q did not appear in %s within %s", text, selector, timeout)
+		}
+		if err := sleepWithContext(ctx, urlPollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// stableChecks - number of consecutive identical bounding boxes after which an element is stable
+const stableChecks = 3
+
+// WaitForElementStable - waits until element exists and its bounding box stops changing,
+// e.g. when an animation or lazy rendering has finished
+func (s *SeleniumController) WaitForElementStable(ctx context.Context, selector string, timeout time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+	var last *entities.BoundingBox
+	same := 0
+
+	for {
+		box, err := s.GetBoundingBox(ctx, selector)
+		if err == nil && box.Width > 0 && box.Height > 0 {
+			if last != nil && *box == *last {
+				same++
+				if same >= stableChecks-1 {
+					return nil
+				}
+			} else {
+				same = 0
+			}
+			last = box
+		} else {
+			last = nil
+			same = 0
+		}
+
+		if time.Now().After(deadline) {
+			if last == nil {
+				return newBrowserError(interfaces.ErrTimeout, nil, "element %s did not appear within %s", selector, timeout)
+			}
+			return newBrowserError(interfaces.ErrTimeout, nil, "element %s kept moving for %s", selector, timeout)
+		}
+		if err := sleepWithContext(ctx, urlPollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// sleepWithContext - pauses for the given duration, returning early with ctx error on cancellation
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
 }
 
 // Scroll - scrolls page in specified direction
 func (s *SeleniumController) Scroll(ctx context.Context, direction string, amount int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	if amount == 0 {
 		amount = 500
 	}
@@ -310,33 +1022,62 @@ func (s *SeleniumController) Scroll(ctx context.Context, direction string, amoun
 
 // GetCurrentURL - returns current page URL
 func (s *SeleniumController) GetCurrentURL(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
 	return s.wd.CurrentURL()
 }
 
 // GetPageTitle - returns current page title
 func (s *SeleniumController) GetPageTitle(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
 	return s.wd.Title()
 }
 
 // TakeScreenshot - takes screenshot of current page
 func (s *SeleniumController) TakeScreenshot(ctx context.Context) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	return s.wd.Screenshot()
 }
 
-// Close - closes browser and stops ChromeDriver service
+// Close - closes browser and stops ChromeDriver service. Safe to call more than once;
+// the service is stopped even if quitting the browser panics
 func (s *SeleniumController) Close() error {
-	if s.wd != nil {
-		s.wd.Quit()
-	}
-	if s.service != nil {
-		s.service.Stop()
-	}
+	s.closeOnce.Do(func() {
+		if s.tempProfile {
+			defer os.RemoveAll(s.userDataDir)
+		}
+		if s.service != nil {
+			defer releaseDriverOwner(s.driverPort)
+			defer s.service.Stop()
+		}
+		if s.wd != nil {
+			if config.FromEnv().Browser.RestoreLastURL && !s.tempProfile {
+				if err := s.saveSession(); err != nil {
+					s.logger.WithError(err).Warn("Failed to save last session")
+				}
+			}
+			s.wd.Quit()
+		}
+	})
 	return nil
 }
 
 // IsElementVisible - checks if element is visible on page
 func (s *SeleniumController) IsElementVisible(ctx context.Context, selector string) (bool, error) {
-	element, err := s.findElement(selector)
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	// A check, not an action: a missing element is reported as not visible right away
+	element, err := s.findElementOnce(selector)
 	if err != nil {
 		return false, nil
 	}
@@ -344,9 +1085,32 @@ func (s *SeleniumController) IsElementVisible(ctx context.Context, selector stri
 	return element.IsDisplayed()
 }
 
+// IsElementEnabled - checks if element can be interacted with: not disabled and not aria-disabled
+func (s *SeleniumController) IsElementEnabled(ctx context.Context, selector string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	element, err := s.findElement(ctx, selector)
+	if err != nil {
+		return false, err
+	}
+
+	enabled, err := element.IsEnabled()
+	if err != nil || !enabled {
+		return false, err
+	}
+	ariaDisabled, _ := element.GetAttribute("aria-disabled")
+	return ariaDisabled != "true", nil
+}
+
 // FindElementsByText - finds elements containing specified text
 func (s *SeleniumController) FindElementsByText(ctx context.Context, text string) ([]entities.PageElement, error) {
-	xpath := fmt.Sprintf("//*[contains(text(), '%s')]", text)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	xpath := fmt.Sprintf("//*[contains(text(), %s)]", xpathLiteral(text))
 	elements, err := s.wd.FindElements(selenium.ByXPATH, xpath)
 	if err != nil {
 		return nil, err
@@ -368,8 +1132,169 @@ func (s *SeleniumController) FindElementsByText(ctx context.Context, text string
 	return result, nil
 }
 
-// findElement - finds element using various selector strategies
-func (s *SeleniumController) findElement(selector string) (selenium.WebElement, error) {
+// GetAttribute - returns attribute value of element identified by selector
+func (s *SeleniumController) GetAttribute(ctx context.Context, selector string, attr string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	element, err := s.findElement(ctx, selector)
+	if err != nil {
+		return "", err
+	}
+
+	return element.GetAttribute(attr)
+}
+
+// GetElementText - returns visible text of element identified by selector
+func (s *SeleniumController) GetElementText(ctx context.Context, selector string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	element, err := s.findElement(ctx, selector)
+	if err != nil {
+		return "", err
+	}
+
+	text, err := element.Text()
+	if err != nil {
+		return "", err
+	}
+
+	// Inputs have no inner text, fall back to their current value
+	if text == "" {
+		if value, err := element.GetAttribute("value"); err == nil {
+			text = value
+		}
+	}
+
+	return text, nil
+}
+
+// ReadClipboard - reads text from system clipboard via Clipboard API
+func (s *SeleniumController) ReadClipboard(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	script := `
+	var done = arguments[arguments.length - 1];
+	if (!navigator.clipboard || !navigator.clipboard.readText) {
+		done({ error: 'Clipboard API is not available' });
+		return;
+	}
+	navigator.clipboard.readText()
+		.then(function(text) { done({ text: text }); })
+		.catch(function(e) { done({ error: String(e) }); });
+	`
+
+	result, err := s.runClipboardScript(script, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
+
+// WriteClipboard - writes text to system clipboard via Clipboard API
+func (s *SeleniumController) WriteClipboard(ctx context.Context, text string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	script := `
+	var text = arguments[0];
+	var done = arguments[arguments.length - 1];
+	if (!navigator.clipboard || !navigator.clipboard.writeText) {
+		done({ error: 'Clipboard API is not available' });
+		return;
+	}
+	navigator.clipboard.writeText(text)
+		.then(function() { done({ text: text }); })
+		.catch(function(e) { done({ error: String(e) }); });
+	`
+
+	_, err := s.runClipboardScript(script, []interface{}{text})
+	return err
+}
+
+// runClipboardScript - runs async clipboard script and converts browser denial into error
+func (s *SeleniumController) runClipboardScript(script string, args []interface{}) (string, error) {
+	rawResult, err := s.wd.ExecuteScriptAsync(script, args)
+	if err != nil {
+		return "", driverError(interfaces.ErrCommand, err, "clipboard script failed: %v", err)
+	}
+
+	result, ok := rawResult.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("unexpected clipboard script result: %v", rawResult)
+	}
+
+	if errMsg, ok := result["error"].(string); ok && errMsg != "" {
+		return "", fmt.Errorf("clipboard access denied by browser: %s", errMsg)
+	}
+
+	text, _ := result["text"].(string)
+	return text, nil
+}
+
+// ElementExists - checks whether any element matches selector
+func (s *SeleniumController) ElementExists(ctx context.Context, selector string) (bool, error) {
+	count, err := s.CountElements(ctx, selector)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CountElements - counts elements matching CSS selector or XPath
+func (s *SeleniumController) CountElements(ctx context.Context, selector string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	elements, err := s.findElements(selector)
+	if err != nil {
+		return 0, err
+	}
+	return len(elements), nil
+}
+
+// findElements - finds all elements matching selector as XPath (when it looks like one) or CSS
+func (s *SeleniumController) findElements(selector string) ([]selenium.WebElement, error) {
+	if strategy, ok := parseSelectorStrategy(selector); ok {
+		return s.wd.FindElements(strategy.by, strategy.value)
+	}
+	if strings.HasPrefix(selector, "/") || strings.HasPrefix(selector, "(") {
+		return s.wd.FindElements(selenium.ByXPATH, selector)
+	}
+
+	elements, err := s.wd.FindElements(selenium.ByCSSSelector, selector)
+	if err != nil {
+		// Not a valid CSS selector - give XPath a chance
+		if xpathElements, xpathErr := s.wd.FindElements(selenium.ByXPATH, selector); xpathErr == nil {
+			return xpathElements, nil
+		}
+		return nil, fmt.Errorf("invalid selector %s: %w", selector, err)
+	}
+
+	return elements, nil
+}
+
+// findElementOnce - finds element using various selector strategies, or only the one
+// named by a css=/xpath=/id=/text= prefix
+func (s *SeleniumController) findElementOnce(selector string) (selenium.WebElement, error) {
+	if strategy, ok := parseSelectorStrategy(selector); ok {
+		element, err := s.wd.FindElement(strategy.by, strategy.value)
+		if err != nil {
+			return nil, driverError(interfaces.ErrElementNotFound, err, "element not found with selector: %s", selector)
+		}
+		return element, nil
+	}
+
+	// lastErr tells a missing element from a lost session
+	var lastErr error
 	strategies := []struct {
 		by    string
 		value string
@@ -382,23 +1307,26 @@ func (s *SeleniumController) findElement(selector string) (selenium.WebElement,
 	}
 
 	if !strings.Contains(selector, "/") && !strings.Contains(selector, "[") && !strings.Contains(selector, "#") && !strings.Contains(selector, ".") {
-		textXPath := fmt.Sprintf("//*[contains(text(), '%s')]", selector)
+		textXPath := fmt.Sprintf("//*[contains(text(), %s)]", xpathLiteral(selector))
 		element, err := s.wd.FindElement(selenium.ByXPATH, textXPath)
 		if err == nil {
 			return element, nil
 		}
+		lastErr = err
 
-		buttonXPath := fmt.Sprintf("//button[contains(text(), '%s')]", selector)
+		buttonXPath := fmt.Sprintf("//button[contains(text(), %s)]", xpathLiteral(selector))
 		element, err = s.wd.FindElement(selenium.ByXPATH, buttonXPath)
 		if err == nil {
 			return element, nil
 		}
+		lastErr = err
 
-		linkXPath := fmt.Sprintf("//a[contains(text(), '%s')]", selector)
+		linkXPath := fmt.Sprintf("//a[contains(text(), %s)]", xpathLiteral(selector))
 		element, err = s.wd.FindElement(selenium.ByXPATH, linkXPath)
 		if err == nil {
 			return element, nil
 		}
+		lastErr = err
 	}
 
 	for _, strategy := range strategies {
@@ -406,15 +1334,17 @@ func (s *SeleniumController) findElement(selector string) (selenium.WebElement,
 		if err == nil {
 			return element, nil
 		}
+		lastErr = err
 	}
 
-	return nil, fmt.Errorf("element not found with selector: %s", selector)
+	return nil, driverError(interfaces.ErrElementNotFound, lastErr, "element not found with selector: %s", selector)
 }
 
 // extractElements - extracts interactive elements from page using JavaScript
-func (s *SeleniumController) extractElements(ctx context.Context) ([]entities.PageElement, error) {
+func (s *SeleniumController) extractElements(ctx context.Context, page int, root selenium.WebElement) ([]entities.PageElement, bool, error) {
 	script := `
-	(function() {
+	return (function(page, limit, root) {` + accessibilityHelpersScript + selectorHelpersScript + `
+		root = root || document;
 		const elements = [];
 		const interactiveSelectors = [
 			'button', 'a', 'input', 'select', 'textarea',
@@ -431,7 +1361,7 @@ func (s *SeleniumController) extractElements(ctx context.Context) ([]entities.Pa
 		// First, collect all interactive elements (including those not in viewport)
 		interactiveSelectors.forEach(selector => {
 			try {
-				document.querySelectorAll(selector).forEach(el => {
+				root.querySelectorAll(selector).forEach(el => {
 					const style = window.getComputedStyle(el);
 					const isHidden = style.visibility === 'hidden' || style.display === 'none';
 					
@@ -481,6 +1411,7 @@ func (s *SeleniumController) extractElements(ctx context.Context) ([]entities.Pa
 						const firstClass = el.className.trim().split(/\s+/)[0];
 						if (firstClass) primarySelector += '.' + firstClass;
 					}
+					primarySelector = uniqueSelector(el, [primarySelector].concat(selectors));
 					
 					const text = el.textContent ? el.textContent.trim().substring(0, 200) : '';
 					const placeholder = el.placeholder || '';
@@ -500,55 +1431,52 @@ func (s *SeleniumController) extractElements(ctx context.Context) ([]entities.Pa
 						attributes: attrs,
 						selector: primarySelector,
 						all_selectors: selectors,
+						role: ariaRole(el),
+						accessible_name: accessibleName(el),
 						is_visible: isVisible,
-						is_clickable: true
+						is_clickable: true,
+						is_disabled: isDisabled(el)
 					});
 				});
 			} catch(e) {}
 		});
 		
-		// Remove duplicates and increase limit to get all elements
+		// Remove duplicates, the page of results is cut out below
 		const seen = new Set();
 		const unique = [];
 		interactiveElements.forEach(el => {
 			const key = el.selector + '|' + el.text.substring(0, 50);
-			if (!seen.has(key) && unique.length < 100) {
+			if (!seen.has(key)) {
 				seen.add(key);
 				unique.push(el);
 			}
 		});
 		
-		return unique;
-	})();
+		const start = page * limit;
+		return { items: unique.slice(start, start + limit), total: unique.length };
+	})(arguments[0], arguments[1], arguments[2]);
 	`
 
 	var result []entities.PageElement
-	rawResult, err := s.wd.ExecuteScript(script, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	jsonData, err := json.Marshal(rawResult)
+	hasMore, err := s.runPagedScript(script, page, s.limits.Elements, root, &result)
 	if err != nil {
-		return nil, err
-	}
-
-	if err := json.Unmarshal(jsonData, &result); err != nil {
-		return nil, err
+		return nil, false, err
 	}
+	keepAttributes(result, s.attributes)
 
-	return result, nil
+	return result, hasMore, nil
 }
 
 // extractLinks - extracts links from page using JavaScript
-func (s *SeleniumController) extractLinks(ctx context.Context) ([]entities.LinkInfo, error) {
+func (s *SeleniumController) extractLinks(ctx context.Context, page int, root selenium.WebElement) ([]entities.LinkInfo, bool, error) {
 	script := `
-	(function() {
+	return (function(page, limit, root) {` + selectorHelpersScript + `
+		root = root || document;
 		const links = [];
-		const allLinks = document.querySelectorAll('a[href]');
+		const allLinks = root.querySelectorAll('a[href]');
 		const seen = new Set();
 		
-		for (let i = 0; i < allLinks.length && links.length < 100; i++) {
+		for (let i = 0; i < allLinks.length; i++) {
 			const link = allLinks[i];
 			const style = window.getComputedStyle(link);
 			const isHidden = style.visibility === 'hidden' || style.display === 'none';
@@ -584,6 +1512,7 @@ func (s *SeleniumController) extractLinks(ctx context.Context) ([]entities.LinkI
 			if (link.getAttribute('data-qa')) {
 				selector = 'a[data-qa="' + link.getAttribute('data-qa') + '"]';
 			}
+			selector = uniqueSelector(link, [selector]);
 			
 			links.push({
 				text: text,
@@ -593,46 +1522,53 @@ func (s *SeleniumController) extractLinks(ctx context.Context) ([]entities.LinkI
 			});
 		}
 		
-		return links;
-	})();
+		const start = page * limit;
+		return { items: links.slice(start, start + limit), total: links.length };
+	})(arguments[0], arguments[1], arguments[2]);
 	`
 
 	var result []entities.LinkInfo
-	rawResult, err := s.wd.ExecuteScript(script, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	jsonData, err := json.Marshal(rawResult)
+	hasMore, err := s.runPagedScript(script, page, s.limits.Links, root, &result)
 	if err != nil {
-		return nil, err
-	}
-
-	if err := json.Unmarshal(jsonData, &result); err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	return result, nil
+	return result, hasMore, nil
 }
 
+// maxFormOptions - options of a select list listed in form info, longer lists need get_select_options
+const maxFormOptions = 50
+
 // extractForms - extracts forms from page using JavaScript
-func (s *SeleniumController) extractForms(ctx context.Context) ([]entities.FormInfo, error) {
+func (s *SeleniumController) extractForms(ctx context.Context, root selenium.WebElement) ([]entities.FormInfo, error) {
 	script := `
-	(function() {
+	return (function(root, maxOptions) {
 		const forms = [];
-		const allForms = document.querySelectorAll('form');
+		root = root || document;
+		// A scope inside a form still shows that form
+		const enclosing = root.closest ? root.closest('form') : null;
+		const allForms = enclosing ? [enclosing] : root.querySelectorAll('form');
 		
 		for (let form of allForms) {
 			const inputs = [];
 			const formInputs = form.querySelectorAll('input, textarea, select');
 			
 			for (let input of formInputs) {
-				inputs.push({
+				const field = {
 					type: input.type || input.tagName.toLowerCase(),
 					name: input.name || '',
 					placeholder: input.placeholder || '',
 					value: input.value || ''
-				});
+				};
+				// Lists show their choices, so the AI can pick one without asking for them
+				if (input.tagName.toLowerCase() === 'select') {
+					const options = Array.from(input.options);
+					field.options = options.slice(0, maxOptions)
+						.map(o => (o.label || o.textContent || '').trim().replace(/\s+/g, ' '));
+					field.value = options.filter(o => o.selected)
+						.map(o => (o.label || o.textContent || '').trim()).join(', ');
+				}
+				inputs.push(field);
 			}
 			
 			const submitBtn = form.querySelector('button[type="submit"], input[type="submit"]');
@@ -646,11 +1582,11 @@ func (s *SeleniumController) extractForms(ctx context.Context) ([]entities.FormI
 		}
 		
 		return forms;
-	})();
+	})(arguments[0], arguments[1]);
 	`
 
 	var result []entities.FormInfo
-	rawResult, err := s.wd.ExecuteScript(script, nil)
+	rawResult, err := s.wd.ExecuteScript(script, []interface{}{root, maxFormOptions})
 	if err != nil {
 		return nil, err
 	}
@@ -668,9 +1604,10 @@ func (s *SeleniumController) extractForms(ctx context.Context) ([]entities.FormI
 }
 
 // extractButtons - extracts buttons from page using JavaScript
-func (s *SeleniumController) extractButtons(ctx context.Context) ([]entities.PageElement, error) {
+func (s *SeleniumController) extractButtons(ctx context.Context, page int, root selenium.WebElement) ([]entities.PageElement, bool, error) {
 	script := `
-	(function() {
+	return (function(page, limit, root) {` + accessibilityHelpersScript + selectorHelpersScript + `
+		root = root || document;
 		const buttons = [];
 		const selectors = [
 			'button',
@@ -689,7 +1626,7 @@ func (s *SeleniumController) extractButtons(ctx context.Context) ([]entities.Pag
 		
 		selectors.forEach(selector => {
 			try {
-				document.querySelectorAll(selector).forEach(btn => {
+				root.querySelectorAll(selector).forEach(btn => {
 					const style = window.getComputedStyle(btn);
 					const isHidden = style.visibility === 'hidden' || style.display === 'none';
 					
@@ -701,11 +1638,12 @@ func (s *SeleniumController) extractButtons(ctx context.Context) ([]entities.Pag
 					
 					// Skip buttons with zero size (truly invisible)
 					if (!hasSize && rect.width === 0 && rect.height === 0) return;
+					const isVisible = hasSize;
 					
 					const text = btn.textContent ? btn.textContent.trim().substring(0, 150) : (btn.value || '');
 					const key = btn.tagName + '|' + text + '|' + (btn.id || '');
 					
-					if (seen.has(key) || buttons.length >= 80) return;
+					if (seen.has(key)) return;
 					seen.add(key);
 					
 					// Generate selector
@@ -724,45 +1662,74 @@ func (s *SeleniumController) extractButtons(ctx context.Context) ([]entities.Pag
 					if (btn.getAttribute('data-qa')) {
 						selectorStr = '[data-qa="' + btn.getAttribute('data-qa') + '"]';
 					}
+					selectorStr = uniqueSelector(btn, [selectorStr]);
+					
+					const attrs = {};
+					for (let attr of btn.attributes) {
+						attrs[attr.name] = attr.value;
+					}
 					
 					buttons.push({
 						tag_name: btn.tagName.toLowerCase(),
 						text: text,
-						attributes: {},
+						attributes: attrs,
 						selector: selectorStr,
+						role: ariaRole(btn),
+						accessible_name: accessibleName(btn),
 						is_visible: isVisible,
-						is_clickable: true
+						is_clickable: true,
+						is_disabled: isDisabled(btn)
 					});
 				});
 			} catch(e) {}
 		});
 		
-		return buttons;
-	})();
+		const start = page * limit;
+		return { items: buttons.slice(start, start + limit), total: buttons.length };
+	})(arguments[0], arguments[1], arguments[2]);
 	`
 
 	var result []entities.PageElement
-	rawResult, err := s.wd.ExecuteScript(script, nil)
+	hasMore, err := s.runPagedScript(script, page, s.limits.Buttons, root, &result)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
+	keepAttributes(result, s.attributes)
 
-	jsonData, err := json.Marshal(rawResult)
+	return result, hasMore, nil
+}
+
+// extractFrames - returns src URLs of iframes on the page
+func (s *SeleniumController) extractFrames(ctx context.Context) ([]string, error) {
+	script := `
+		return Array.from(document.querySelectorAll('iframe[src]'))
+			.map(function(frame) { return frame.src; })
+			.filter(function(src) { return src && src !== 'about:blank'; });
+	`
+
+	rawResult, err := s.wd.ExecuteScript(script, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := json.Unmarshal(jsonData, &result); err != nil {
-		return nil, err
+	items, ok := rawResult.([]interface{})
+	if !ok {
+		return nil, nil
 	}
-
-	return result, nil
+	frames := make([]string, 0, len(items))
+	for _, item := range items {
+		if src, ok := item.(string); ok {
+			frames = append(frames, src)
+		}
+	}
+	return frames, nil
 }
 
-// getVisibleText - extracts visible text content from page
-func (s *SeleniumController) getVisibleText(ctx context.Context) (string, error) {
+// getVisibleText - extracts visible text content from page, up to limits.Text characters
+func (s *SeleniumController) getVisibleText(ctx context.Context, root selenium.WebElement) (string, error) {
 	script := `
-	(function() {
+	return (function(root, limit) {
+		root = root || document.body;
 		// Extract text from clickable elements first (list items, table rows, etc.)
 		const clickableTexts = [];
 		const clickableSelectors = [
@@ -780,7 +1747,7 @@ func (s *SeleniumController) getVisibleText(ctx context.Context) (string, error)
 		
 		clickableSelectors.forEach(sel => {
 			try {
-				document.querySelectorAll(sel).forEach(el => {
+				root.querySelectorAll(sel).forEach(el => {
 					const rect = el.getBoundingClientRect();
 					const isVisible = rect.width > 0 && rect.height > 0 &&
 						window.getComputedStyle(el).visibility !== 'hidden' &&
@@ -798,7 +1765,7 @@ func (s *SeleniumController) getVisibleText(ctx context.Context) (string, error)
 		
 		// Also get general visible text
 		const walker = document.createTreeWalker(
-			document.body,
+			root,
 			NodeFilter.SHOW_TEXT,
 			null,
 			false
@@ -806,29 +1773,27 @@ func (s *SeleniumController) getVisibleText(ctx context.Context) (string, error)
 		
 		let text = clickableTexts.join(' | ') + ' | ';
 		let node;
-		let charCount = 0;
-		while ((node = walker.nextNode()) && charCount < 1500) {
+		while ((node = walker.nextNode()) && text.length < limit) {
 			const parent = node.parentElement;
 			if (parent && window.getComputedStyle(parent).display !== 'none') {
 				const nodeText = node.textContent ? node.textContent.trim() : '';
 				if (nodeText.length > 3) {
 					text += nodeText + ' ';
-					charCount += nodeText.length;
 				}
 			}
 		}
 		
-		return text.trim().substring(0, 2000);
-	})();
+		return text.trim().substring(0, limit);
+	})(arguments[0], arguments[1]);
 	`
 
-	result, err := s.wd.ExecuteScript(script, nil)
+	result, err := s.wd.ExecuteScript(script, []interface{}{root, s.limits.Text})
 	if err != nil {
 		return "", err
 	}
 
 	if text, ok := result.(string); ok {
-		return text, nil
+		return capText(text, s.limits.Text), nil
 	}
 
 	return "", nil
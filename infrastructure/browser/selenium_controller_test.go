@@ -0,0 +1,50 @@
+package browser
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tebeka/selenium"
+)
+
+func TestAppendRetryAfterStaleElementKeepsTextOnce(t *testing.T) {
+	// The field re-renders after "ab" of "abc" was typed, the new one shows what the page kept
+	stale := &fakeElement{value: "note: ", staleAfter: 2}
+	fresh := &fakeElement{value: "note: ab"}
+	var setText []interface{}
+	lookups := 0
+	driver := &scriptDriver{
+		find: func(by, value string) (selenium.WebElement, error) {
+			lookups++
+			if lookups == 1 {
+				return stale, nil
+			}
+			return fresh, nil
+		},
+		reply: func(script string, args []interface{}) (interface{}, error) {
+			element := args[0].(*fakeElement)
+			switch {
+			case strings.Contains(script, "isContentEditable"):
+				return textTargetInput, nil
+			case strings.Contains(script, "el.value : el.innerText"):
+				return element.value, nil
+			case strings.Contains(script, "setter.call"):
+				setText = args[1:]
+				element.value = args[1].(string)
+			}
+			return true, nil
+		},
+	}
+	s := newFakeController(driver)
+
+	if err := s.AppendText(context.Background(), "#note", "abc"); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if fresh.value != "note: abc" {
+		t.Errorf("field = %q, want %q", fresh.value, "note: abc")
+	}
+	if len(setText) != 2 || setText[0] != "note: abc" || setText[1] != true {
+		t.Errorf("retry set %v, want the original content plus the text replacing the field", setText)
+	}
+}
@@ -0,0 +1,133 @@
+package browser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ai_automation/config"
+)
+
+// SessionState - pages open when the browser was closed. Cookies and storage live in the
+// Chrome profile, this file only remembers where the user was
+type SessionState struct {
+	// URL is the page of the active tab
+	URL string `json:"url"`
+	// Tabs are the pages of the other open tabs
+	Tabs []string `json:"tabs,omitempty"`
+}
+
+// sessionStateFile - returns ~/.ai_automation/last_session.json, next to the Chrome profile
+func sessionStateFile() (string, error) {
+	homeDir := os.Getenv("HOME")
+	if homeDir == "" {
+		return "", fmt.Errorf("HOME environment variable is not set")
+	}
+	return filepath.Join(homeDir, ".ai_automation", "last_session.json"), nil
+}
+
+// saveSessionState - writes state to the session file
+func saveSessionState(state *SessionState) error {
+	path, err := sessionStateFile()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// clearSessionState - removes the session file
+func clearSessionState() error {
+	path, err := sessionStateFile()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// LoadLastSession - reads the pages saved when the browser was last closed,
+// returns nil when RESTORE_LAST_URL is off or nothing was saved
+func LoadLastSession() (*SessionState, error) {
+	if !config.FromEnv().Browser.RestoreLastURL {
+		return nil, nil
+	}
+	path, err := sessionStateFile()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read last session: %w", err)
+	}
+
+	var state SessionState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid last session file %s: %w", path, err)
+	}
+	if state.URL == "" {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+// Pages - URLs to reopen, the active page first
+func (s *SessionState) Pages() []string {
+	return append([]string{s.URL}, s.Tabs...)
+}
+
+// saveSession - records URLs of all open tabs, the active one first
+func (s *SeleniumController) saveSession() error {
+	current, err := s.wd.CurrentWindowHandle()
+	if err != nil {
+		return err
+	}
+	handles, err := s.wd.WindowHandles()
+	if err != nil {
+		return err
+	}
+
+	state := &SessionState{}
+	for _, handle := range handles {
+		if err := s.wd.SwitchWindow(handle); err != nil {
+			continue
+		}
+		url, err := s.wd.CurrentURL()
+		if err != nil || !isRestorableURL(url) {
+			continue
+		}
+		if handle == current {
+			state.URL = url
+		} else {
+			state.Tabs = append(state.Tabs, url)
+		}
+	}
+	if state.URL == "" && len(state.Tabs) > 0 {
+		state.URL, state.Tabs = state.Tabs[0], state.Tabs[1:]
+	}
+	if state.URL == "" {
+		// Nothing worth reopening, an older session must not be offered instead
+		return clearSessionState()
+	}
+	return saveSessionState(state)
+}
+
+// isRestorableURL - skips blank and browser-internal pages
+func isRestorableURL(url string) bool {
+	return strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")
+}
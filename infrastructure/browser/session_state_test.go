@@ -0,0 +1,39 @@
+package browser
+
+import "testing"
+
+func TestLastSessionRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("RESTORE_LAST_URL", "true")
+
+	saved := &SessionState{URL: "https://shop.example.com/cart", Tabs: []string{"https://shop.example.com/item/1"}}
+	if err := saveSessionState(saved); err != nil {
+		t.Fatalf("saveSessionState: %v", err)
+	}
+
+	state, err := LoadLastSession()
+	if err != nil {
+		t.Fatalf("LoadLastSession: %v", err)
+	}
+	if state == nil || state.URL != saved.URL || len(state.Tabs) != 1 || state.Tabs[0] != saved.Tabs[0] {
+		t.Fatalf("loaded %+v, want %+v", state, saved)
+	}
+
+	if pages := state.Pages(); len(pages) != 2 || pages[0] != saved.URL || pages[1] != saved.Tabs[0] {
+		t.Errorf("Pages() = %v, want the active page first", pages)
+	}
+}
+
+func TestLastSessionDisabled(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("RESTORE_LAST_URL", "true")
+	if err := saveSessionState(&SessionState{URL: "https://example.com"}); err != nil {
+		t.Fatalf("saveSessionState: %v", err)
+	}
+
+	t.Setenv("RESTORE_LAST_URL", "")
+	state, err := LoadLastSession()
+	if err != nil || state != nil {
+		t.Errorf("LoadLastSession with restore off = %+v, %v; want nothing", state, err)
+	}
+}
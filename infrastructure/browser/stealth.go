@@ -0,0 +1,86 @@
+package browser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// stealthScript - hides the most common automation fingerprints, runs before page scripts
+const stealthScript = `
+	Object.defineProperty(navigator, 'webdriver', {get: () => undefined});
+	if (!window.chrome) { window.chrome = {runtime: {}}; }
+	if (!navigator.languages || navigator.languages.length === 0) {
+		Object.defineProperty(navigator, 'languages', {get: () => ['en-US', 'en']});
+	}
+	if (navigator.plugins.length === 0) {
+		Object.defineProperty(navigator, 'plugins', {get: () => [1, 2, 3, 4, 5]});
+	}
+	var originalQuery = window.navigator.permissions && window.navigator.permissions.query;
+	if (originalQuery) {
+		window.navigator.permissions.query = function(parameters) {
+			if (parameters && parameters.name === 'notifications') {
+				return Promise.resolve({state: Notification.permission});
+			}
+			return originalQuery.call(window.navigator.permissions, parameters);
+		};
+	}
+`
+
+// installStealth - registers stealthScript for every new document and applies it to the current one
+func (s *SeleniumController) installStealth() error {
+	if err := s.executeCDP("Page.addScriptToEvaluateOnNewDocument", map[string]interface{}{"source": stealthScript}); err != nil {
+		return fmt.Errorf("failed to install stealth script: %w", err)
+	}
+	if _, err := s.wd.ExecuteScript(stealthScript, nil); err != nil {
+		s.logger.Warnf("Failed to apply stealth script to current page: %v", err)
+	}
+	return nil
+}
+
+// executeCDP - runs a Chrome DevTools Protocol command through ChromeDriver's goog/cdp endpoint
+func (s *SeleniumController) executeCDP(cmd string, params map[string]interface{}) error {
+	return callCDP(s.driverURL, s.wd.SessionID(), cmd, params, nil)
+}
+
+// callCDP - posts a CDP command to the session's goog/cdp endpoint and decodes its result into result (nil skips it)
+func callCDP(driverURL, sessionID, cmd string, params map[string]interface{}, result interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{"cmd": cmd, "params": params})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/session/%s/goog/cdp/execute", driverURL, sessionID)
+	resp, err := http.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var reply struct {
+		Value json.RawMessage `json:"value"`
+	}
+	decodeErr := json.NewDecoder(resp.Body).Decode(&reply)
+
+	if resp.StatusCode != http.StatusOK {
+		// WebDriver errors carry the reason in value.message
+		var failure struct {
+			Message string `json:"message"`
+		}
+		if decodeErr == nil && json.Unmarshal(reply.Value, &failure) == nil && failure.Message != "" {
+			return fmt.Errorf("CDP command %s failed with status %d: %s", cmd, resp.StatusCode, failure.Message)
+		}
+		return fmt.Errorf("CDP command %s failed with status %d", cmd, resp.StatusCode)
+	}
+	if result == nil {
+		return nil
+	}
+	if decodeErr != nil {
+		return fmt.Errorf("failed to decode CDP %s response: %w", cmd, decodeErr)
+	}
+	if err := json.Unmarshal(reply.Value, result); err != nil {
+		return fmt.Errorf("failed to decode CDP %s response: %w", cmd, err)
+	}
+	return nil
+}
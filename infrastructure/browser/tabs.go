@@ -0,0 +1,247 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"ai_automation/domain/interfaces"
+)
+
+// newTabWindow - how long after a click a newly opened tab is looked for
+const newTabWindow = 500 * time.Millisecond
+
+// followNewTab - switches to a tab opened by the last action (target=_blank, window.open),
+// remembering the current one so the agent can come back with SwitchToPreviousTab
+func (s *SeleniumController) followNewTab(ctx context.Context, handlesBefore []string) {
+	if handlesBefore == nil {
+		return
+	}
+	known := make(map[string]bool, len(handlesBefore))
+	for _, handle := range handlesBefore {
+		known[handle] = true
+	}
+
+	deadline := time.Now().Add(newTabWindow)
+	for {
+		handles, err := s.wd.WindowHandles()
+		if err != nil {
+			return
+		}
+		for _, handle := range handles {
+			if known[handle] {
+				continue
+			}
+			current, err := s.wd.CurrentWindowHandle()
+			if err != nil {
+				return
+			}
+			if err := s.wd.SwitchWindow(handle); err != nil {
+				s.logger.Warnf("Failed to switch to new tab: %v", err)
+				return
+			}
+			s.tabHistory = append(s.tabHistory, current)
+			s.logger.Infof("Switched to new tab (%d open)", len(handles))
+			return
+		}
+
+		if time.Now().After(deadline) || sleepWithContext(ctx, 100*time.Millisecond) != nil {
+			return
+		}
+	}
+}
+
+// OpenNewTab - opens url in a new tab and switches to it, the current tab stays open
+// and SwitchToPreviousTab returns to it
+func (s *SeleniumController) OpenNewTab(ctx context.Context, url string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	handlesBefore, err := s.wd.WindowHandles()
+	if err != nil {
+		return driverError(interfaces.ErrCommand, err, "failed to list tabs: %v", err)
+	}
+	if _, err := s.wd.ExecuteScript("window.open('about:blank', '_blank'); return null;", nil); err != nil {
+		return driverError(interfaces.ErrCommand, err, "failed to open new tab: %v", err)
+	}
+	s.followNewTab(ctx, handlesBefore)
+
+	current, err := s.wd.CurrentWindowHandle()
+	if err != nil {
+		return driverError(interfaces.ErrCommand, err, "failed to open new tab: %v", err)
+	}
+	for _, handle := range handlesBefore {
+		if handle == current {
+			return newBrowserError(interfaces.ErrNavigation, nil, "new tab did not open (blocked as a popup?)")
+		}
+	}
+	return s.Navigate(ctx, url)
+}
+
+// SwitchToTab - switches to an open tab already showing url instead of opening a duplicate,
+// reports false (staying on the current tab) when there is none
+func (s *SeleniumController) SwitchToTab(ctx context.Context, url string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	current, err := s.wd.CurrentWindowHandle()
+	if err != nil {
+		return false, driverError(interfaces.ErrCommand, err, "failed to list tabs: %v", err)
+	}
+	if currentURL, err := s.wd.CurrentURL(); err == nil && sameTabURL(currentURL, url) {
+		return true, nil
+	}
+	handles, err := s.wd.WindowHandles()
+	if err != nil {
+		return false, driverError(interfaces.ErrCommand, err, "failed to list tabs: %v", err)
+	}
+
+	// WebDriver reads the URL of the active tab only, so each other tab is visited in turn
+	for _, handle := range handles {
+		if handle == current {
+			continue
+		}
+		if err := s.wd.SwitchWindow(handle); err != nil {
+			continue
+		}
+		if tabURL, err := s.wd.CurrentURL(); err == nil && sameTabURL(tabURL, url) {
+			s.tabHistory = append(s.tabHistory, current)
+			s.logger.Infof("Switched to already open tab %s", tabURL)
+			return true, nil
+		}
+	}
+	if err := s.wd.SwitchWindow(current); err != nil {
+		return false, driverError(interfaces.ErrCommand, err, "failed to return to the current tab: %v", err)
+	}
+	return false, nil
+}
+
+// sameTabURL - compares URLs the way a reader would: scheme and host case, a trailing slash
+// and the fragment do not make a different page
+func sameTabURL(a, b string) bool {
+	return tabURLKey(a) == tabURLKey(b)
+}
+
+func tabURLKey(raw string) string {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || parsed.Host == "" {
+		return strings.TrimSpace(raw)
+	}
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	parsed.Host = strings.ToLower(parsed.Host)
+	parsed.Path = strings.TrimSuffix(parsed.Path, "/")
+	parsed.Fragment = ""
+	parsed.RawFragment = ""
+	return parsed.String()
+}
+
+// BringToFront - raises the window with the active tab above other windows. Chrome and Edge do it
+// through CDP, WebDriver switching to the window does it elsewhere
+func (s *SeleniumController) BringToFront(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if s.browserName != "firefox" {
+		return s.executeCDP("Page.bringToFront", map[string]interface{}{})
+	}
+	current, err := s.wd.CurrentWindowHandle()
+	if err != nil {
+		return driverError(interfaces.ErrCommand, err, "failed to list tabs: %v", err)
+	}
+	if err := s.wd.SwitchWindow(current); err != nil {
+		return driverError(interfaces.ErrCommand, err, "failed to raise the window: %v", err)
+	}
+	return nil
+}
+
+// SwitchToPreviousTab - returns to the tab that was active before the current one
+func (s *SeleniumController) SwitchToPreviousTab(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	handles, err := s.wd.WindowHandles()
+	if err != nil {
+		return driverError(interfaces.ErrCommand, err, "failed to list tabs: %v", err)
+	}
+	current, err := s.wd.CurrentWindowHandle()
+	if err != nil {
+		return driverError(interfaces.ErrCommand, err, "failed to list tabs: %v", err)
+	}
+
+	target := s.popPreviousTab(handles, current)
+	if target == "" {
+		return fmt.Errorf("there is no previous tab")
+	}
+	if err := s.wd.SwitchWindow(target); err != nil {
+		return driverError(interfaces.ErrCommand, err, "failed to switch tabs: %v", err)
+	}
+	return nil
+}
+
+// CloseCurrentTab - closes the active tab and returns to the previous one.
+// The last tab is never closed (that would end the session), it is reset to a blank page instead
+func (s *SeleniumController) CloseCurrentTab(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	handles, err := s.wd.WindowHandles()
+	if err != nil {
+		return driverError(interfaces.ErrCommand, err, "failed to list tabs: %v", err)
+	}
+	if len(handles) <= 1 {
+		s.tabHistory = nil
+		if err := s.wd.Get("about:blank"); err != nil {
+			return driverError(interfaces.ErrNavigation, err, "failed to reset the last tab: %v", err)
+		}
+		return nil
+	}
+
+	current, err := s.wd.CurrentWindowHandle()
+	if err != nil {
+		return driverError(interfaces.ErrCommand, err, "failed to list tabs: %v", err)
+	}
+	target := s.popPreviousTab(handles, current)
+	if err := s.wd.CloseWindow(current); err != nil {
+		return driverError(interfaces.ErrCommand, err, "failed to close tab: %v", err)
+	}
+	if err := s.wd.SwitchWindow(target); err != nil {
+		return driverError(interfaces.ErrCommand, err, "failed to switch tabs: %v", err)
+	}
+	return nil
+}
+
+// popPreviousTab - takes the most recent still open tab from history other than current,
+// falling back to the tab before current in window order
+func (s *SeleniumController) popPreviousTab(handles []string, current string) string {
+	open := make(map[string]bool, len(handles))
+	for _, handle := range handles {
+		open[handle] = true
+	}
+
+	for len(s.tabHistory) > 0 {
+		last := s.tabHistory[len(s.tabHistory)-1]
+		s.tabHistory = s.tabHistory[:len(s.tabHistory)-1]
+		if open[last] && last != current {
+			return last
+		}
+	}
+
+	for i, handle := range handles {
+		if handle != current {
+			continue
+		}
+		if i > 0 {
+			return handles[i-1]
+		}
+		if len(handles) > 1 {
+			return handles[1]
+		}
+	}
+	return ""
+}
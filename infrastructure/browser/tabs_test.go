@@ -0,0 +1,24 @@
+package browser
+
+import "testing"
+
+func TestSameTabURL(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"https://shop.example.com/cart", "https://shop.example.com/cart", true},
+		{"https://Shop.Example.com/cart/", "https://shop.example.com/cart", true},
+		{"https://shop.example.com/", "https://shop.example.com", true},
+		{"https://shop.example.com/cart#summary", "https://shop.example.com/cart", true},
+		{"https://shop.example.com/cart?page=2", "https://shop.example.com/cart", false},
+		{"https://shop.example.com/Cart", "https://shop.example.com/cart", false},
+		{"http://shop.example.com/cart", "https://shop.example.com/cart", false},
+		{"about:blank", "about:blank", true},
+	}
+	for _, tt := range tests {
+		if got := sameTabURL(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameTabURL(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -2,25 +2,77 @@ package security
 
 import (
 	"context"
+	"net/url"
 	"strings"
 
+	"ai_automation/config"
 	"ai_automation/domain/entities"
 	"ai_automation/domain/interfaces"
 
 	"github.com/sirupsen/logrus"
 )
 
+// Policy controls which actions need user approval
+type Policy string
+
+const (
+	// PolicyStrict asks for approval of every medium and high risk action
+	PolicyStrict Policy = "strict"
+	// PolicyNormal asks for approval of destructive, payment and critical form actions
+	PolicyNormal Policy = "normal"
+	// PolicyYolo never asks for approval
+	PolicyYolo Policy = "yolo"
+)
+
 type SecurityLayer struct {
 	logger *logrus.Logger
+	policy Policy
+	// sensitiveDomains - hosts (with their subdomains) where every action needs approval
+	sensitiveDomains []string
 }
 
 func NewSecurityLayer(logger *logrus.Logger) *SecurityLayer {
+	cfg := config.FromEnv().Security
+	policy := Policy(cfg.Policy)
+	if policy == PolicyYolo {
+		logger.Warn("SECURITY_POLICY=yolo: actions will run without approval")
+		if len(cfg.SensitiveDomains) > 0 {
+			logger.Warnf("Actions on %s still require approval (SENSITIVE_DOMAINS)", strings.Join(cfg.SensitiveDomains, ", "))
+		}
+	}
+
 	return &SecurityLayer{
-		logger: logger,
+		logger:           logger,
+		policy:           policy,
+		sensitiveDomains: cfg.SensitiveDomains,
 	}
 }
 
+// Policy - returns active approval policy
+func (s *SecurityLayer) Policy() Policy {
+	return s.policy
+}
+
 func (s *SecurityLayer) RequiresApproval(ctx context.Context, action *entities.Action, pageInfo *entities.PageInfo) bool {
+	// On banking and other sensitive sites even harmless looking actions are confirmed, whatever the policy
+	if pageInfo != nil && s.isSensitiveDomain(pageInfo.URL) {
+		return true
+	}
+
+	switch s.policy {
+	case PolicyYolo:
+		return false
+	case PolicyStrict:
+		if risk := s.GetActionRiskLevel(ctx, action); risk == "medium" || risk == "high" {
+			return true
+		}
+	}
+
+	// A coordinate click has no selector, without a description there is nothing to classify
+	if action.Type == entities.ActionClickAt && strings.TrimSpace(action.Description) == "" {
+		return true
+	}
+
 	if s.IsDestructiveAction(ctx, action) {
 		return true
 	}
@@ -44,8 +96,13 @@ func (s *SecurityLayer) RequiresApproval(ctx context.Context, action *entities.A
 }
 
 func (s *SecurityLayer) IsDestructiveAction(ctx context.Context, action *entities.Action) bool {
+	// Custom JavaScript can change anything on the page
+	if action.Type == entities.ActionExecuteJS {
+		return true
+	}
+
 	// Check action type
-	if action.Type == entities.ActionClick {
+	if isClickAction(action) {
 		// Check if clicking on delete, remove, or similar buttons
 		lowerSelector := strings.ToLower(action.Selector)
 		lowerDesc := strings.ToLower(action.Description)
@@ -77,12 +134,12 @@ func (s *SecurityLayer) GetActionRiskLevel(ctx context.Context, action *entities
 		return "low"
 	}
 	
-	if action.Type == entities.ActionTypeText {
+	if action.Type == entities.ActionTypeText || action.Type == entities.ActionPaste || action.Type == entities.ActionSetDate || action.Type == entities.ActionSelect {
 		// Typing text could be medium risk if it's in forms
 		return "medium"
 	}
 	
-	if action.Type == entities.ActionClick {
+	if isClickAction(action) {
 		// Clicking could be medium to high risk depending on context
 		return "medium"
 	}
@@ -105,7 +162,7 @@ func (s *SecurityLayer) isPaymentAction(ctx context.Context, action *entities.Ac
 	for _, keyword := range paymentKeywords {
 		if strings.Contains(lowerURL, keyword) {
 			// If we're on a payment page and clicking submit/confirm
-			if action.Type == entities.ActionClick {
+			if isClickAction(action) {
 				lowerSelector := strings.ToLower(action.Selector)
 				lowerDesc := strings.ToLower(action.Description)
 				
@@ -127,7 +184,7 @@ func (s *SecurityLayer) isPaymentAction(ctx context.Context, action *entities.Ac
 }
 
 func (s *SecurityLayer) isDeletionAction(ctx context.Context, action *entities.Action, pageInfo *entities.PageInfo) bool {
-	if action.Type != entities.ActionClick {
+	if !isClickAction(action) {
 		return false
 	}
 	
@@ -153,7 +210,7 @@ func (s *SecurityLayer) isCriticalFormSubmission(ctx context.Context, action *en
 		return false
 	}
 	
-	if action.Type == entities.ActionClick {
+	if isClickAction(action) {
 		// Check if we're submitting a form
 		lowerSelector := strings.ToLower(action.Selector)
 		lowerDesc := strings.ToLower(action.Description)
@@ -175,6 +232,34 @@ func (s *SecurityLayer) isCriticalFormSubmission(ctx context.Context, action *en
 	return false
 }
 
+// isSensitiveDomain - reports pages whose host is one of SENSITIVE_DOMAINS or their subdomain
+func (s *SecurityLayer) isSensitiveDomain(pageURL string) bool {
+	if len(s.sensitiveDomains) == 0 {
+		return false
+	}
+	parsed, err := url.Parse(pageURL)
+	if err != nil {
+		return false
+	}
+	host := strings.TrimSuffix(strings.ToLower(parsed.Hostname()), ".")
+	for _, domain := range s.sensitiveDomains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// isClickAction - reports actions that click an element: right-clicks, coordinate clicks,
+// download triggers and checkboxes get the same keyword checks as a plain click
+func isClickAction(action *entities.Action) bool {
+	switch action.Type {
+	case entities.ActionClick, entities.ActionClickAt, entities.ActionRightClick, entities.ActionDownload, entities.ActionSetChecked:
+		return true
+	}
+	return false
+}
+
 // Ensure SecurityLayer implements SecurityLayer interface
 var _ interfaces.SecurityLayer = (*SecurityLayer)(nil)
 
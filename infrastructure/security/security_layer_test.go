@@ -0,0 +1,54 @@
+package security
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"ai_automation/domain/entities"
+
+	"github.com/sirupsen/logrus"
+)
+
+func newTestLayer(t *testing.T, policy, domains string) *SecurityLayer {
+	t.Helper()
+	t.Setenv("SECURITY_POLICY", policy)
+	t.Setenv("SENSITIVE_DOMAINS", domains)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewSecurityLayer(logger)
+}
+
+func TestSensitiveDomainRequiresApprovalForBenignClick(t *testing.T) {
+	layer := newTestLayer(t, "normal", "mybank.com")
+	click := &entities.Action{Type: entities.ActionClick, Selector: "#statements", Description: "open statements"}
+
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://mybank.com/accounts", true},
+		{"https://online.MyBank.com/accounts", true},
+		{"https://shop.example.com/accounts", false},
+		{"https://notmybank.com/accounts", false},
+		{"https://mybank.com.evil.example/accounts", false},
+	}
+	for _, tt := range tests {
+		page := &entities.PageInfo{URL: tt.url}
+		if got := layer.RequiresApproval(context.Background(), click, page); got != tt.want {
+			t.Errorf("RequiresApproval on %s = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestSensitiveDomainOverridesYolo(t *testing.T) {
+	layer := newTestLayer(t, "yolo", "mybank.com")
+	scroll := &entities.Action{Type: entities.ActionScroll, Text: "down", Description: "see more"}
+
+	if !layer.RequiresApproval(context.Background(), scroll, &entities.PageInfo{URL: "https://mybank.com/"}) {
+		t.Error("action on a sensitive domain ran without approval under yolo")
+	}
+	if layer.RequiresApproval(context.Background(), scroll, &entities.PageInfo{URL: "https://example.com/"}) {
+		t.Error("yolo asked for approval outside sensitive domains")
+	}
+}
@@ -1,21 +1,56 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"ai_automation/presentation/terminal"
 )
 
 func main() {
-	termInterface, err := terminal.NewTerminalInterface()
+	configPath := flag.String("config", "", "env file applied after .env and .env.local (variables set in the environment still win)")
+	replayPath := flag.String("replay", "", "replay a script saved with /export instead of starting the interactive agent")
+	tasksPath := flag.String("tasks-file", "", "run the tasks of a file (one per line or a JSON array) one after another and exit")
+	tasksOutput := flag.String("tasks-output", "", "results file of -tasks-file, one JSON line per task (default: <tasks-file>.results.jsonl)")
+	flag.Parse()
+
+	termInterface, err := terminal.NewTerminalInterface(*configPath)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to initialize: %v\n", err)
 		os.Exit(1)
 	}
 	defer termInterface.Close()
 
-	if err := termInterface.Run(); err != nil {
+	// Ctrl-C cancels the running task instead of killing the process,
+	// so the browser and ChromeDriver are shut down by Close()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *replayPath != "" {
+		if err := termInterface.Replay(ctx, *replayPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *tasksPath != "" {
+		output := *tasksOutput
+		if output == "" {
+			output = *tasksPath + ".results.jsonl"
+		}
+		if err := termInterface.RunTaskQueue(ctx, *tasksPath, output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := termInterface.Run(ctx); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
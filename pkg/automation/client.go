@@ -0,0 +1,146 @@
+// Package automation - runs the agent from other Go programs. It builds the same components
+// as the terminal interface but never reads stdin or writes stdout
+package automation
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"ai_automation/application/agent"
+	"ai_automation/config"
+	"ai_automation/domain/entities"
+	"ai_automation/domain/i18n"
+	"ai_automation/domain/interfaces"
+	"ai_automation/infrastructure/ai"
+	"ai_automation/infrastructure/browser"
+	"ai_automation/infrastructure/security"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Options - settings of a Client. Everything else comes from the environment, as for the terminal
+type Options struct {
+	// ConfigPath is an env file applied after .env and .env.local, like --config
+	ConfigPath string
+	// Logger receives component logs, nil discards them
+	Logger *logrus.Logger
+	// Output receives progress messages, nil discards them
+	Output io.Writer
+	// Approve decides on actions that need approval, nil rejects all of them
+	Approve agent.Approver
+	// Observers receive the lifecycle of every task: decided and executed actions, approvals, outcomes
+	Observers []agent.Observer
+	// Browser, AI and Security replace the components built from configuration
+	Browser  interfaces.BrowserController
+	AI       interfaces.AIService
+	Security interfaces.SecurityLayer
+}
+
+// Result - outcome of a task
+type Result struct {
+	Status entities.TaskStatus
+	// Summary is the AI's description of the result, empty when it gave none
+	Summary string
+	// Actions are the steps that succeeded, in order
+	Actions  []entities.Action
+	Duration time.Duration
+}
+
+// Client - an agent with its own browser. Tasks run one at a time, Close shuts the browser down
+type Client struct {
+	agent   *agent.Agent
+	browser interfaces.BrowserController
+	// mu serializes tasks, they share the browser
+	mu    sync.Mutex
+	tasks int
+}
+
+// New - loads configuration and starts the components not supplied in opts
+func New(opts Options) (*Client, error) {
+	cfg, err := config.Load(opts.ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	i18n.SetLanguage(cfg.Lang)
+
+	logger := opts.Logger
+	if logger == nil {
+		logger = logrus.New()
+		logger.SetOutput(io.Discard)
+	}
+	for _, warning := range cfg.Warnings {
+		logger.Warn(warning)
+	}
+
+	aiService := opts.AI
+	if aiService == nil {
+		if aiService, err = ai.NewClient(cfg.AI.Provider, logger); err != nil {
+			return nil, fmt.Errorf("failed to initialize AI service: %w", err)
+		}
+	}
+
+	browserCtrl := opts.Browser
+	if browserCtrl == nil {
+		if browserCtrl, err = browser.NewSeleniumController(logger); err != nil {
+			return nil, fmt.Errorf("failed to initialize browser: %w", err)
+		}
+	}
+
+	securityLayer := opts.Security
+	if securityLayer == nil {
+		securityLayer = security.NewSecurityLayer(logger)
+	}
+
+	output := opts.Output
+	if output == nil {
+		output = io.Discard
+	}
+	approve := opts.Approve
+	if approve == nil {
+		approve = func(ctx context.Context, action *entities.Action) bool { return false }
+	}
+
+	ag := agent.NewAgent(browserCtrl, aiService, securityLayer, logger)
+	ag.SetPresenter(agent.NewConsolePresenter(output))
+	ag.SetProgress(nil)
+	ag.SetApprover(approve)
+	for _, observer := range opts.Observers {
+		ag.AddObserver(observer)
+	}
+
+	return &Client{agent: ag, browser: browserCtrl}, nil
+}
+
+// Run - executes the task and waits for it to finish. The error is the one the task failed with,
+// Result describes the task either way
+func (c *Client) Run(ctx context.Context, taskDescription string) (Result, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.tasks++
+	task := &entities.Task{
+		ID:          fmt.Sprintf("task-%d", c.tasks),
+		Description: strings.TrimSpace(taskDescription),
+		Status:      entities.TaskStatusPending,
+	}
+
+	startedAt := time.Now()
+	// Nobody answers questions or login prompts, the agent proceeds without them
+	err := c.agent.ExecuteTask(ctx, task, bufio.NewReader(strings.NewReader("")))
+	return Result{
+		Status:   task.Status,
+		Summary:  task.Result,
+		Actions:  task.Actions,
+		Duration: time.Since(startedAt),
+	}, err
+}
+
+// Close - shuts down the browser, including one passed in Options
+func (c *Client) Close() error {
+	return c.browser.Close()
+}
@@ -0,0 +1,83 @@
+package automation_test
+
+import (
+	"context"
+	"testing"
+
+	"ai_automation/domain/entities"
+	"ai_automation/pkg/automation"
+	"ai_automation/testing/mocks"
+)
+
+func TestRunDrivesTaskToCompletion(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	browser := mocks.NewBrowser()
+	ai := mocks.NewAI(
+		&entities.Action{Type: entities.ActionNavigate, URL: "https://shop.example.com", Description: "open the shop"},
+		&entities.Action{Type: entities.ActionClick, Selector: "#buy", Description: "buy"},
+		&entities.Action{Type: entities.ActionComplete, Text: "order placed"},
+	)
+	security := mocks.NewSecurity()
+	security.Approval[entities.ActionClick] = true
+
+	var asked []entities.Action
+	client, err := automation.New(automation.Options{
+		Browser:  browser,
+		AI:       ai,
+		Security: security,
+		Approve: func(ctx context.Context, action *entities.Action) bool {
+			asked = append(asked, *action)
+			return true
+		},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	result, err := client.Run(context.Background(), "buy the cheapest item")
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if result.Status != entities.TaskStatusCompleted || result.Summary != "order placed" {
+		t.Errorf("result = %+v, want completed with the summary", result)
+	}
+	if len(result.Actions) != 2 || result.Actions[0].Type != entities.ActionNavigate || result.Actions[1].Selector != "#buy" {
+		t.Errorf("actions = %+v, want navigate and click", result.Actions)
+	}
+	if len(asked) != 1 || asked[0].Selector != "#buy" {
+		t.Errorf("approval asked for %+v, want the click only", asked)
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if browser.CallCount("Close") != 1 {
+		t.Error("Close did not shut the browser down")
+	}
+}
+
+func TestRunWithoutApproverRejectsActions(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	browser := mocks.NewBrowser()
+	security := mocks.NewSecurity()
+	security.Approval[entities.ActionClick] = true
+	client, err := automation.New(automation.Options{
+		Browser:  browser,
+		AI:       mocks.NewAI(&entities.Action{Type: entities.ActionClick, Selector: "#delete", Description: "delete account"}),
+		Security: security,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer client.Close()
+
+	result, _ := client.Run(context.Background(), "delete my account")
+	if browser.CallCount("Click")+browser.CallCount("ClickClosest") != 0 {
+		t.Error("action ran without approval")
+	}
+	if len(result.Actions) != 0 {
+		t.Errorf("actions = %+v, want none", result.Actions)
+	}
+}
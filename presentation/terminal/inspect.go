@@ -0,0 +1,98 @@
+package terminal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"ai_automation/domain/entities"
+	"ai_automation/domain/i18n"
+)
+
+// inspectTextWidth - element text is cut to this many characters so rows fit the terminal
+const inspectTextWidth = 40
+
+// inspectRow - one element as /inspect shows it
+type inspectRow struct {
+	tag, text, selector string
+	visible, clickable  bool
+}
+
+// inspectRows - links, buttons and other elements of the page in the order the AI sees them.
+// A non-empty filter keeps elements whose selector contains it, case-insensitively
+func inspectRows(pageInfo *entities.PageInfo, filter string) []inspectRow {
+	var rows []inspectRow
+	for _, link := range pageInfo.Links {
+		// Only visible links are extracted
+		rows = append(rows, inspectRow{tag: "a", text: link.Text, selector: link.Selector, visible: true, clickable: true})
+	}
+	for _, elements := range [][]entities.PageElement{pageInfo.Buttons, pageInfo.Elements} {
+		for _, elem := range elements {
+			text := elem.Text
+			if text == "" {
+				text = elem.AccessibleName
+			}
+			rows = append(rows, inspectRow{tag: elem.TagName, text: text, selector: elem.Selector, visible: elem.IsVisible, clickable: elem.IsClickable})
+		}
+	}
+
+	filter = strings.ToLower(strings.TrimSpace(filter))
+	if filter == "" {
+		return rows
+	}
+	var matched []inspectRow
+	for _, row := range rows {
+		if strings.Contains(strings.ToLower(row.selector), filter) {
+			matched = append(matched, row)
+		}
+	}
+	return matched
+}
+
+// writeInspectTable - prints rows as aligned columns, "+" and "-" mark visibility and clickability
+func writeInspectTable(w io.Writer, rows []inspectRow) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, i18n.T(i18n.MsgInspectColumns))
+	for _, row := range rows {
+		text := strings.Join(strings.Fields(row.text), " ")
+		if runes := []rune(text); len(runes) > inspectTextWidth {
+			text = string(runes[:inspectTextWidth]) + "..."
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", row.tag, text, row.selector, mark(row.visible), mark(row.clickable))
+	}
+	return table.Flush()
+}
+
+func mark(value bool) string {
+	if value {
+		return "+"
+	}
+	return "-"
+}
+
+// inspectPage - prints what the agent extracts from the current page, optionally only elements
+// whose selector contains filter
+func (t *TerminalInterface) inspectPage(ctx context.Context, filter string) {
+	pageInfo, err := t.browserCtrl.ExtractPageInfo(ctx, 0)
+	if err != nil {
+		t.out.Printf("\n%s\n\n", i18n.T(i18n.MsgInspectFailed, err))
+		return
+	}
+
+	rows := inspectRows(pageInfo, filter)
+	if len(rows) == 0 {
+		t.out.Printf("\n%s\n\n", i18n.T(i18n.MsgInspectNone))
+		return
+	}
+
+	t.out.Printf("\n%s\n", i18n.T(i18n.MsgInspectHeader, pageInfo.URL, len(rows)))
+	var table strings.Builder
+	writeInspectTable(&table, rows)
+	t.out.Print(table.String())
+	if pageInfo.HasMore {
+		t.out.Println(i18n.T(i18n.MsgInspectMore))
+	}
+	t.out.Println()
+}
@@ -0,0 +1,56 @@
+package terminal
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"ai_automation/domain/entities"
+	"ai_automation/domain/i18n"
+	"ai_automation/testing/mocks"
+)
+
+func TestInspectPrintsExtractedElements(t *testing.T) {
+	i18n.SetLanguage("en")
+	defer i18n.SetLanguage("ru")
+
+	browser := mocks.NewBrowser()
+	browser.PageInfo = &entities.PageInfo{
+		URL:   "https://shop.example/cart",
+		Links: []entities.LinkInfo{{Text: "Home", Href: "/", Selector: "#home"}},
+		Buttons: []entities.PageElement{
+			{TagName: "button", Text: "Checkout", Selector: "#checkout", IsVisible: true, IsClickable: true},
+		},
+		Elements: []entities.PageElement{
+			{TagName: "div", AccessibleName: "Promo banner", Selector: "#promo", IsVisible: false},
+		},
+	}
+	var out bytes.Buffer
+	term := newTestTerminal(browser, mocks.NewAI(), mocks.NewSecurity(), &out)
+
+	term.inspectPage(context.Background(), "")
+	rows := map[string][]string{}
+	for _, line := range strings.Split(out.String(), "\n") {
+		if fields := strings.Fields(line); len(fields) >= 4 {
+			rows[fields[0]] = fields
+		}
+	}
+	want := map[string][]string{
+		"TAG":    {"TAG", "TEXT", "SELECTOR", "VISIBLE", "CLICKABLE"},
+		"a":      {"a", "Home", "#home", "+", "+"},
+		"button": {"button", "Checkout", "#checkout", "+", "+"},
+		"div":    {"div", "Promo", "banner", "#promo", "-", "-"},
+	}
+	for tag, fields := range want {
+		if strings.Join(rows[tag], " ") != strings.Join(fields, " ") {
+			t.Errorf("%s row = %q, want %q\n%s", tag, rows[tag], fields, out.String())
+		}
+	}
+
+	out.Reset()
+	term.inspectPage(context.Background(), " CHECK")
+	if !strings.Contains(out.String(), "#checkout") || strings.Contains(out.String(), "#home") || strings.Contains(out.String(), "#promo") {
+		t.Errorf("filtered output lists other elements:\n%s", out.String())
+	}
+}
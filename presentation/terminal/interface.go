@@ -4,52 +4,73 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"time"
 
 	"ai_automation/application/agent"
+	"ai_automation/config"
 	"ai_automation/domain/entities"
+	"ai_automation/domain/i18n"
 	"ai_automation/domain/interfaces"
 	"ai_automation/infrastructure/ai"
 	"ai_automation/infrastructure/browser"
 	"ai_automation/infrastructure/security"
 
-	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
 )
 
+// apiValidationTimeout - max time for the startup API key check
+const apiValidationTimeout = 15 * time.Second
+
 type TerminalInterface struct {
 	agent       *agent.Agent
 	browserCtrl interfaces.BrowserController
 	logger      *logrus.Logger
 	reader      *bufio.Reader
+	out         agent.Presenter
+	lastTask    *entities.Task
 }
 
-func NewTerminalInterface() (*TerminalInterface, error) {
-	// Load environment variables
-	if err := godotenv.Load(); err != nil {
-		// .env file is optional
-		fmt.Println("Warning: .env file not found, using environment variables")
+// NewTerminalInterface - loads configuration (.env, .env.local, then configPath when set) and starts all components
+func NewTerminalInterface(configPath string) (*TerminalInterface, error) {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, err
 	}
 
+	// Output language for user-facing messages (ru by default)
+	i18n.SetLanguage(cfg.Lang)
+
 	// Setup logger
-	logger := logrus.New()
-	logger.SetLevel(logrus.InfoLevel)
-	logger.SetFormatter(&logrus.TextFormatter{
-		FullTimestamp: true,
-	})
+	logger := newLogger(cfg.Log)
+	if len(cfg.Files) == 0 {
+		// env files are optional
+		logger.Warn(".env file not found, using environment variables")
+	} else {
+		logger.Debugf("Loaded configuration from %s", strings.Join(cfg.Files, ", "))
+	}
+	for _, warning := range cfg.Warnings {
+		logger.Warn(warning)
+	}
 
-	// Initialize browser controller
-	browserCtrl, err := browser.NewSeleniumController(logger)
+	// Initialize AI service first so a bad key fails before the browser is launched
+	aiService, err := ai.NewClient(cfg.AI.Provider, logger)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize browser: %w", err)
+		return nil, fmt.Errorf("failed to initialize AI service: %w", err)
 	}
 
-	// Initialize AI service
-	aiService, err := ai.NewOpenAIClient(logger)
+	if cfg.AI.ValidateKey {
+		if err := validateAIService(aiService); err != nil {
+			return nil, err
+		}
+	}
+
+	// Initialize browser controller
+	browserCtrl, err := browser.NewSeleniumController(logger)
 	if err != nil {
-		browserCtrl.Close()
-		return nil, fmt.Errorf("failed to initialize AI service: %w", err)
+		return nil, fmt.Errorf("failed to initialize browser: %w", err)
 	}
 
 	// Initialize security layer
@@ -57,27 +78,70 @@ func NewTerminalInterface() (*TerminalInterface, error) {
 
 	// Initialize agent
 	ag := agent.NewAgent(browserCtrl, aiService, securityLayer, logger)
+	out := agent.NewConsolePresenter(os.Stdout)
+	ag.SetPresenter(out)
+	// Streamed /analyze output goes through the presenter like everything else
+	if streamer, ok := aiService.(interface{ SetStreamOutput(w io.Writer) }); ok {
+		streamer.SetStreamOutput(agent.NewPresenterWriter(out))
+	}
 
-	return &TerminalInterface{
+	t := &TerminalInterface{
 		agent:       ag,
 		browserCtrl: browserCtrl,
 		logger:      logger,
 		reader:      bufio.NewReader(os.Stdin),
-	}, nil
+		out:         out,
+	}
+	ag.AddObserver(lastTaskObserver{t: t})
+	return t, nil
+}
+
+// lastTaskObserver - keeps the task that finished last for /export, whichever way it was started
+type lastTaskObserver struct {
+	agent.BaseObserver
+	t *TerminalInterface
+}
+
+func (o lastTaskObserver) OnTaskComplete(task *entities.Task, err error) {
+	o.t.lastTask = task
 }
 
-func (t *TerminalInterface) Run() error {
+// validateAIService - runs the provider's credential check when it has one
+func validateAIService(aiService interfaces.AIService) error {
+	validator, ok := aiService.(interface{ Validate(ctx context.Context) error })
+	if !ok {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), apiValidationTimeout)
+	defer cancel()
+	if err := validator.Validate(ctx); err != nil {
+		return fmt.Errorf("AI service validation failed: %w", err)
+	}
+	return nil
+}
+
+func (t *TerminalInterface) Run(ctx context.Context) error {
 	defer t.browserCtrl.Close()
 
-	fmt.Println("AI Браузер Агент")
-	fmt.Println("=================")
-	fmt.Println("Введите задачу для агента, или 'quit' для выхода")
-	fmt.Println()
+	t.out.Println(i18n.T(i18n.MsgWelcomeTitle))
+	t.out.Println("=================")
+	t.out.Println(i18n.T(i18n.MsgWelcomeHint))
+	t.out.Println(i18n.T(i18n.MsgWelcomeCommands))
+	t.out.Println()
+
+	if err := t.offerLastSession(ctx); err != nil {
+		return err
+	}
 
 	for {
-		fmt.Print("> ")
-		input, err := t.reader.ReadString('\n')
+		t.out.Print("> ")
+		input, err := t.readLine(ctx)
 		if err != nil {
+			if ctx.Err() != nil {
+				t.out.Printf("\n%s\n", i18n.T(i18n.MsgGoodbye))
+				return nil
+			}
 			return err
 		}
 
@@ -87,34 +151,238 @@ func (t *TerminalInterface) Run() error {
 		}
 
 		if input == "quit" || input == "exit" || input == "q" {
-			fmt.Println("До свидания!")
+			t.out.Println(i18n.T(i18n.MsgGoodbye))
 			return nil
 		}
 
-		// Create task
+		if input == "/export" || strings.HasPrefix(input, "/export ") {
+			t.exportLastTask(strings.TrimSpace(strings.TrimPrefix(input, "/export")))
+			continue
+		}
+
+		if input == "/frames" {
+			t.listFrames(ctx)
+			continue
+		}
+
+		if input == "/inspect" || strings.HasPrefix(input, "/inspect ") {
+			t.inspectPage(ctx, strings.TrimPrefix(input, "/inspect"))
+			continue
+		}
+
+		if input == "/console" {
+			t.showConsole(ctx)
+			continue
+		}
+
+		if input == "/analyze" || strings.HasPrefix(input, "/analyze ") {
+			focus := strings.TrimSpace(strings.TrimPrefix(input, "/analyze"))
+			if err := t.analyzePage(ctx, focus); err != nil {
+				t.out.Printf("\n%s\n\n", i18n.T(i18n.MsgAnalyzeFailed, err))
+			}
+			continue
+		}
+
+		// Create task, "open <url> then ..." starts it on that page
+		description, startURL := parseTaskInput(input)
 		task := &entities.Task{
 			ID:          fmt.Sprintf("task-%d", len(input)),
-			Description: input,
+			Description: description,
+			StartURL:    startURL,
 			Status:      entities.TaskStatusPending,
 		}
 
 		// Execute task
-		fmt.Printf("\nНачинаю выполнение задачи: %s\n\n", task.Description)
+		t.out.Printf("\n%s\n\n", i18n.T(i18n.MsgStartingTask, task.Description))
 		
-		ctx := context.Background()
 		err = t.agent.ExecuteTask(ctx, task, t.reader)
 		
 		if err != nil {
-			if task.Status == entities.TaskStatusWaiting {
+			if task.Status == entities.TaskStatusCancelled {
+				t.out.Println(i18n.T(i18n.MsgTaskCancelledExit))
+				return nil
+			} else if task.Status == entities.TaskStatusWaiting {
 				// Task is waiting for user input, continue loop
 				continue
 			} else {
-				fmt.Printf("\nЗадача не выполнена: %v\n\n", err)
+				t.out.Printf("\n%s\n\n", i18n.T(i18n.MsgTaskFailed, err))
 			}
 		} else {
-			fmt.Printf("\nЗадача выполнена\n\n")
+			t.out.Printf("\n%s\n\n", i18n.T(i18n.MsgTaskCompleted))
+		}
+	}
+}
+
+// readLine - reads a line from the terminal, returning early when ctx is cancelled
+func (t *TerminalInterface) readLine(ctx context.Context) (string, error) {
+	type readResult struct {
+		line string
+		err  error
+	}
+
+	resultCh := make(chan readResult, 1)
+	go func() {
+		line, err := t.reader.ReadString('\n')
+		resultCh <- readResult{line: line, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case result := <-resultCh:
+		return result.line, result.err
+	}
+}
+
+// offerLastSession - asks whether to reopen the pages saved on the last exit (RESTORE_LAST_URL=true)
+func (t *TerminalInterface) offerLastSession(ctx context.Context) error {
+	state, err := browser.LoadLastSession()
+	if err != nil {
+		t.logger.WithError(err).Warn("Failed to load last session")
+		return nil
+	}
+	if state == nil {
+		return nil
+	}
+
+	t.out.Print(i18n.T(i18n.MsgRestorePrompt, state.URL, len(state.Tabs)+1))
+	answer, err := t.readLine(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
 		}
+		return err
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes", "д", "да":
+	default:
+		t.out.Println()
+		return nil
+	}
+
+	if err := t.agent.RestorePages(ctx, state.Pages(), t.reader); err != nil {
+		t.out.Println(i18n.T(i18n.MsgRestoreFailed, err))
+	}
+	t.out.Println()
+	return nil
+}
+
+// analyzePage - prints AI summary of the current page without performing any actions
+func (t *TerminalInterface) analyzePage(ctx context.Context, focus string) error {
+	if focus == "" {
+		focus = i18n.T(i18n.MsgAnalyzeDefault)
+	}
+
+	pageInfo, err := t.agent.GetBrowser().ExtractPageInfo(ctx, 0)
+	if err != nil {
+		return fmt.Errorf("failed to extract page info: %w", err)
+	}
+
+	task := &entities.Task{
+		ID:          "analyze",
+		Description: focus,
+		Status:      entities.TaskStatusInProgress,
+	}
+
+	t.out.Printf("\n%s\n", i18n.T(i18n.MsgAnalyzingPage))
+	aiService := t.agent.GetAI()
+	analysis, err := aiService.AnalyzePage(ctx, pageInfo, task)
+	if err != nil {
+		return err
+	}
+
+	// Streaming clients have already printed the analysis token by token
+	if streamer, ok := aiService.(interface{ Streams() bool }); ok && streamer.Streams() {
+		t.out.Println()
+		return nil
+	}
+
+	t.out.Printf("\n%s\n\n", analysis)
+	return nil
+}
+
+// exportLastTask - saves successful steps of the last task as a replay script
+func (t *TerminalInterface) exportLastTask(path string) {
+	if t.lastTask == nil || len(t.lastTask.Actions) == 0 {
+		t.out.Printf("\n%s\n\n", i18n.T(i18n.MsgExportNoTask))
+		return
+	}
+	if path == "" {
+		path = t.lastTask.ID + ".json"
+	}
+
+	if err := agent.SaveScript(path, t.lastTask); err != nil {
+		t.out.Printf("\n%s\n\n", i18n.T(i18n.MsgExportFailed, err))
+		return
+	}
+	t.out.Printf("\n%s\n\n", i18n.T(i18n.MsgScriptSaved, path, len(t.lastTask.Actions)))
+}
+
+// listFrames - prints the frame tree of the current page, nested frames indented
+func (t *TerminalInterface) listFrames(ctx context.Context) {
+	frames, err := t.browserCtrl.ListFrames(ctx)
+	if err != nil {
+		t.out.Printf("\n%s\n\n", i18n.T(i18n.MsgFramesFailed, err))
+		return
+	}
+	if len(frames) == 0 {
+		t.out.Printf("\n%s\n\n", i18n.T(i18n.MsgFramesNone))
+		return
+	}
+
+	t.out.Printf("\n%s\n", i18n.T(i18n.MsgFramesHeader, len(frames)))
+	for _, frame := range frames {
+		line := strings.Repeat("  ", frame.Depth) + "- "
+		if frame.Name != "" {
+			line += frame.Name + ": "
+		}
+		line += frame.URL
+		if !frame.SameOrigin {
+			line += " " + i18n.T(i18n.MsgFrameCrossOrigin)
+		}
+		t.out.Println(line)
+	}
+	t.out.Println()
+}
+
+// showConsole - prints console messages and JavaScript errors of the current page
+func (t *TerminalInterface) showConsole(ctx context.Context) {
+	entries, err := t.browserCtrl.GetConsoleLogs(ctx)
+	if err != nil {
+		t.out.Printf("\n%s\n\n", i18n.T(i18n.MsgConsoleFailed, err))
+		return
+	}
+	if len(entries) == 0 {
+		t.out.Printf("\n%s\n\n", i18n.T(i18n.MsgConsoleNone))
+		return
+	}
+
+	t.out.Printf("\n%s\n", i18n.T(i18n.MsgConsoleHeader, len(entries)))
+	for _, entry := range entries {
+		line := fmt.Sprintf("%s [%s] ", entry.Time.Format("15:04:05"), entry.Level)
+		if entry.Source != "" {
+			line += entry.Source + " "
+		}
+		t.out.Println(line + entry.Message)
+	}
+	t.out.Println()
+}
+
+// Replay - runs a saved script without the AI
+func (t *TerminalInterface) Replay(ctx context.Context, path string) error {
+	defer t.browserCtrl.Close()
+
+	script, err := agent.LoadScript(path)
+	if err != nil {
+		return err
+	}
+
+	t.out.Printf("%s\n\n", i18n.T(i18n.MsgReplayStart, path, len(script.Actions)))
+	if err := t.agent.Replay(ctx, script, t.reader); err != nil {
+		return err
 	}
+	t.out.Println(i18n.T(i18n.MsgReplayDone))
+	return nil
 }
 
 func (t *TerminalInterface) Close() error {
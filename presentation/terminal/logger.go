@@ -0,0 +1,39 @@
+package terminal
+
+import (
+	"os"
+
+	"ai_automation/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// newLogger - creates logger writing to stderr, configured by
+// LOG_LEVEL (debug, info, warn, error) and LOG_FORMAT (text, json)
+func newLogger(cfg config.LogConfig) *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(os.Stderr)
+	logger.SetLevel(logrus.InfoLevel)
+	logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+	})
+
+	switch format := cfg.Format; format {
+	case "", "text":
+	case "json":
+		logger.SetFormatter(&logrus.JSONFormatter{})
+	default:
+		logger.Warnf("Unknown LOG_FORMAT %q, using text", format)
+	}
+
+	if value := cfg.Level; value != "" {
+		level, err := logrus.ParseLevel(value)
+		if err != nil {
+			logger.Warnf("Unknown LOG_LEVEL %q, using info", value)
+		} else {
+			logger.SetLevel(level)
+		}
+	}
+
+	return logger
+}
@@ -0,0 +1,68 @@
+package terminal
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// startURLPattern - "open <url> then <task>" and its Russian form "открой <url> затем|потом <task>",
+// the task part is optional
+var startURLPattern = regexp.MustCompile(`(?is)^(?:open|открой|откройте)\s+(\S+)(?:\s+(?:then|and then|затем|потом|и)\s+(.+))?$`)
+
+// parseTaskInput - splits the start page off a task line. Without the open prefix the whole line
+// is the description and startURL is empty
+func parseTaskInput(input string) (description, startURL string) {
+	input = strings.TrimSpace(input)
+	match := startURLPattern.FindStringSubmatch(input)
+	if match == nil || !looksLikeURL(match[1]) {
+		return input, ""
+	}
+
+	startURL = match[1]
+	if !strings.Contains(startURL, "://") {
+		startURL = "https://" + startURL
+	}
+	description = strings.TrimSpace(match[2])
+	if description == "" {
+		description = input
+	}
+	return description, startURL
+}
+
+// fileExtensions - file name endings that would pass for a top-level domain ("README.md")
+var fileExtensions = map[string]bool{
+	"md": true, "txt": true, "pdf": true, "csv": true, "json": true, "xml": true, "yaml": true, "yml": true,
+	"html": true, "htm": true, "js": true, "ts": true, "go": true, "py": true, "sh": true, "log": true,
+	"doc": true, "docx": true, "xls": true, "xlsx": true, "ppt": true, "pptx": true, "zip": true,
+	"png": true, "jpg": true, "jpeg": true, "gif": true, "svg": true, "exe": true,
+}
+
+// looksLikeURL - accepts URLs with a scheme and bare hosts like example.com/path. A bare host needs
+// a top-level domain of letters that is not a file extension, so "README.md" stays a file name
+func looksLikeURL(value string) bool {
+	if strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://") {
+		return true
+	}
+	host := strings.SplitN(value, "/", 2)[0]
+	host, _, _ = strings.Cut(host, ":")
+	labels := strings.Split(host, ".")
+	if len(labels) < 2 || strings.ContainsAny(host, "@,;") {
+		return false
+	}
+	for _, label := range labels {
+		if label == "" {
+			return false
+		}
+	}
+	tld := strings.ToLower(labels[len(labels)-1])
+	if len([]rune(tld)) < 2 || fileExtensions[tld] {
+		return false
+	}
+	for _, r := range tld {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
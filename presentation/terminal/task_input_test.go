@@ -0,0 +1,26 @@
+package terminal
+
+import "testing"
+
+func TestParseTaskInput(t *testing.T) {
+	tests := []struct {
+		input, description, startURL string
+	}{
+		{"open https://x.com then search for Go jobs", "search for Go jobs", "https://x.com"},
+		{"Open example.com/shop then find the cheapest phone", "find the cheapest phone", "https://example.com/shop"},
+		{"открой ya.ru затем найди погоду", "найди погоду", "https://ya.ru"},
+		{"open https://x.com", "open https://x.com", "https://x.com"},
+		{"open the settings menu then change the language", "open the settings menu then change the language", ""},
+		{"find cheap flights", "find cheap flights", ""},
+		{"open README.md then summarize it", "open README.md then summarize it", ""},
+		{"open v1.2 then list the changes", "open v1.2 then list the changes", ""},
+		{"open shop.example.рф then find the phone", "find the phone", "https://shop.example.рф"},
+		{"open localhost.dev:8080/admin then log in", "log in", "https://localhost.dev:8080/admin"},
+	}
+	for _, tt := range tests {
+		description, startURL := parseTaskInput(tt.input)
+		if description != tt.description || startURL != tt.startURL {
+			t.Errorf("parseTaskInput(%q) = %q, %q, want %q, %q", tt.input, description, startURL, tt.description, tt.startURL)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package terminal
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"ai_automation/domain/entities"
+	"ai_automation/domain/i18n"
+)
+
+// queuedTask - one entry of a task file
+type queuedTask struct {
+	Description string `json:"task"`
+	StartURL    string `json:"start_url,omitempty"`
+}
+
+// queueResult - outcome of one task, written as a JSON line to the results file
+type queueResult struct {
+	Task     string              `json:"task"`
+	StartURL string              `json:"start_url,omitempty"`
+	Status   entities.TaskStatus `json:"status"`
+	Result   string              `json:"result,omitempty"`
+	Error    string              `json:"error,omitempty"`
+	Duration float64             `json:"duration_seconds"`
+}
+
+// loadTaskQueue - reads a task file: either a JSON array of {"task", "start_url"} objects or
+// one task per line, where blank lines and # comments are skipped and "open <url> then ..."
+// sets the start page like in the interactive prompt
+func loadTaskQueue(path string) ([]queuedTask, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read task file: %w", err)
+	}
+
+	var tasks []queuedTask
+	if content := strings.TrimSpace(string(data)); strings.HasPrefix(content, "[") {
+		if err := json.Unmarshal([]byte(content), &tasks); err != nil {
+			return nil, fmt.Errorf("invalid task file %s: %w", path, err)
+		}
+		for i, task := range tasks {
+			if strings.TrimSpace(task.Description) == "" {
+				return nil, fmt.Errorf("task %d in %s has no description", i+1, path)
+			}
+		}
+	} else {
+		for _, line := range strings.Split(content, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			description, startURL := parseTaskInput(line)
+			tasks = append(tasks, queuedTask{Description: description, StartURL: startURL})
+		}
+	}
+
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("task file %s has no tasks", path)
+	}
+	return tasks, nil
+}
+
+// RunTaskQueue - executes the tasks of a task file one after another, each in its own tab.
+// A failed task does not stop the queue; every outcome is appended to outputPath as a JSON line
+// and a summary is printed at the end. Cancelling ctx stops the queue after the current task.
+// Nobody answers prompts in a queue, so an action that needs approval fails its task
+func (t *TerminalInterface) RunTaskQueue(ctx context.Context, path, outputPath string) error {
+	defer t.browserCtrl.Close()
+
+	tasks, err := loadTaskQueue(path)
+	if err != nil {
+		return err
+	}
+	output, err := os.OpenFile(outputPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open results file: %w", err)
+	}
+	defer output.Close()
+	encoder := json.NewEncoder(output)
+
+	t.out.Printf("%s\n\n", i18n.T(i18n.MsgQueueStart, path, len(tasks)))
+	completed, failed := 0, 0
+	for i, queued := range tasks {
+		if ctx.Err() != nil {
+			break
+		}
+		t.out.Printf("%s\n\n", i18n.T(i18n.MsgQueueTask, i+1, len(tasks), queued.Description))
+
+		result := t.runQueuedTask(ctx, fmt.Sprintf("queue-%d", i+1), queued)
+		if err := encoder.Encode(result); err != nil {
+			return fmt.Errorf("failed to write results file: %w", err)
+		}
+		if result.Status == entities.TaskStatusCompleted {
+			completed++
+		} else {
+			failed++
+			t.out.Printf("\n%s\n\n", i18n.T(i18n.MsgTaskFailed, result.Error))
+		}
+	}
+
+	skipped := len(tasks) - completed - failed
+	t.out.Println(i18n.T(i18n.MsgQueueSummary, completed, failed, skipped, outputPath))
+	return nil
+}
+
+// runQueuedTask - runs one task in a fresh tab, so pages of the previous task do not leak into it
+func (t *TerminalInterface) runQueuedTask(ctx context.Context, id string, queued queuedTask) queueResult {
+	task := &entities.Task{
+		ID:          id,
+		Description: queued.Description,
+		StartURL:    queued.StartURL,
+		Status:      entities.TaskStatusPending,
+	}
+	started := time.Now()
+
+	taskCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	err := t.browserCtrl.OpenNewTab(taskCtx, "about:blank")
+	if err == nil {
+		// The terminal reader is not handed over: a queue runs unattended and stray input
+		// must not approve actions
+		err = t.agent.ExecuteTask(taskCtx, task, bufio.NewReader(strings.NewReader("")))
+		if closeErr := t.browserCtrl.CloseCurrentTab(ctx); closeErr != nil {
+			t.logger.WithError(closeErr).Warn("Failed to close the task tab")
+		}
+	}
+
+	result := queueResult{
+		Task:     task.Description,
+		StartURL: task.StartURL,
+		Status:   task.Status,
+		Result:   task.Result,
+		Duration: time.Since(started).Seconds(),
+	}
+	if err != nil {
+		result.Error = err.Error()
+		// Some failures (the AI, the page) return before the agent sets a final status, and
+		// a task waiting for approval will not get it in a queue
+		switch result.Status {
+		case entities.TaskStatusPending, entities.TaskStatusInProgress, entities.TaskStatusCompleted, entities.TaskStatusWaiting:
+			result.Status = entities.TaskStatusFailed
+		}
+	}
+	return result
+}
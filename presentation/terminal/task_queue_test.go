@@ -0,0 +1,201 @@
+package terminal
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"ai_automation/application/agent"
+	"ai_automation/domain/entities"
+	"ai_automation/domain/interfaces"
+	"ai_automation/testing/mocks"
+
+	"github.com/sirupsen/logrus"
+)
+
+// failingAI - fails decisions of one task, the others get the scripted actions
+type failingAI struct {
+	*mocks.AI
+	failTask string
+}
+
+func (f *failingAI) DecideNextAction(ctx context.Context, task *entities.Task, pageInfo *entities.PageInfo, history []entities.Action) (*entities.Action, error) {
+	if task.Description == f.failTask {
+		return nil, errors.New("model unavailable")
+	}
+	return f.AI.DecideNextAction(ctx, task, pageInfo, history)
+}
+
+// newTestTerminal - terminal on mock components writing its output to w
+func newTestTerminal(browser *mocks.Browser, aiService interfaces.AIService, security *mocks.Security, w io.Writer) *TerminalInterface {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	out := agent.NewConsolePresenter(w)
+	ag := agent.NewAgent(browser, aiService, security, logger)
+	ag.SetPresenter(out)
+	ag.SetProgress(nil)
+	term := &TerminalInterface{
+		agent:       ag,
+		browserCtrl: browser,
+		logger:      logger,
+		reader:      bufio.NewReader(strings.NewReader("")),
+		out:         out,
+	}
+	ag.AddObserver(lastTaskObserver{t: term})
+	return term
+}
+
+func TestLoadTaskQueue(t *testing.T) {
+	dir := t.TempDir()
+	lines := filepath.Join(dir, "tasks.txt")
+	writeTaskFile(t, lines, "# weekly checks\nfind the weather\n\nopen example.com then read the headline\n")
+	array := filepath.Join(dir, "tasks.json")
+	writeTaskFile(t, array, `[{"task": "read the headline", "start_url": "https://example.com"}, {"task": "find the weather"}]`)
+
+	for _, path := range []string{lines, array} {
+		tasks, err := loadTaskQueue(path)
+		if err != nil {
+			t.Fatalf("loadTaskQueue(%s): %v", path, err)
+		}
+		if len(tasks) != 2 {
+			t.Fatalf("%s: tasks = %+v, want 2", path, tasks)
+		}
+		for _, task := range tasks {
+			if task.Description == "read the headline" && task.StartURL != "https://example.com" {
+				t.Errorf("%s: start URL = %q, want https://example.com", path, task.StartURL)
+			}
+		}
+	}
+
+	empty := filepath.Join(dir, "empty.txt")
+	writeTaskFile(t, empty, "# nothing yet\n")
+	if _, err := loadTaskQueue(empty); err == nil {
+		t.Error("loadTaskQueue accepted a file without tasks")
+	}
+}
+
+func TestRunTaskQueueContinuesAfterFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tasks.txt")
+	writeTaskFile(t, path, "find the weather\nbroken task\nopen example.com then read the headline\n")
+	output := filepath.Join(dir, "results.jsonl")
+
+	browser := mocks.NewBrowser()
+	aiService := &failingAI{
+		AI: mocks.NewAI(
+			&entities.Action{Type: entities.ActionComplete, Text: "sunny"},
+			&entities.Action{Type: entities.ActionComplete, Text: "Big news"},
+		),
+		failTask: "broken task",
+	}
+	term := newTestTerminal(browser, aiService, mocks.NewSecurity(), io.Discard)
+
+	if err := term.RunTaskQueue(context.Background(), path, output); err != nil {
+		t.Fatalf("RunTaskQueue: %v", err)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []queueResult
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var result queueResult
+		if err := json.Unmarshal([]byte(line), &result); err != nil {
+			t.Fatalf("result line %q: %v", line, err)
+		}
+		results = append(results, result)
+	}
+
+	want := []struct {
+		task   string
+		status entities.TaskStatus
+		result string
+	}{
+		{"find the weather", entities.TaskStatusCompleted, "sunny"},
+		{"broken task", entities.TaskStatusFailed, ""},
+		{"read the headline", entities.TaskStatusCompleted, "Big news"},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("results = %+v, want %d lines", results, len(want))
+	}
+	for i, w := range want {
+		if results[i].Task != w.task || results[i].Status != w.status || results[i].Result != w.result {
+			t.Errorf("result %d = %+v, want %s %s %q", i+1, results[i], w.task, w.status, w.result)
+		}
+	}
+	if results[1].Error == "" || results[2].StartURL != "https://example.com" {
+		t.Errorf("failed task error %q, start URL %q", results[1].Error, results[2].StartURL)
+	}
+
+	if term.lastTask == nil || term.lastTask.Description != "read the headline" {
+		t.Errorf("last task = %+v, want the third one", term.lastTask)
+	}
+
+	// Every task gets its own tab, closed when the task ends
+	if opened, closed := browser.CallCount("OpenNewTab"), browser.CallCount("CloseCurrentTab"); opened != 3 || closed != 3 {
+		t.Errorf("opened %d and closed %d tabs, want 3 each", opened, closed)
+	}
+}
+
+func TestRunTaskQueueFailsTaskNeedingApproval(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tasks.txt")
+	writeTaskFile(t, path, "buy the cheapest ticket\nfind the weather\n")
+	output := filepath.Join(dir, "results.jsonl")
+
+	browser := mocks.NewBrowser()
+	security := mocks.NewSecurity()
+	security.Approval[entities.ActionClick] = true
+	aiService := mocks.NewAI(
+		&entities.Action{Type: entities.ActionClick, Selector: "#buy"},
+		&entities.Action{Type: entities.ActionComplete, Text: "sunny"},
+	)
+	term := newTestTerminal(browser, aiService, security, io.Discard)
+	// Whatever is typed in the terminal, queued tasks do not read it
+	term.reader = bufio.NewReader(strings.NewReader("yes\nyes\n"))
+
+	if err := term.RunTaskQueue(context.Background(), path, output); err != nil {
+		t.Fatalf("RunTaskQueue: %v", err)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("results = %q, want 2 lines", lines)
+	}
+	var blocked, next queueResult
+	if err := json.Unmarshal([]byte(lines[0]), &blocked); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &next); err != nil {
+		t.Fatal(err)
+	}
+
+	if blocked.Status != entities.TaskStatusFailed || blocked.Error == "" {
+		t.Errorf("task needing approval = %+v, want failed with an error", blocked)
+	}
+	if browser.CallCount("Click") != 0 {
+		t.Error("action needing approval ran in a queue")
+	}
+	if next.Status != entities.TaskStatusCompleted || next.Result != "sunny" {
+		t.Errorf("next task = %+v, want completed", next)
+	}
+}
+
+func writeTaskFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
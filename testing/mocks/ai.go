@@ -0,0 +1,81 @@
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"ai_automation/domain/entities"
+	"ai_automation/domain/interfaces"
+)
+
+var _ interfaces.AIService = (*AI)(nil)
+
+// AI - scripted AIService. DecideNextAction returns Actions one by one and
+// a complete action once they run out
+type AI struct {
+	Recorder
+
+	mu      sync.Mutex
+	Actions []*entities.Action
+	next    int
+
+	Analysis      string
+	ExtractedData string
+	Plan          []string
+	// Rejections are reasons VerifyCompletion rejects completions with, one per call, later calls confirm
+	Rejections []string
+}
+
+// NewAI - creates an AI mock that will decide the given actions in order
+func NewAI(actions ...*entities.Action) *AI {
+	return &AI{Recorder: newRecorder(), Actions: actions}
+}
+
+func (m *AI) DecideNextAction(ctx context.Context, task *entities.Task, pageInfo *entities.PageInfo, history []entities.Action) (*entities.Action, error) {
+	if err := m.record("DecideNextAction", task, pageInfo, history); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.next >= len(m.Actions) {
+		return &entities.Action{Type: entities.ActionComplete, Description: "scripted actions finished"}, nil
+	}
+	action := *m.Actions[m.next]
+	m.next++
+	return &action, nil
+}
+
+func (m *AI) AnalyzePage(ctx context.Context, pageInfo *entities.PageInfo, task *entities.Task) (string, error) {
+	return m.Analysis, m.record("AnalyzePage", pageInfo, task)
+}
+
+func (m *AI) ExtractData(ctx context.Context, pageInfo *entities.PageInfo, schema string) (string, error) {
+	return m.ExtractedData, m.record("ExtractData", pageInfo, schema)
+}
+
+func (m *AI) PlanTask(ctx context.Context, task *entities.Task) ([]string, error) {
+	return m.Plan, m.record("PlanTask", task)
+}
+
+func (m *AI) VerifyCompletion(ctx context.Context, task *entities.Task, pageInfo *entities.PageInfo, summary string) (bool, string, error) {
+	if err := m.record("VerifyCompletion", task, pageInfo, summary); err != nil {
+		return false, "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.Rejections) == 0 {
+		return true, "", nil
+	}
+	reason := m.Rejections[0]
+	m.Rejections = m.Rejections[1:]
+	return false, reason, nil
+}
+
+// Remaining - returns how many scripted actions have not been decided yet
+func (m *AI) Remaining() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.Actions) - m.next
+}
@@ -0,0 +1,506 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"ai_automation/domain/entities"
+	"ai_automation/domain/interfaces"
+)
+
+var _ interfaces.BrowserController = (*Browser)(nil)
+
+// Browser - programmable BrowserController. Navigate updates URL, everything else
+// returns the values stored in the fields below. Fields the mock itself changes
+// (URL, Tabs, tab URLs, Clipboard, Selection, ElementText, Checked, Toggles, Selected, ConsentBanner, DialogOpen, DialogAccepted) are guarded by a mutex, set them
+// before the agent starts or read them after it returns
+type Browser struct {
+	Recorder
+
+	mu    sync.Mutex
+	URL   string
+	Title string
+	// PageInfo is returned by ExtractPageInfo; nil returns an empty page at URL
+	PageInfo *entities.PageInfo
+	// PageInfoFunc overrides PageInfo, e.g. to return different pages per call
+	PageInfoFunc func(page int) *entities.PageInfo
+
+	Screenshot  []byte
+	Clipboard   string
+	Selection   string
+	ElementText map[string]string
+	// Checked holds checkbox states, SetChecked counts in Toggles the calls that changed one
+	Checked map[string]bool
+	Toggles int
+	// SelectOptions maps a select list to its options, SelectOption records the choice in Selected
+	SelectOptions map[string][]string
+	Selected      map[string][]string
+	Attributes    map[string]string
+	Visible       bool
+	Enabled       bool
+	Exists        bool
+	Count         int
+	Loads         int
+	Elements      []entities.PageElement
+	// NearText maps an anchor text to the element FindElementNearText returns, others are not found
+	NearText     map[string]entities.PageElement
+	ScriptResult string
+	DownloadPath string
+	BoundingBox  *entities.BoundingBox
+	// ClosestSelectors maps a selector to the one ClickClosest substitutes for it
+	ClosestSelectors map[string]string
+	// Missing lists selectors that match nothing: element actions on them fail
+	// and ElementExists reports false
+	Missing map[string]bool
+
+	// Tabs is the number of open tabs, OpenNewTab and CloseCurrentTab change it
+	Tabs int
+	// tabURLs holds URLs opened with OpenNewTab, SwitchToTab finds them
+	tabURLs []string
+
+	// Frames is returned by ListFrames
+	Frames []entities.FrameInfo
+	// ConsoleLogs is returned by GetConsoleLogs
+	ConsoleLogs []entities.LogEntry
+
+	// ConsentBanner is the accept button label of a cookie banner on the page,
+	// DismissConsentBanner clears it
+	ConsentBanner string
+
+	// DialogMessage is reported by GetOpenDialog while DialogOpen is true
+	DialogMessage string
+	DialogOpen    bool
+	// DialogAccepted records the last HandleDialog answer
+	DialogAccepted bool
+}
+
+// NewBrowser - creates a browser mock on about:blank
+func NewBrowser() *Browser {
+	return &Browser{
+		Recorder:      newRecorder(),
+		URL:           "about:blank",
+		Tabs:          1,
+		ElementText:   make(map[string]string),
+		Checked:       make(map[string]bool),
+		SelectOptions: make(map[string][]string),
+		Selected:      make(map[string][]string),
+		Attributes:    make(map[string]string),
+		Missing:       make(map[string]bool),
+		Visible:       true,
+		Enabled:       true,
+		Exists:        true,
+	}
+}
+
+func (b *Browser) Navigate(ctx context.Context, url string) error {
+	if err := b.record("Navigate", url); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	b.URL = url
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *Browser) Click(ctx context.Context, selector string) error {
+	if err := b.record("Click", selector); err != nil {
+		return err
+	}
+	return b.missing(selector)
+}
+
+func (b *Browser) ClickClosest(ctx context.Context, selector string) (string, error) {
+	if err := b.record("ClickClosest", selector); err != nil {
+		return "", err
+	}
+	if clicked, ok := b.ClosestSelectors[selector]; ok {
+		return clicked, nil
+	}
+	return selector, b.missing(selector)
+}
+
+func (b *Browser) SetDate(ctx context.Context, selector string, date string) error {
+	if err := b.record("SetDate", selector, date); err != nil {
+		return err
+	}
+	if err := b.missing(selector); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.ElementText[selector] = date
+	return nil
+}
+
+func (b *Browser) SetChecked(ctx context.Context, selector string, checked bool) error {
+	if err := b.record("SetChecked", selector, checked); err != nil {
+		return err
+	}
+	if err := b.missing(selector); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.Checked[selector] != checked {
+		b.Toggles++
+	}
+	b.Checked[selector] = checked
+	return nil
+}
+
+func (b *Browser) SelectOption(ctx context.Context, selector string, values []string) error {
+	if err := b.record("SelectOption", selector, values); err != nil {
+		return err
+	}
+	if err := b.missing(selector); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if options, ok := b.SelectOptions[selector]; ok {
+		for _, value := range values {
+			if !containsString(options, value) {
+				return fmt.Errorf("option %q not found in %s", value, selector)
+			}
+		}
+	}
+	b.Selected[selector] = append([]string(nil), values...)
+	return nil
+}
+
+func (b *Browser) GetSelectOptions(ctx context.Context, selector string) ([]string, error) {
+	if err := b.record("GetSelectOptions", selector); err != nil {
+		return nil, err
+	}
+	if err := b.missing(selector); err != nil {
+		return nil, err
+	}
+	options, ok := b.SelectOptions[selector]
+	if !ok {
+		return nil, fmt.Errorf("element %s is not a select list", selector)
+	}
+	return options, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Browser) TypeText(ctx context.Context, selector string, text string) error {
+	if err := b.record("TypeText", selector, text); err != nil {
+		return err
+	}
+	if err := b.missing(selector); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.ElementText[selector] = text
+	return nil
+}
+
+func (b *Browser) AppendText(ctx context.Context, selector string, text string) error {
+	if err := b.record("AppendText", selector, text); err != nil {
+		return err
+	}
+	if err := b.missing(selector); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.ElementText[selector] += text
+	return nil
+}
+
+func (b *Browser) ExtractPageInfo(ctx context.Context, page int) (*entities.PageInfo, error) {
+	if err := b.record("ExtractPageInfo", page); err != nil {
+		return nil, err
+	}
+	if b.PageInfoFunc != nil {
+		return b.PageInfoFunc(page), nil
+	}
+	if b.PageInfo != nil {
+		info := *b.PageInfo
+		return &info, nil
+	}
+	return &entities.PageInfo{URL: b.currentURL(), Title: b.Title, Page: page}, nil
+}
+
+func (b *Browser) ExtractPageInfoWithin(ctx context.Context, rootSelector string, page int) (*entities.PageInfo, error) {
+	info, err := b.ExtractPageInfo(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	b.record("ExtractPageInfoWithin", rootSelector, page)
+	info.Scope = rootSelector
+	return info, nil
+}
+
+func (b *Browser) Wait(ctx context.Context, condition string, timeout int) error {
+	return b.record("Wait", condition, timeout)
+}
+
+func (b *Browser) Scroll(ctx context.Context, direction string, amount int) error {
+	return b.record("Scroll", direction, amount)
+}
+
+func (b *Browser) LoadAll(ctx context.Context, maxScrolls int) (int, error) {
+	return b.Loads, b.record("LoadAll", maxScrolls)
+}
+
+func (b *Browser) GetCurrentURL(ctx context.Context) (string, error) {
+	return b.currentURL(), b.record("GetCurrentURL")
+}
+
+func (b *Browser) GetPageTitle(ctx context.Context) (string, error) {
+	return b.Title, b.record("GetPageTitle")
+}
+
+func (b *Browser) TakeScreenshot(ctx context.Context) ([]byte, error) {
+	return b.Screenshot, b.record("TakeScreenshot")
+}
+
+func (b *Browser) SavePDF(ctx context.Context, path string) error {
+	return b.record("SavePDF", path)
+}
+
+func (b *Browser) Close() error {
+	return b.record("Close")
+}
+
+func (b *Browser) IsElementVisible(ctx context.Context, selector string) (bool, error) {
+	return b.Visible, b.record("IsElementVisible", selector)
+}
+
+func (b *Browser) IsElementEnabled(ctx context.Context, selector string) (bool, error) {
+	return b.Enabled, b.record("IsElementEnabled", selector)
+}
+
+func (b *Browser) ListFrames(ctx context.Context) ([]entities.FrameInfo, error) {
+	return b.Frames, b.record("ListFrames")
+}
+
+func (b *Browser) GetConsoleLogs(ctx context.Context) ([]entities.LogEntry, error) {
+	return b.ConsoleLogs, b.record("GetConsoleLogs")
+}
+
+func (b *Browser) DismissConsentBanner(ctx context.Context) (string, error) {
+	if err := b.record("DismissConsentBanner"); err != nil {
+		return "", err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	clicked := b.ConsentBanner
+	b.ConsentBanner = ""
+	return clicked, nil
+}
+
+func (b *Browser) FindElementsByText(ctx context.Context, text string) ([]entities.PageElement, error) {
+	return b.Elements, b.record("FindElementsByText", text)
+}
+
+func (b *Browser) FindElementNearText(ctx context.Context, anchorText string, role string) (*entities.PageElement, error) {
+	if err := b.record("FindElementNearText", anchorText, role); err != nil {
+		return nil, err
+	}
+	element, ok := b.NearText[anchorText]
+	if !ok {
+		return nil, &interfaces.BrowserError{
+			Kind:    interfaces.ErrElementNotFound,
+			Message: fmt.Sprintf("no %s found near %q", role, anchorText),
+		}
+	}
+	return &element, nil
+}
+
+func (b *Browser) FindElementsByRole(ctx context.Context, role string, name string) ([]entities.PageElement, error) {
+	return b.Elements, b.record("FindElementsByRole", role, name)
+}
+
+func (b *Browser) ReadClipboard(ctx context.Context) (string, error) {
+	err := b.record("ReadClipboard")
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.Clipboard, err
+}
+
+func (b *Browser) WriteClipboard(ctx context.Context, text string) error {
+	if err := b.record("WriteClipboard", text); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	b.Clipboard = text
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *Browser) SelectText(ctx context.Context, selector string) error {
+	if err := b.record("SelectText", selector); err != nil {
+		return err
+	}
+	if err := b.missing(selector); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	b.Selection = b.ElementText[selector]
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *Browser) GetSelectedText(ctx context.Context) (string, error) {
+	err := b.record("GetSelectedText")
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.Selection, err
+}
+
+func (b *Browser) GetAttribute(ctx context.Context, selector string, attr string) (string, error) {
+	return b.Attributes[selector+"@"+attr], b.record("GetAttribute", selector, attr)
+}
+
+func (b *Browser) GetElementText(ctx context.Context, selector string) (string, error) {
+	b.mu.Lock()
+	text := b.ElementText[selector]
+	b.mu.Unlock()
+	return text, b.record("GetElementText", selector)
+}
+
+func (b *Browser) RightClick(ctx context.Context, selector string) error {
+	if err := b.record("RightClick", selector); err != nil {
+		return err
+	}
+	return b.missing(selector)
+}
+
+func (b *Browser) ElementExists(ctx context.Context, selector string) (bool, error) {
+	return b.Exists && !b.Missing[selector], b.record("ElementExists", selector)
+}
+
+func (b *Browser) CountElements(ctx context.Context, selector string) (int, error) {
+	return b.Count, b.record("CountElements", selector)
+}
+
+func (b *Browser) DownloadFile(ctx context.Context, selector string) (string, error) {
+	return b.DownloadPath, b.record("DownloadFile", selector)
+}
+
+func (b *Browser) ExecuteScript(ctx context.Context, script string) (string, error) {
+	return b.ScriptResult, b.record("ExecuteScript", script)
+}
+
+func (b *Browser) ClickAt(ctx context.Context, x, y int) error {
+	return b.record("ClickAt", x, y)
+}
+
+func (b *Browser) GetBoundingBox(ctx context.Context, selector string) (*entities.BoundingBox, error) {
+	return b.BoundingBox, b.record("GetBoundingBox", selector)
+}
+
+func (b *Browser) WaitForURLChange(ctx context.Context, previousURL string, timeout time.Duration) (string, error) {
+	return b.currentURL(), b.record("WaitForURLChange", previousURL, timeout)
+}
+
+func (b *Browser) WaitForNavigation(ctx context.Context, timeout time.Duration) error {
+	return b.record("WaitForNavigation", timeout)
+}
+
+func (b *Browser) WaitForTextContains(ctx context.Context, selector string, text string, timeout time.Duration) error {
+	return b.record("WaitForTextContains", selector, text, timeout)
+}
+
+func (b *Browser) WaitForElementStable(ctx context.Context, selector string, timeout time.Duration) error {
+	return b.record("WaitForElementStable", selector, timeout)
+}
+
+func (b *Browser) GetOpenDialog(ctx context.Context) (string, bool) {
+	b.record("GetOpenDialog")
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.DialogMessage, b.DialogOpen
+}
+
+func (b *Browser) OpenNewTab(ctx context.Context, url string) error {
+	if err := b.record("OpenNewTab", url); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	b.Tabs++
+	b.URL = url
+	b.tabURLs = append(b.tabURLs, url)
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *Browser) SwitchToTab(ctx context.Context, url string) (bool, error) {
+	if err := b.record("SwitchToTab", url); err != nil {
+		return false, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.URL == url {
+		return true, nil
+	}
+	for _, tabURL := range b.tabURLs {
+		if tabURL == url {
+			b.URL = url
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (b *Browser) BringToFront(ctx context.Context) error {
+	return b.record("BringToFront")
+}
+
+func (b *Browser) SwitchToPreviousTab(ctx context.Context) error {
+	return b.record("SwitchToPreviousTab")
+}
+
+func (b *Browser) CloseCurrentTab(ctx context.Context) error {
+	if err := b.record("CloseCurrentTab"); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	if b.Tabs > 1 {
+		b.Tabs--
+	}
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *Browser) HandleDialog(ctx context.Context, accept bool) error {
+	if err := b.record("HandleDialog", accept); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	b.DialogOpen = false
+	b.DialogAccepted = accept
+	b.mu.Unlock()
+	return nil
+}
+
+// missing - returns the element not found error for selectors listed in Missing
+func (b *Browser) missing(selector string) error {
+	if b.Missing[selector] {
+		return &interfaces.BrowserError{
+			Kind:    interfaces.ErrElementNotFound,
+			Message: fmt.Sprintf("element not found with selector: %s", selector),
+		}
+	}
+	return nil
+}
+
+// currentURL - reads URL under the lock, Navigate may change it concurrently
+func (b *Browser) currentURL() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.URL
+}
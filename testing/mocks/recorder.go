@@ -0,0 +1,72 @@
+// Package mocks provides in-memory implementations of the domain interfaces
+// (BrowserController, AIService, SecurityLayer) for exercising the agent without
+// a real browser or API key. Return values are set through exported fields,
+// failures are injected per method through Errors, and every call is recorded.
+//
+//	browser := mocks.NewBrowser()
+//	browser.Errors["Click"] = errors.New("element not found")
+//	ai := mocks.NewAI(&entities.Action{Type: entities.ActionClick, Selector: "#buy"})
+//	ag := agent.NewAgent(browser, ai, mocks.NewSecurity(), logger)
+//	...
+//	if browser.CallCount("Click") != 1 { ... }
+package mocks
+
+import "sync"
+
+// Call - a recorded method call
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Recorder - records calls and returns injected errors, safe for concurrent use
+type Recorder struct {
+	mu    sync.Mutex
+	calls []Call
+	// Errors maps a method name to the error it returns
+	Errors map[string]error
+}
+
+func newRecorder() Recorder {
+	return Recorder{Errors: make(map[string]error)}
+}
+
+// record - stores the call and returns the error injected for the method
+func (r *Recorder) record(method string, args ...interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+	return r.Errors[method]
+}
+
+// Calls - returns a copy of all recorded calls in order
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// CallsTo - returns recorded calls of one method in order
+func (r *Recorder) CallsTo(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var calls []Call
+	for _, call := range r.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// CallCount - returns how many times a method was called
+func (r *Recorder) CallCount(method string) int {
+	return len(r.CallsTo(method))
+}
+
+// Reset - forgets recorded calls, injected errors are kept
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
@@ -0,0 +1,54 @@
+package mocks
+
+import (
+	"context"
+
+	"ai_automation/domain/entities"
+	"ai_automation/domain/interfaces"
+)
+
+var _ interfaces.SecurityLayer = (*Security)(nil)
+
+// Security - SecurityLayer with per-action-type answers; unlisted actions need no approval,
+// are not destructive and have low risk
+type Security struct {
+	Recorder
+
+	Approval map[entities.ActionType]bool
+	// SensitiveURLs are pages where every action needs approval, like SENSITIVE_DOMAINS
+	SensitiveURLs map[string]bool
+	Destructive   map[entities.ActionType]bool
+	RiskLevels    map[entities.ActionType]string
+}
+
+// NewSecurity - creates a security mock that approves everything silently
+func NewSecurity() *Security {
+	return &Security{
+		Recorder:      newRecorder(),
+		Approval:      make(map[entities.ActionType]bool),
+		SensitiveURLs: make(map[string]bool),
+		Destructive:   make(map[entities.ActionType]bool),
+		RiskLevels:    make(map[entities.ActionType]string),
+	}
+}
+
+func (s *Security) RequiresApproval(ctx context.Context, action *entities.Action, pageInfo *entities.PageInfo) bool {
+	s.record("RequiresApproval", action, pageInfo)
+	if pageInfo != nil && s.SensitiveURLs[pageInfo.URL] {
+		return true
+	}
+	return s.Approval[action.Type]
+}
+
+func (s *Security) IsDestructiveAction(ctx context.Context, action *entities.Action) bool {
+	s.record("IsDestructiveAction", action)
+	return s.Destructive[action.Type]
+}
+
+func (s *Security) GetActionRiskLevel(ctx context.Context, action *entities.Action) string {
+	s.record("GetActionRiskLevel", action)
+	if level, ok := s.RiskLevels[action.Type]; ok {
+		return level
+	}
+	return "low"
+}